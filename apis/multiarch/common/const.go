@@ -9,4 +9,12 @@ const (
 	NodeAffinityScoringPluginName Plugin = iota
 	// ENoExecPlugin checks the ENoExecEvent resources.
 	ExecFormatErrorMonitorPluginName
+	// NodeInventoryPluginName restricts the node affinity to the architectures available in the cluster.
+	NodeInventoryPluginName
+	// ArchitectureTolerationsPluginName adds the tolerations for the taints of the nodes dedicated to an architecture.
+	ArchitectureTolerationsPluginName
+	// EmulationFallbackPluginName sends the pods with no common architecture to the nodes able to emulate it.
+	EmulationFallbackPluginName
+	// ArchitectureSpreadPluginName spreads the replicas of multi-architecture workloads across the architectures.
+	ArchitectureSpreadPluginName
 )
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ArchitectureSpreadPluginName is the name of the ArchitectureSpread plugin.
+	ArchitectureSpreadPluginName = "ArchitectureSpread"
+)
+
+// ArchitectureSpread spreads the replicas of the workloads running on more than one architecture across the
+// architectures, via a topologySpreadConstraint on the kubernetes.io/arch label.
+// Since the topologySpreadConstraints of a pod cannot be changed after its creation, the constraint is added by the
+// webhook at admission time, for the pods whose owner is already known to run on more than one architecture.
+// The pods that already have a topologySpreadConstraint on the kubernetes.io/arch label are not modified.
+type ArchitectureSpread struct {
+	BasePlugin `json:",inline"`
+
+	// MaxSkew is the maximum difference between the number of matching pods on any two architectures.
+	// Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// WhenUnsatisfiable indicates how to deal with a pod if it doesn't satisfy the spread constraint.
+	// Defaults to ScheduleAnyway.
+	// +optional
+	// +kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	// +kubebuilder:default=ScheduleAnyway
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+// TopologySpreadConstraint returns the topologySpreadConstraint on the given topology key, for the pods matching
+// the given label selector.
+func (a *ArchitectureSpread) TopologySpreadConstraint(topologyKey string,
+	labelSelector *metav1.LabelSelector) corev1.TopologySpreadConstraint {
+	maxSkew := a.MaxSkew
+	if maxSkew < 1 {
+		maxSkew = 1
+	}
+	whenUnsatisfiable := a.WhenUnsatisfiable
+	if whenUnsatisfiable == "" {
+		whenUnsatisfiable = corev1.ScheduleAnyway
+	}
+	return corev1.TopologySpreadConstraint{
+		MaxSkew:           maxSkew,
+		TopologyKey:       topologyKey,
+		WhenUnsatisfiable: whenUnsatisfiable,
+		LabelSelector:     labelSelector,
+	}
+}
+
+func (a *ArchitectureSpread) Name() string {
+	return ArchitectureSpreadPluginName
+}
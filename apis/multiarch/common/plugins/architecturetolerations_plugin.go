@@ -0,0 +1,121 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ArchitectureTolerationsPluginName is the name of the ArchitectureTolerations plugin.
+	ArchitectureTolerationsPluginName = "ArchitectureTolerations"
+)
+
+// ArchitectureTolerations maps the architectures to the taints of the nodes dedicated to them.
+// When the images of a pod support an architecture, the pod placement controller adds to the pod the tolerations
+// for the taints of that architecture. The tolerations set by the users are never removed or modified.
+type ArchitectureTolerations struct {
+	BasePlugin `json:",inline"`
+
+	// Platforms is a required field and must contain at least one entry.
+	// +kubebuilder:validation:MinItems=1
+	Platforms []ArchitectureTaintsTerm `json:"platforms"`
+}
+
+// ArchitectureTaintsTerm defines the taints of the nodes dedicated to an architecture.
+type ArchitectureTaintsTerm struct {
+	// Architecture is the architecture of the tainted nodes.
+	// +kubebuilder:validation:Enum=arm64;amd64;ppc64le;s390x
+	Architecture string `json:"architecture"`
+
+	// Taints is the list of the taints set on the nodes of the given architecture.
+	// +kubebuilder:validation:MinItems=1
+	Taints []ArchitectureTaint `json:"taints"`
+}
+
+// ArchitectureTaint is a taint set on the nodes dedicated to an architecture.
+type ArchitectureTaint struct {
+	// Key is the taint key.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Value is the taint value. When empty, the toleration matches any value of the taint key.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Effect is the taint effect. When empty, the toleration matches all the taint effects.
+	// +kubebuilder:validation:Enum=NoSchedule;PreferNoSchedule;NoExecute
+	// +optional
+	Effect corev1.TaintEffect `json:"effect,omitempty"`
+}
+
+// Toleration returns the toleration for the taint.
+func (t *ArchitectureTaint) Toleration() corev1.Toleration {
+	if t.Value == "" {
+		return corev1.Toleration{
+			Key:      t.Key,
+			Operator: corev1.TolerationOpExists,
+			Effect:   t.Effect,
+		}
+	}
+	return corev1.Toleration{
+		Key:      t.Key,
+		Operator: corev1.TolerationOpEqual,
+		Value:    t.Value,
+		Effect:   t.Effect,
+	}
+}
+
+// ValidateArchitecturesSet verifies that each architecture is listed at most once.
+func (a *ArchitectureTolerations) ValidateArchitecturesSet() (bool, error) {
+	seen := make(map[string]struct{})
+	for _, term := range a.Platforms {
+		if _, exists := seen[term.Architecture]; exists {
+			return false, fmt.Errorf("duplicate architecture %q found in architectureTolerations.platforms", term.Architecture)
+		}
+		seen[term.Architecture] = struct{}{}
+	}
+	return true, nil
+}
+
+// Validate implements IValidatingPlugin.
+func (a *ArchitectureTolerations) Validate() error {
+	_, err := a.ValidateArchitecturesSet()
+	return err
+}
+
+// TolerationsFor returns the tolerations for the taints of the nodes dedicated to the given architectures.
+func (a *ArchitectureTolerations) TolerationsFor(architectures []string) []corev1.Toleration {
+	tolerations := []corev1.Toleration{}
+	for _, architecture := range architectures {
+		for _, term := range a.Platforms {
+			if term.Architecture != architecture {
+				continue
+			}
+			for i := range term.Taints {
+				tolerations = append(tolerations, term.Taints[i].Toleration())
+			}
+		}
+	}
+	return tolerations
+}
+
+func (a *ArchitectureTolerations) Name() string {
+	return ArchitectureTolerationsPluginName
+}
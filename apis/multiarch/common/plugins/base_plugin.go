@@ -16,7 +16,13 @@ limitations under the License.
 
 package plugins
 
-import "github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+)
 
 // +k8s:deepcopy-gen=package
 
@@ -24,6 +30,8 @@ import "github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
 // +kubebuilder:object:generate=true
 type LocalPlugins struct {
 	NodeAffinityScoring *NodeAffinityScoring `json:"nodeAffinityScoring,omitempty"`
+
+	ArchitectureSpread *ArchitectureSpread `json:"architectureSpread,omitempty"`
 }
 
 // localPluginChecks is a map that associates a plugin name with a function that can
@@ -32,6 +40,9 @@ var localPluginChecks = map[common.Plugin]func(lp *LocalPlugins) bool{
 	common.NodeAffinityScoringPluginName: func(lp *LocalPlugins) bool {
 		return lp.NodeAffinityScoring != nil && lp.NodeAffinityScoring.IsEnabled()
 	},
+	common.ArchitectureSpreadPluginName: func(lp *LocalPlugins) bool {
+		return lp.ArchitectureSpread != nil && lp.ArchitectureSpread.IsEnabled()
+	},
 }
 
 // PluginEnabled provides a generic and safe way to check if a specific plugin is enabled.
@@ -49,26 +60,82 @@ type Plugins struct {
 	NodeAffinityScoring *NodeAffinityScoring `json:"nodeAffinityScoring,omitempty"`
 
 	ExecFormatErrorMonitor *ExecFormatErrorMonitor `json:"execFormatErrorMonitor,omitempty"`
+
+	NodeInventory *NodeInventory `json:"nodeInventory,omitempty"`
+
+	ArchitectureTolerations *ArchitectureTolerations `json:"architectureTolerations,omitempty"`
+
+	EmulationFallback *EmulationFallback `json:"emulationFallback,omitempty"`
 }
 
-// pluginChecks is a map that associates a plugin name with a function that can
-// safely check if that specific plugin is enabled on a Plugins struct.
-var pluginChecks = map[common.Plugin]func(p *Plugins) bool{
-	common.NodeAffinityScoringPluginName: func(p *Plugins) bool {
-		return p.NodeAffinityScoring != nil && p.NodeAffinityScoring.IsEnabled()
+// pluginFields is a map that associates a plugin name with a function returning the configuration of that plugin
+// in a Plugins struct, or nil when it is not set. It is the single list of the plugins of a Plugins struct.
+var pluginFields = map[common.Plugin]func(p *Plugins) IBasePlugin{
+	common.NodeAffinityScoringPluginName: func(p *Plugins) IBasePlugin {
+		return basePluginOrNil(p.NodeAffinityScoring)
 	},
-	common.ExecFormatErrorMonitorPluginName: func(p *Plugins) bool {
-		return p.ExecFormatErrorMonitor != nil && p.ExecFormatErrorMonitor.IsEnabled()
+	common.ExecFormatErrorMonitorPluginName: func(p *Plugins) IBasePlugin {
+		return basePluginOrNil(p.ExecFormatErrorMonitor)
 	},
+	common.NodeInventoryPluginName: func(p *Plugins) IBasePlugin {
+		return basePluginOrNil(p.NodeInventory)
+	},
+	common.ArchitectureTolerationsPluginName: func(p *Plugins) IBasePlugin {
+		return basePluginOrNil(p.ArchitectureTolerations)
+	},
+	common.EmulationFallbackPluginName: func(p *Plugins) IBasePlugin {
+		return basePluginOrNil(p.EmulationFallback)
+	},
+}
+
+// basePluginOrNil returns nil for a nil plugin pointer, to not wrap it in a non-nil IBasePlugin interface.
+func basePluginOrNil[T any, P interface {
+	*T
+	IBasePlugin
+}](plugin P) IBasePlugin {
+	if plugin == nil {
+		return nil
+	}
+	return plugin
+}
+
+// All returns the plugins configured in the Plugins struct, ordered by plugin name.
+func (p *Plugins) All() []IBasePlugin {
+	all := []IBasePlugin{}
+	if p == nil {
+		return all
+	}
+	for _, name := range slices.Sorted(maps.Keys(pluginFields)) {
+		if plugin := pluginFields[name](p); plugin != nil {
+			all = append(all, plugin)
+		}
+	}
+	return all
+}
+
+// Validate validates the configuration of the plugins implementing IValidatingPlugin.
+func (p *Plugins) Validate() error {
+	for _, plugin := range p.All() {
+		validatingPlugin, ok := plugin.(IValidatingPlugin)
+		if !ok {
+			continue
+		}
+		if err := validatingPlugin.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration of the %s plugin: %w", plugin.Name(), err)
+		}
+	}
+	return nil
 }
 
 // PluginEnabled provides a generic and safe way to check if a specific plugin is enabled.
 // It handles the case where the Plugins struct itself is nil.
 func (p *Plugins) PluginEnabled(plugin common.Plugin) bool {
-	if checkFunc, found := pluginChecks[plugin]; found {
-		return checkFunc(p)
+	field, found := pluginFields[plugin]
+	if !found || p == nil {
+		return false
 	}
-	return false
+	configuration := field(p)
+	return configuration != nil && configuration.IsEnabled()
 }
 
 // IBasePlugin defines a basic interface for plugins.
@@ -80,6 +147,14 @@ type IBasePlugin interface {
 	Name() string
 }
 
+// IValidatingPlugin is implemented by the plugins whose configuration needs validation beyond the OpenAPI schema.
+// +k8s:deepcopy-gen=false
+type IValidatingPlugin interface {
+	IBasePlugin
+	// Validate returns an error if the configuration of the plugin is not valid.
+	Validate() error
+}
+
 // BasePlugin defines basic structure of a plugin
 type BasePlugin struct {
 	// Enabled indicates whether the plugin is enabled.
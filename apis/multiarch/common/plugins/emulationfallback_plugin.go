@@ -0,0 +1,79 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// EmulationFallbackPluginName is the name of the EmulationFallback plugin.
+	EmulationFallbackPluginName = "EmulationFallback"
+	// DefaultEmulationFallbackInspectionTimeout is the inspection timeout used when .inspectionTimeout is not set.
+	DefaultEmulationFallbackInspectionTimeout = 2 * time.Second
+	// MaxEmulationFallbackInspectionTimeout caps the inspection timeout, to answer before the webhook times out.
+	MaxEmulationFallbackInspectionTimeout = 5 * time.Second
+)
+
+// EmulationFallback sends the pods whose images have no common architecture to the nodes able to emulate the
+// missing architectures, for example via qemu-user binfmt handlers.
+// Since the runtimeClassName of a pod cannot be changed after its creation, the decision is taken by the webhook at
+// admission time, for the pods in the namespaces labeled with multiarch.openshift.io/emulation-fallback=enabled.
+// The nodes able to emulate an architecture are expected to be labeled with multiarch.openshift.io/emulates-<arch>.
+type EmulationFallback struct {
+	BasePlugin `json:",inline"`
+
+	// RuntimeClassName is the name of the RuntimeClass set in the pods running under emulation.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	RuntimeClassName string `json:"runtimeClassName"`
+
+	// InspectionTimeout bounds the time the webhook spends inspecting the images of a pod at admission time.
+	// When the inspection does not complete in time, the pod is processed as usual by the pod placement controller.
+	// Defaults to 2s. Values greater than 5s are capped to 5s.
+	// +optional
+	InspectionTimeout *metav1.Duration `json:"inspectionTimeout,omitempty"`
+}
+
+// GetInspectionTimeout returns the inspection timeout, or its default when not set, capped to
+// MaxEmulationFallbackInspectionTimeout.
+func (e *EmulationFallback) GetInspectionTimeout() time.Duration {
+	if e.InspectionTimeout == nil || e.InspectionTimeout.Duration <= 0 {
+		return DefaultEmulationFallbackInspectionTimeout
+	}
+	return min(e.InspectionTimeout.Duration, MaxEmulationFallbackInspectionTimeout)
+}
+
+// Validate implements IValidatingPlugin.
+func (e *EmulationFallback) Validate() error {
+	if errs := validation.IsDNS1123Subdomain(e.RuntimeClassName); len(errs) > 0 {
+		return fmt.Errorf("invalid emulationFallback.runtimeClassName %q: %s", e.RuntimeClassName, strings.Join(errs, "; "))
+	}
+	if e.InspectionTimeout != nil && e.InspectionTimeout.Duration <= 0 {
+		return fmt.Errorf("emulationFallback.inspectionTimeout must be positive")
+	}
+	return nil
+}
+
+func (e *EmulationFallback) Name() string {
+	return EmulationFallbackPluginName
+}
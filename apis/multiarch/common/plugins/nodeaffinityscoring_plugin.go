@@ -16,13 +16,29 @@ limitations under the License.
 
 package plugins
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
 
 const (
 	// PluginName for NodeAffinityScoring.
 	NodeAffinityScoringPluginName = "NodeAffinityScoring"
 )
 
+// NodeAffinityScoringMode defines how the weights of the NodeAffinityScoring plugin are computed.
+type NodeAffinityScoringMode string
+
+const (
+	// NodeAffinityScoringModeStatic uses the weights set in the platforms terms.
+	NodeAffinityScoringModeStatic NodeAffinityScoringMode = "Static"
+	// NodeAffinityScoringModeDynamic periodically computes the weights from the free capacity and the cost of the
+	// nodes of each architecture.
+	NodeAffinityScoringModeDynamic NodeAffinityScoringMode = "Dynamic"
+)
+
 // NodeAffinityScoring is the plugin that implements the ScorePlugin interface.
 type NodeAffinityScoring struct {
 	BasePlugin `json:",inline"`
@@ -30,6 +46,29 @@ type NodeAffinityScoring struct {
 	// Platforms is a required field and must contain at least one entry.
 	// +kubebuilder:validation:MinItems=1
 	Platforms []NodeAffinityScoringPlatformTerm `json:"platforms" protobuf:"bytes,2,opt,name=platforms"`
+
+	// Mode defines how the weights are computed. In the Static mode, the weights of the platforms terms are used.
+	// In the Dynamic mode, the pod placement controller periodically computes the weights of the architectures of the
+	// platforms terms from the CPU and memory allocatable and not requested on their nodes, and optionally from
+	// their cost. The computed weights are reported in the status of the ClusterPodPlacementConfig, and the weights of
+	// the platforms terms are used until they are first computed.
+	// The Dynamic mode is only supported in the ClusterPodPlacementConfig.
+	// Defaults to Static.
+	// +kubebuilder:validation:Enum=Static;Dynamic
+	// +kubebuilder:default=Static
+	// +optional
+	Mode NodeAffinityScoringMode `json:"mode,omitempty"`
+
+	// CostLabel is the key of the label, or annotation, of the nodes holding their cost, for example their hourly
+	// price. In the Dynamic mode, the architectures whose nodes are cheaper on average get higher weights.
+	// The architectures without cost information are not penalized.
+	// +optional
+	CostLabel string `json:"costLabel,omitempty"`
+}
+
+// IsDynamic returns true if the weights are computed dynamically.
+func (n *NodeAffinityScoring) IsDynamic() bool {
+	return n.Mode == NodeAffinityScoringModeDynamic
 }
 
 // ValidateArchitecturesSet checks whether duplicate architectures are set in NodeAffinityScoring
@@ -45,6 +84,19 @@ func (n *NodeAffinityScoring) ValidateArchitecturesSet() (bool, error) {
 	return true, nil
 }
 
+// Validate implements IValidatingPlugin.
+func (n *NodeAffinityScoring) Validate() error {
+	if _, err := n.ValidateArchitecturesSet(); err != nil {
+		return err
+	}
+	if n.CostLabel != "" {
+		if errs := validation.IsQualifiedName(n.CostLabel); len(errs) > 0 {
+			return fmt.Errorf("invalid nodeAffinityScoring.costLabel %q: %s", n.CostLabel, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
 // NodeAffinityScoringPlatformTerm holds configuration for specific platforms, with required fields validated.
 type NodeAffinityScoringPlatformTerm struct {
 	// Architecture must be a list of non-empty string of arch names.
@@ -0,0 +1,46 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+const (
+	// NodeInventoryPluginName is the name of the NodeInventory plugin.
+	NodeInventoryPluginName = "NodeInventory"
+)
+
+// NodeInventory restricts the architectures in the required node affinity of the pods to the ones of the nodes
+// available in the cluster.
+// When none of the architectures supported by the images of a pod is available in the cluster, the pod is labeled,
+// an event is published and the pod is counted in the status of the ClusterPodPlacementConfig.
+type NodeInventory struct {
+	BasePlugin `json:",inline"`
+
+	// EligibleNodesOnly restricts the inventory to the nodes the pod can be scheduled on: the nodes that are
+	// schedulable, whose NoSchedule and NoExecute taints are tolerated by the pod, and that match the nodeSelector
+	// and the required node affinity of the pod.
+	// +optional
+	EligibleNodesOnly bool `json:"eligibleNodesOnly,omitempty"`
+
+	// KeepGatedUntilNodeAvailable keeps the scheduling gate on the pods for which no node with a supported
+	// architecture is available, until such a node joins the cluster.
+	// The scheduling gate deadline does not apply to these pods.
+	// +optional
+	KeepGatedUntilNodeAvailable bool `json:"keepGatedUntilNodeAvailable,omitempty"`
+}
+
+func (n *NodeInventory) Name() string {
+	return NodeInventoryPluginName
+}
@@ -2,6 +2,10 @@ package plugins
 
 import (
 	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
 )
 
 func TestBasePlugin_IsEnabled(t *testing.T) {
@@ -45,3 +49,129 @@ func TestExecFormatErrorMonitor_Name(t *testing.T) {
 		t.Errorf("Expected plugin name %s, but got %s", ExecFormatErrorMonitorPluginName, plugin.Name())
 	}
 }
+
+func TestNodeInventory_Name(t *testing.T) {
+	plugin := &NodeInventory{}
+
+	if plugin.Name() != NodeInventoryPluginName {
+		t.Errorf("Expected plugin name %s, but got %s", NodeInventoryPluginName, plugin.Name())
+	}
+}
+
+func TestArchitectureTolerations_Name(t *testing.T) {
+	plugin := &ArchitectureTolerations{}
+
+	if plugin.Name() != ArchitectureTolerationsPluginName {
+		t.Errorf("Expected plugin name %s, but got %s", ArchitectureTolerationsPluginName, plugin.Name())
+	}
+}
+
+func TestArchitectureTolerations_ValidateArchitecturesSet(t *testing.T) {
+	plugin := &ArchitectureTolerations{
+		Platforms: []ArchitectureTaintsTerm{
+			{Architecture: "arm64", Taints: []ArchitectureTaint{{Key: "arch", Value: "arm64"}}},
+			{Architecture: "arm64", Taints: []ArchitectureTaint{{Key: "dedicated"}}},
+		},
+	}
+	if valid, err := plugin.ValidateArchitecturesSet(); valid || err == nil {
+		t.Errorf("Expected the duplicate architecture to be rejected")
+	}
+	plugin.Platforms = plugin.Platforms[:1]
+	if valid, err := plugin.ValidateArchitecturesSet(); !valid || err != nil {
+		t.Errorf("Expected the architectures set to be valid, got %v", err)
+	}
+}
+
+func TestEmulationFallback_Name(t *testing.T) {
+	plugin := &EmulationFallback{}
+
+	if plugin.Name() != EmulationFallbackPluginName {
+		t.Errorf("Expected plugin name %s, but got %s", EmulationFallbackPluginName, plugin.Name())
+	}
+}
+
+func TestArchitectureSpread_Name(t *testing.T) {
+	plugin := &ArchitectureSpread{}
+
+	if plugin.Name() != ArchitectureSpreadPluginName {
+		t.Errorf("Expected plugin name %s, but got %s", ArchitectureSpreadPluginName, plugin.Name())
+	}
+}
+
+func TestArchitectureSpread_TopologySpreadConstraint(t *testing.T) {
+	plugin := &ArchitectureSpread{}
+	constraint := plugin.TopologySpreadConstraint("kubernetes.io/arch", nil)
+	if constraint.MaxSkew != 1 || constraint.WhenUnsatisfiable != corev1.ScheduleAnyway {
+		t.Errorf("Expected the default maxSkew and whenUnsatisfiable, got %d and %s",
+			constraint.MaxSkew, constraint.WhenUnsatisfiable)
+	}
+	plugin.MaxSkew = 2
+	plugin.WhenUnsatisfiable = corev1.DoNotSchedule
+	constraint = plugin.TopologySpreadConstraint("kubernetes.io/arch", nil)
+	if constraint.MaxSkew != 2 || constraint.WhenUnsatisfiable != corev1.DoNotSchedule {
+		t.Errorf("Expected the configured maxSkew and whenUnsatisfiable, got %d and %s",
+			constraint.MaxSkew, constraint.WhenUnsatisfiable)
+	}
+}
+
+func TestPlugins_Validate(t *testing.T) {
+	var nilPlugins *Plugins
+	if err := nilPlugins.Validate(); err != nil {
+		t.Errorf("Expected nil plugins to be valid, got %v", err)
+	}
+	p := &Plugins{
+		NodeAffinityScoring: &NodeAffinityScoring{
+			Platforms: []NodeAffinityScoringPlatformTerm{{Architecture: "arm64", Weight: 1}},
+		},
+		ExecFormatErrorMonitor: &ExecFormatErrorMonitor{},
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("Expected the plugins to be valid, got %v", err)
+	}
+	p.NodeAffinityScoring.Platforms = append(p.NodeAffinityScoring.Platforms,
+		NodeAffinityScoringPlatformTerm{Architecture: "arm64", Weight: 2})
+	if err := p.Validate(); err == nil {
+		t.Errorf("Expected the duplicate architecture in the NodeAffinityScoring plugin to be rejected")
+	}
+}
+
+func TestPlugins_PluginEnabled(t *testing.T) {
+	var nilPlugins *Plugins
+	if nilPlugins.PluginEnabled(common.NodeInventoryPluginName) {
+		t.Errorf("Expected no plugin to be enabled in nil plugins")
+	}
+	p := &Plugins{
+		NodeInventory:     &NodeInventory{BasePlugin: BasePlugin{Enabled: true}},
+		EmulationFallback: &EmulationFallback{BasePlugin: BasePlugin{Enabled: false}},
+	}
+	if !p.PluginEnabled(common.NodeInventoryPluginName) {
+		t.Errorf("Expected the NodeInventory plugin to be enabled")
+	}
+	if p.PluginEnabled(common.EmulationFallbackPluginName) {
+		t.Errorf("Expected the disabled EmulationFallback plugin not to be enabled")
+	}
+	if p.PluginEnabled(common.NodeAffinityScoringPluginName) {
+		t.Errorf("Expected the unset NodeAffinityScoring plugin not to be enabled")
+	}
+	if p.PluginEnabled(common.ArchitectureSpreadPluginName) {
+		t.Errorf("Expected the local-only ArchitectureSpread plugin not to be enabled")
+	}
+}
+
+func TestPlugins_All(t *testing.T) {
+	var nilPlugins *Plugins
+	if all := nilPlugins.All(); len(all) != 0 {
+		t.Errorf("Expected no plugin in nil plugins, got %d", len(all))
+	}
+	p := &Plugins{
+		EmulationFallback:   &EmulationFallback{},
+		NodeAffinityScoring: &NodeAffinityScoring{},
+	}
+	all := p.All()
+	if len(all) != 2 {
+		t.Fatalf("Expected the 2 configured plugins, got %d", len(all))
+	}
+	if all[0].Name() != NodeAffinityScoringPluginName || all[1].Name() != EmulationFallbackPluginName {
+		t.Errorf("Expected the plugins ordered by ID, got %s and %s", all[0].Name(), all[1].Name())
+	}
+}
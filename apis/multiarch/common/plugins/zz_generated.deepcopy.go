@@ -20,6 +20,84 @@ limitations under the License.
 
 package plugins
 
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchitectureSpread) DeepCopyInto(out *ArchitectureSpread) {
+	*out = *in
+	out.BasePlugin = in.BasePlugin
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchitectureSpread.
+func (in *ArchitectureSpread) DeepCopy() *ArchitectureSpread {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchitectureSpread)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchitectureTaint) DeepCopyInto(out *ArchitectureTaint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchitectureTaint.
+func (in *ArchitectureTaint) DeepCopy() *ArchitectureTaint {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchitectureTaint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchitectureTaintsTerm) DeepCopyInto(out *ArchitectureTaintsTerm) {
+	*out = *in
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]ArchitectureTaint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchitectureTaintsTerm.
+func (in *ArchitectureTaintsTerm) DeepCopy() *ArchitectureTaintsTerm {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchitectureTaintsTerm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchitectureTolerations) DeepCopyInto(out *ArchitectureTolerations) {
+	*out = *in
+	out.BasePlugin = in.BasePlugin
+	if in.Platforms != nil {
+		in, out := &in.Platforms, &out.Platforms
+		*out = make([]ArchitectureTaintsTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchitectureTolerations.
+func (in *ArchitectureTolerations) DeepCopy() *ArchitectureTolerations {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchitectureTolerations)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BasePlugin) DeepCopyInto(out *BasePlugin) {
 	*out = *in
@@ -35,6 +113,27 @@ func (in *BasePlugin) DeepCopy() *BasePlugin {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmulationFallback) DeepCopyInto(out *EmulationFallback) {
+	*out = *in
+	out.BasePlugin = in.BasePlugin
+	if in.InspectionTimeout != nil {
+		in, out := &in.InspectionTimeout, &out.InspectionTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmulationFallback.
+func (in *EmulationFallback) DeepCopy() *EmulationFallback {
+	if in == nil {
+		return nil
+	}
+	out := new(EmulationFallback)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExecFormatErrorMonitor) DeepCopyInto(out *ExecFormatErrorMonitor) {
 	*out = *in
@@ -59,6 +158,11 @@ func (in *LocalPlugins) DeepCopyInto(out *LocalPlugins) {
 		*out = new(NodeAffinityScoring)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ArchitectureSpread != nil {
+		in, out := &in.ArchitectureSpread, &out.ArchitectureSpread
+		*out = new(ArchitectureSpread)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalPlugins.
@@ -107,6 +211,22 @@ func (in *NodeAffinityScoringPlatformTerm) DeepCopy() *NodeAffinityScoringPlatfo
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeInventory) DeepCopyInto(out *NodeInventory) {
+	*out = *in
+	out.BasePlugin = in.BasePlugin
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeInventory.
+func (in *NodeInventory) DeepCopy() *NodeInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Plugins) DeepCopyInto(out *Plugins) {
 	*out = *in
@@ -120,6 +240,21 @@ func (in *Plugins) DeepCopyInto(out *Plugins) {
 		*out = new(ExecFormatErrorMonitor)
 		**out = **in
 	}
+	if in.NodeInventory != nil {
+		in, out := &in.NodeInventory, &out.NodeInventory
+		*out = new(NodeInventory)
+		**out = **in
+	}
+	if in.ArchitectureTolerations != nil {
+		in, out := &in.ArchitectureTolerations, &out.ArchitectureTolerations
+		*out = new(ArchitectureTolerations)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EmulationFallback != nil {
+		in, out := &in.EmulationFallback, &out.EmulationFallback
+		*out = new(EmulationFallback)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugins.
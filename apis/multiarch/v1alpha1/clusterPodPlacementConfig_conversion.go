@@ -17,19 +17,40 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"encoding/json"
+	"fmt"
+	"maps"
+
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	multiarchv1beta1 "github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
 )
 
+// ConversionDataAnnotation is the reserved annotation holding, in the v1alpha1 representation of a
+// ClusterPodPlacementConfig, the v1beta1 spec. It preserves the fields that only exist in v1beta1, e.g., the plugins,
+// when an object is read and written back through v1alpha1.
+const ConversionDataAnnotation = "multiarch.openshift.io/v1beta1-spec"
+
 // ConvertTo converts this ClusterPodPlacementConfig to the Hub version v1beta1.
 func (src *ClusterPodPlacementConfig) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*multiarchv1beta1.ClusterPodPlacementConfig)
 
 	// ObjectMeta
-	dst.ObjectMeta = src.ObjectMeta
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
 
 	// Spec
+	// The fields that only exist in v1beta1 are restored from the conversion data, if any.
+	dst.Spec = multiarchv1beta1.ClusterPodPlacementConfigSpec{}
+	if data, ok := dst.Annotations[ConversionDataAnnotation]; ok {
+		if err := json.Unmarshal([]byte(data), &dst.Spec); err != nil {
+			return fmt.Errorf("unable to restore the v1beta1 spec from the %s annotation: %w",
+				ConversionDataAnnotation, err)
+		}
+		delete(dst.Annotations, ConversionDataAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
 	dst.Spec.LogVerbosity = src.Spec.LogVerbosity
 	dst.Spec.NamespaceSelector = src.Spec.NamespaceSelector
 
@@ -51,6 +72,8 @@ func (dst *ClusterPodPlacementConfig) ConvertFrom(srcRaw conversion.Hub) error {
 
 	// ObjectMeta
 	dst.ObjectMeta = src.ObjectMeta
+	// The annotations are copied, not to add the conversion data to the ones of the hub object.
+	dst.Annotations = maps.Clone(src.Annotations)
 
 	// Spec
 	dst.Spec.LogVerbosity = src.Spec.LogVerbosity
@@ -62,6 +85,12 @@ func (dst *ClusterPodPlacementConfig) ConvertFrom(srcRaw conversion.Hub) error {
 	if dst.Annotations == nil {
 		dst.Annotations = make(map[string]string)
 	}
+	// The whole v1beta1 spec is kept, for the fields that only exist in v1beta1 to be restored by ConvertTo.
+	data, err := json.Marshal(src.Spec)
+	if err != nil {
+		return fmt.Errorf("unable to store the v1beta1 spec in the %s annotation: %w", ConversionDataAnnotation, err)
+	}
+	dst.Annotations[ConversionDataAnnotation] = string(data)
 
 	return nil
 }
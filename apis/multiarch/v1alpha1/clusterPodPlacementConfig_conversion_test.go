@@ -0,0 +1,103 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/randfill"
+
+	multiarchv1beta1 "github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+)
+
+const fuzzIterations = 500
+
+// newFiller returns a filler of the specs whose values survive a JSON round-trip.
+func newFiller() *randfill.Filler {
+	return randfill.New().NilChance(0.3).NumElements(1, 3).Funcs(
+		func(q *resource.Quantity, c randfill.Continue) {
+			*q = *resource.NewQuantity(c.Int63n(1000), resource.DecimalSI)
+		},
+	)
+}
+
+func objectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        "cluster",
+		Annotations: map[string]string{"example.com/owner": "gitops"},
+		Generation:  3,
+	}
+}
+
+func TestClusterPodPlacementConfig_v1beta1RoundTrip(t *testing.T) {
+	g := NewGomegaWithT(t)
+	filler := newFiller()
+	for i := 0; i < fuzzIterations; i++ {
+		hub := &multiarchv1beta1.ClusterPodPlacementConfig{ObjectMeta: objectMeta()}
+		filler.Fill(&hub.Spec)
+		original := hub.DeepCopy()
+
+		spoke := &ClusterPodPlacementConfig{}
+		g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+		g.Expect(hub).To(Equal(original), "the conversion should not modify the hub object")
+		g.Expect(spoke.Annotations).To(HaveKey(ConversionDataAnnotation))
+
+		restored := &multiarchv1beta1.ClusterPodPlacementConfig{}
+		g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+		g.Expect(equality.Semantic.DeepEqual(restored.Spec, original.Spec)).To(BeTrue(),
+			"the v1beta1 spec should survive the round-trip through v1alpha1:\n%+v\n%+v", restored.Spec, original.Spec)
+		g.Expect(restored.Annotations).To(Equal(original.Annotations))
+	}
+}
+
+func TestClusterPodPlacementConfig_v1alpha1RoundTrip(t *testing.T) {
+	g := NewGomegaWithT(t)
+	filler := newFiller()
+	for i := 0; i < fuzzIterations; i++ {
+		spoke := &ClusterPodPlacementConfig{ObjectMeta: objectMeta()}
+		filler.Fill(&spoke.Spec)
+		original := spoke.DeepCopy()
+
+		hub := &multiarchv1beta1.ClusterPodPlacementConfig{}
+		g.Expect(spoke.ConvertTo(hub)).To(Succeed())
+		restored := &ClusterPodPlacementConfig{}
+		g.Expect(restored.ConvertFrom(hub)).To(Succeed())
+		g.Expect(equality.Semantic.DeepEqual(restored.Spec, original.Spec)).To(BeTrue(),
+			"the v1alpha1 spec should survive the round-trip through v1beta1:\n%+v\n%+v", restored.Spec, original.Spec)
+		delete(restored.Annotations, ConversionDataAnnotation)
+		g.Expect(restored.Annotations).To(Equal(original.Annotations))
+	}
+}
+
+func TestClusterPodPlacementConfig_v1alpha1Changes(t *testing.T) {
+	g := NewGomegaWithT(t)
+	filler := newFiller()
+	for i := 0; i < fuzzIterations; i++ {
+		hub := &multiarchv1beta1.ClusterPodPlacementConfig{ObjectMeta: objectMeta()}
+		filler.Fill(&hub.Spec)
+		spoke := &ClusterPodPlacementConfig{}
+		g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+		// A client pinning v1alpha1 changes the fields it knows about.
+		filler.Fill(&spoke.Spec)
+		restored := &multiarchv1beta1.ClusterPodPlacementConfig{}
+		g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+
+		want := hub.Spec.DeepCopy()
+		want.LogVerbosity = spoke.Spec.LogVerbosity
+		want.NamespaceSelector = spoke.Spec.NamespaceSelector
+		g.Expect(equality.Semantic.DeepEqual(restored.Spec, *want)).To(BeTrue(),
+			"the v1alpha1 changes should be applied and the v1beta1 fields kept:\n%+v\n%+v", restored.Spec, *want)
+	}
+}
+
+func TestClusterPodPlacementConfig_ConvertTo_invalidConversionData(t *testing.T) {
+	g := NewGomegaWithT(t)
+	spoke := &ClusterPodPlacementConfig{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{ConversionDataAnnotation: "{invalid"},
+	}}
+	g.Expect(spoke.ConvertTo(&multiarchv1beta1.ClusterPodPlacementConfig{})).NotTo(Succeed())
+}
@@ -18,8 +18,11 @@ package v1beta1
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
@@ -48,24 +51,298 @@ type ClusterPodPlacementConfigSpec struct {
 	// This field is optional and will be omitted from the output if not set.
 	// +optional
 	Plugins *plugins.Plugins `json:"plugins,omitempty"`
+
+	// SchedulingGateDeadline is the maximum time a pod is expected to stay gated by the pod placement operand.
+	// The pod placement controller periodically looks for pods gated for longer than this deadline: it retries
+	// processing them and, if they are still gated, it removes the scheduling gate without setting the
+	// architecture-aware node affinity.
+	// Defaults to 10m.
+	// +optional
+	SchedulingGateDeadline *metav1.Duration `json:"schedulingGateDeadline,omitempty"`
+
+	// InjectedContainerRules tells the pod placement controller how to consider the containers that other mutating
+	// webhooks (e.g., service meshes or secret injectors) inject in the pods, such as sidecars.
+	// The rules are evaluated in order for each container of a pod and the first matching rule applies.
+	// The containers matched by no rule are inspected.
+	// The decision taken for each container is recorded in the multiarch.openshift.io/container-decisions
+	// annotation of the pods.
+	// +optional
+	InjectedContainerRules []InjectedContainerRule `json:"injectedContainerRules,omitempty"`
+
+	// ValidatingPolicy configures a validating webhook that rejects at admission the pods whose images cannot run
+	// on the architectures required by the policy, instead of letting them sit Pending.
+	// When not set, no pod is rejected.
+	// +optional
+	ValidatingPolicy *ValidatingPolicy `json:"validatingPolicy,omitempty"`
+
+	// AllowedArchitectures is the default list of the architectures the pods are allowed to run on. The architectures
+	// supported by the images of a pod, or selected by the user in its nodeSelector or nodeAffinity, are intersected
+	// with it, and the EmulationFallback plugin only sends the pods to the nodes of an allowed architecture. The
+	// PodPlacementConfigs can override it for the pods they select. When empty, all the architectures are allowed.
+	// +optional
+	// +kubebuilder:validation:items:Enum=arm64;amd64;ppc64le;s390x
+	AllowedArchitectures []string `json:"allowedArchitectures,omitempty"`
+
+	// ImageDigestPinning enables the pin-digest mode: while a pod is gated, the pod placement controller rewrites the
+	// images of its containers to the digests of the manifests or indexes it inspected, so that the nodes pull exactly
+	// the images whose architectures were inspected, even if their tags are moved in the meantime.
+	// The original images are recorded in the multiarch.openshift.io/original-images annotation of the pods.
+	// When not set, the images are never rewritten.
+	// +optional
+	ImageDigestPinning *ImageDigestPinning `json:"imageDigestPinning,omitempty"`
+
+	// GlobalPullSecretRef references the Secret with the registry credentials used to inspect the images, in addition
+	// to the image pull secrets of the pods. Defaults to the openshift-config/pull-secret Secret on OpenShift and to
+	// the pull-secret Secret in the namespace of the operator on the other clusters.
+	// +optional
+	GlobalPullSecretRef *corev1.SecretReference `json:"globalPullSecretRef,omitempty"`
+
+	// CABundleConfigMapRef references a ConfigMap in the namespace of the operator whose ca-bundle.crt key holds the
+	// CA bundle used to verify the TLS certificates of the registries. Defaults to the ConfigMap injected with the
+	// trusted CA bundle of the cluster on OpenShift. On the other clusters, the CA bundle shipped in the operand image
+	// is used when it is not set.
+	// +optional
+	CABundleConfigMapRef *corev1.LocalObjectReference `json:"caBundleConfigmapRef,omitempty"`
+
+	// TLSMode selects how the serving certificates of the operand and the CA bundles of its webhook configurations
+	// are provisioned. Defaults to ServiceCA on OpenShift, to CertManager on the other clusters serving the
+	// cert-manager APIs and to SelfManaged otherwise. The serving certificates of the operator itself are issued by
+	// the operator only when it is explicitly set to SelfManaged.
+	// +optional
+	TLSMode TLSMode `json:"tlsMode,omitempty"`
+
+	// Operands customizes the workloads of the operand components, e.g., their replicas and resources.
+	// +optional
+	Operands *Operands `json:"operands,omitempty"`
+
+	// Paused stops the gating of the new pods, leaving the operand deployed: the webhook configurations are removed
+	// and the pod placement controller keeps processing the pods that are already gated until they are all ungated.
+	// The validating webhook configuration of the validatingPolicy is removed too: the policy is not enforced on the
+	// pods admitted while paused.
+	// The deployments and their RBAC are kept, so that resuming the operand does not require a new rollout.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// TLSMode is the provider of the serving certificates of the operand.
+// +kubebuilder:validation:Enum=ServiceCA;CertManager;SelfManaged
+type TLSMode string
+
+const (
+	// TLSModeServiceCA relies on the OpenShift service CA operator to issue the serving certificates and inject the
+	// CA bundles.
+	TLSModeServiceCA TLSMode = "ServiceCA"
+	// TLSModeCertManager relies on cert-manager: the operator creates a self-signed Issuer and a Certificate for
+	// each operand Service, and the cert-manager CA injector injects the CA bundles.
+	TLSModeCertManager TLSMode = "CertManager"
+	// TLSModeSelfManaged makes the operator generate a CA, issue the serving certificates into the Secrets named
+	// after the operand Services and inject the CA bundles, rotating them before they expire.
+	TLSModeSelfManaged TLSMode = "SelfManaged"
+)
+
+// ImageDigestPinning configures the pin-digest mode.
+type ImageDigestPinning struct {
+	// ExcludedNamespaces are the namespaces whose pods keep their original images.
+	// +optional
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+
+	// ExcludedImagePrefixes are the prefixes of the images that are not rewritten, e.g., "quay.io/my-org/".
+	// +optional
+	ExcludedImagePrefixes []string `json:"excludedImagePrefixes,omitempty"`
+}
+
+// Excludes returns true if the image of a container of a pod in the given namespace must not be pinned.
+func (p *ImageDigestPinning) Excludes(namespace, image string) bool {
+	if p == nil {
+		return true
+	}
+	if slices.Contains(p.ExcludedNamespaces, namespace) {
+		return true
+	}
+	return slices.ContainsFunc(p.ExcludedImagePrefixes, func(prefix string) bool {
+		return prefix != "" && strings.HasPrefix(image, prefix)
+	})
+}
+
+// ValidatingPolicyMode is the policy enforced by the validating webhook.
+// +kubebuilder:validation:Enum=RequireCommonArchitecture;RequireMultiArchImages
+type ValidatingPolicyMode string
+
+const (
+	// RequireCommonArchitecture rejects the pods whose images have no architecture in common.
+	RequireCommonArchitecture ValidatingPolicyMode = "RequireCommonArchitecture"
+	// RequireMultiArchImages rejects the pods using any single-architecture image, in addition to the ones rejected
+	// by RequireCommonArchitecture.
+	RequireMultiArchImages ValidatingPolicyMode = "RequireMultiArchImages"
+)
+
+// ValidatingPolicy configures the validating webhook for the pods.
+type ValidatingPolicy struct {
+	// Mode is the policy enforced in all the namespaces selected by the namespaceSelector.
+	// A namespace can override it with the multiarch.openshift.io/validating-policy label, set to one of the modes or
+	// to "None" to opt out. When empty, the policy is enforced only in the labeled namespaces.
+	// +optional
+	Mode ValidatingPolicyMode `json:"mode,omitempty"`
+
+	// InspectionTimeout enables the inspection at admission of the images that are not in the image inspection
+	// cache, bounded by the given timeout. The pods are admitted when the inspection does not complete in time.
+	// When not set, only the cached inspection results are used and the pods with images not inspected yet are
+	// admitted. It cannot exceed 8s.
+	// +optional
+	InspectionTimeout *metav1.Duration `json:"inspectionTimeout,omitempty"`
+}
+
+// MaxValidatingPolicyInspectionTimeout is the highest accepted value for .spec.validatingPolicy.inspectionTimeout,
+// below the timeout of the validating webhook.
+const MaxValidatingPolicyInspectionTimeout = 8 * time.Second
+
+// GetInspectionTimeout returns the timeout of the inspection at admission, 0 if it is disabled.
+func (p *ValidatingPolicy) GetInspectionTimeout() time.Duration {
+	if p == nil || p.InspectionTimeout == nil {
+		return 0
+	}
+	return p.InspectionTimeout.Duration
 }
 
+// InjectedContainerPolicy is the decision applied to the containers matched by an InjectedContainerRule.
+// +kubebuilder:validation:Enum=AssumeMultiArch;Include
+type InjectedContainerPolicy string
+
+const (
+	// InjectedContainerPolicyAssumeMultiArch excludes the image of the container from the inspection: it is assumed
+	// to support all the architectures.
+	InjectedContainerPolicyAssumeMultiArch InjectedContainerPolicy = "AssumeMultiArch"
+	// InjectedContainerPolicyInclude requires the image of the container to be inspected.
+	InjectedContainerPolicyInclude InjectedContainerPolicy = "Include"
+)
+
+// InjectedContainerRule matches the containers of the pods by name and/or image.
+// At least one of containerName and imagePrefix must be set: when both are set, a container must match both.
+type InjectedContainerRule struct {
+	// ContainerName is the name of the containers matched by the rule.
+	// +optional
+	ContainerName string `json:"containerName,omitempty"`
+
+	// ImagePrefix is the prefix of the images of the containers matched by the rule, e.g., "docker.io/istio/proxyv2".
+	// +optional
+	ImagePrefix string `json:"imagePrefix,omitempty"`
+
+	// Policy is the decision applied to the matched containers.
+	// +kubebuilder:validation:Required
+	Policy InjectedContainerPolicy `json:"policy"`
+}
+
+// Matches returns true if the container with the given name and image is matched by the rule.
+func (r *InjectedContainerRule) Matches(name, image string) bool {
+	if r.ContainerName == "" && r.ImagePrefix == "" {
+		return false
+	}
+	return (r.ContainerName == "" || r.ContainerName == name) &&
+		(r.ImagePrefix == "" || strings.HasPrefix(image, r.ImagePrefix))
+}
+
+// DefaultSchedulingGateDeadline is the deadline used when .spec.schedulingGateDeadline is not set.
+const DefaultSchedulingGateDeadline = 10 * time.Minute
+
 // ClusterPodPlacementConfigStatus defines the observed state of ClusterPodPlacementConfig
 type ClusterPodPlacementConfigStatus struct {
 	// Conditions represents the latest available observations of a ClusterPodPlacementConfig's current state.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// Stats summarizes the runtime behavior of the pod placement operand.
+	// It is periodically updated by the leader of the pod placement controllers.
+	// +optional
+	Stats *PodPlacementStats `json:"stats,omitempty"`
+
+	// NodeAffinityScoring reports the weights computed by the NodeAffinityScoring plugin in the Dynamic mode.
+	// +optional
+	NodeAffinityScoring *NodeAffinityScoringStatus `json:"nodeAffinityScoring,omitempty"`
+
 	// The following fields are used to derive the conditions. They are not exposed to the user.
 	available                                bool `json:"-"`
 	progressing                              bool `json:"-"`
 	degraded                                 bool `json:"-"`
 	deprovisioning                           bool `json:"-"`
+	paused                                   bool `json:"-"`
 	podPlacementControllerNotReady           bool `json:"-"`
 	podPlacementWebhookNotReady              bool `json:"-"`
 	mutatingWebhookConfigurationNotAvailable bool `json:"-"`
 	canDeployMutatingWebhook                 bool `json:"-"`
 }
 
+// PodPlacementStats summarizes the runtime behavior of the pod placement operand.
+// The counters of the last window refer to the interval between two consecutive reports, i.e., one minute.
+type PodPlacementStats struct {
+	// PodsWithoutEligibleNodes is the number of pods for which none of the architectures supported by their images
+	// was available in the cluster. It is updated only when the NodeInventory plugin is enabled.
+	// +optional
+	PodsWithoutEligibleNodes int64 `json:"podsWithoutEligibleNodes,omitempty"`
+
+	// GatedPods is the number of pods currently holding the scheduling gate of the operand.
+	// +optional
+	GatedPods int64 `json:"gatedPods,omitempty"`
+
+	// PodsWithNoSupportedArchitectures is the number of pods currently labeled with
+	// multiarch.openshift.io/no-supported-arch, i.e., whose images have no architecture in common.
+	// +optional
+	PodsWithNoSupportedArchitectures int64 `json:"podsWithNoSupportedArchitectures,omitempty"`
+
+	// ProcessedPods is the number of gated pods processed by the controller in the last window.
+	// +optional
+	ProcessedPods int64 `json:"processedPods,omitempty"`
+
+	// InspectionErrors is the number of gated pods processed in the last window whose images could not be inspected.
+	// +optional
+	InspectionErrors int64 `json:"inspectionErrors,omitempty"`
+
+	// InspectionErrorPercentage is the percentage of the processed pods whose processing failed in the last window.
+	// +optional
+	InspectionErrorPercentage int32 `json:"inspectionErrorPercentage,omitempty"`
+
+	// TopFailingRegistries are the registries with the most failed image inspections in the last window, in
+	// descending order of failures.
+	// +listType=atomic
+	// +optional
+	TopFailingRegistries []RegistryInspectionFailures `json:"topFailingRegistries,omitempty"`
+
+	// ImageCacheSize is the number of images whose architectures are cached by the controller.
+	// +optional
+	ImageCacheSize int32 `json:"imageCacheSize,omitempty"`
+
+	// ImageCacheHitPercentage is the percentage of the image inspections served by the cache in the last window.
+	// +optional
+	ImageCacheHitPercentage int32 `json:"imageCacheHitPercentage,omitempty"`
+
+	// Architectures are the architectures of the nodes in the cluster.
+	// +listType=set
+	// +optional
+	Architectures []string `json:"architectures,omitempty"`
+
+	// LastUpdateTime is the time the statistics were last reported.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// RegistryInspectionFailures is the number of failed image inspections for a registry.
+type RegistryInspectionFailures struct {
+	// Registry is the host of the registry, e.g., quay.io.
+	Registry string `json:"registry"`
+
+	// Failures is the number of failed image inspections in the last window.
+	Failures int64 `json:"failures"`
+}
+
+// NodeAffinityScoringStatus reports the weights computed by the NodeAffinityScoring plugin in the Dynamic mode.
+type NodeAffinityScoringStatus struct {
+	// Platforms are the computed weights of the architectures.
+	// +optional
+	Platforms []plugins.NodeAffinityScoringPlatformTerm `json:"platforms,omitempty"`
+
+	// LastUpdateTime is the time the weights were last computed.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
 func (s *ClusterPodPlacementConfigStatus) IsReady() bool {
 	return s.available
 }
@@ -82,6 +359,10 @@ func (s *ClusterPodPlacementConfigStatus) IsDeprovisioning() bool {
 	return s.deprovisioning
 }
 
+func (s *ClusterPodPlacementConfigStatus) IsPaused() bool {
+	return s.paused
+}
+
 func (s *ClusterPodPlacementConfigStatus) IsPodPlacementControllerNotReady() bool {
 	return s.podPlacementControllerNotReady
 }
@@ -102,6 +383,7 @@ func (s *ClusterPodPlacementConfigStatus) CanDeployMutatingWebhook() bool {
 // The build Conditions are:
 //   - Degraded: if some components are not available (no replicas) and the object is not deprovisioning
 //   - Deprovisioning: if the object is being deleted
+//   - Paused: if the operand is paused. The mutating webhook configuration is not expected to exist.
 //   - MutatingWebhookConfigurationNotAvailable: if the mutating webhook configuration does not exist
 //   - PodPlacementControllerNotReady: if the pod placement controller is not available or up-to-date
 //   - PodPlacementWebhookNotReady: if the pod placement webhook is not available or up-to-date
@@ -111,21 +393,27 @@ func (s *ClusterPodPlacementConfigStatus) Build(
 	podPlacementControllerAvailable, podPlacementWebhookAvailable,
 	podPlacementControllerUpToDate, podPlacementWebhookUpToDate,
 	mutatingWebhookConfigurationAvailable,
-	deprovisioning bool) {
+	paused, deprovisioning bool) {
 	s.deprovisioning = deprovisioning
+	s.paused = paused
+	// the mutating webhook configuration is removed on purpose while the operand is paused
+	mutatingWebhookConfigurationExpected := !paused
 	// tracks existence of the mutating webhook configuration
 	s.mutatingWebhookConfigurationNotAvailable = !mutatingWebhookConfigurationAvailable
 	// tracks the availability of the pod placement controller and webhook and if they are up to date
 	s.podPlacementControllerNotReady = !podPlacementControllerAvailable || !podPlacementControllerUpToDate
 	s.podPlacementWebhookNotReady = !podPlacementWebhookAvailable || !podPlacementWebhookUpToDate
 	// if all the components exist and have at least one replica ready
-	s.available = mutatingWebhookConfigurationAvailable && podPlacementWebhookAvailable && podPlacementControllerAvailable
+	s.available = (mutatingWebhookConfigurationAvailable || !mutatingWebhookConfigurationExpected) &&
+		podPlacementWebhookAvailable && podPlacementControllerAvailable
 	// if some components are not available (no replicas)
 	s.degraded = !s.available && !s.deprovisioning // degraded will not track deprovisioning
 	// allow the deployment of the mutating webhook configuration if the pod placement controller and webhook are available
 	// (at least one replica)
-	s.canDeployMutatingWebhook = podPlacementWebhookAvailable && podPlacementControllerAvailable && !s.deprovisioning
-	s.progressing = (!podPlacementControllerUpToDate || !podPlacementWebhookUpToDate || !mutatingWebhookConfigurationAvailable) && !s.deprovisioning
+	s.canDeployMutatingWebhook = podPlacementWebhookAvailable && podPlacementControllerAvailable && !s.deprovisioning &&
+		!s.paused
+	s.progressing = (!podPlacementControllerUpToDate || !podPlacementWebhookUpToDate ||
+		(!mutatingWebhookConfigurationAvailable && mutatingWebhookConfigurationExpected)) && !s.deprovisioning
 	s.buildConditions()
 }
 
@@ -140,9 +428,12 @@ func (s *ClusterPodPlacementConfigStatus) buildConditions() {
 	if s.podPlacementWebhookNotReady {
 		reason += PodPlacementWebhookNotRolledOutType
 	}
-	if s.mutatingWebhookConfigurationNotAvailable {
+	if s.mutatingWebhookConfigurationNotAvailable && !s.paused {
 		reason += MutatingWebhookConfigurationNotAvailable
 	}
+	if reason == "" && s.paused {
+		reason = PausedType
+	}
 	if reason == "" {
 		reason = AllComponentsReady
 	}
@@ -174,6 +465,16 @@ func (s *ClusterPodPlacementConfigStatus) buildConditions() {
 		Reason:  fmt.Sprintf("%s%s", trimAndCapitalize(notFromBool(s.deprovisioning)), DeprovisioningType),
 		Message: fmt.Sprintf(DeprovisioningMsg, notFromBool(s.deprovisioning), deprovisinoingMessagePostfix),
 	})
+	pausedMessagePostfix := ""
+	if s.paused {
+		pausedMessagePostfix = PendingPausedMsg
+	}
+	v1helpers.SetCondition(&s.Conditions, metav1.Condition{
+		Type:    PausedType,
+		Status:  conditionFromBool(s.paused),
+		Reason:  fmt.Sprintf("%s%s", trimAndCapitalize(notFromBool(s.paused)), PausedType),
+		Message: fmt.Sprintf(PausedMsg, notFromBool(s.paused), pausedMessagePostfix),
+	})
 	v1helpers.SetCondition(&s.Conditions, metav1.Condition{
 		Type:    PodPlacementControllerNotRolledOutType,
 		Status:  conditionFromBool(s.podPlacementControllerNotReady),
@@ -204,8 +505,14 @@ func (s *ClusterPodPlacementConfigStatus) buildConditions() {
 // +kubebuilder:printcolumn:name=Available,JSONPath=.status.conditions[?(@.type=="Available")].status,type=string
 // +kubebuilder:printcolumn:name=Progressing,JSONPath=.status.conditions[?(@.type=="Progressing")].status,type=string
 // +kubebuilder:printcolumn:name=Degraded,JSONPath=.status.conditions[?(@.type=="Degraded")].status,type=string
+// +kubebuilder:printcolumn:name=Paused,JSONPath=.spec.paused,type=boolean,priority=1
 // +kubebuilder:printcolumn:name=Since,JSONPath=.status.conditions[?(@.type=="Progressing")].lastTransitionTime,type=date
 // +kubebuilder:printcolumn:name=Status,JSONPath=.status.conditions[?(@.type=="Available")].reason,type=string
+// +kubebuilder:printcolumn:name=Gated,JSONPath=.status.stats.gatedPods,type=integer
+// +kubebuilder:printcolumn:name=Processed,JSONPath=.status.stats.processedPods,type=integer
+// +kubebuilder:printcolumn:name=Errors%,JSONPath=.status.stats.inspectionErrorPercentage,type=integer
+// +kubebuilder:printcolumn:name=Cache Hit%,JSONPath=.status.stats.imageCacheHitPercentage,type=integer,priority=1
+// +kubebuilder:printcolumn:name=Architectures,JSONPath=.status.stats.architectures,type=string,priority=1
 type ClusterPodPlacementConfig struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -221,6 +528,28 @@ func (c *ClusterPodPlacementConfig) PluginsEnabled(plugin common.Plugin) bool {
 	return false
 }
 
+// NodeAffinityScoringPlatforms returns the weights of the architectures of the NodeAffinityScoring plugin: the
+// computed ones in the Dynamic mode, once available, or the ones of the platforms terms.
+func (c *ClusterPodPlacementConfig) NodeAffinityScoringPlatforms() []plugins.NodeAffinityScoringPlatformTerm {
+	if c.Spec.Plugins == nil || c.Spec.Plugins.NodeAffinityScoring == nil {
+		return nil
+	}
+	if c.Spec.Plugins.NodeAffinityScoring.IsDynamic() && c.Status.NodeAffinityScoring != nil &&
+		len(c.Status.NodeAffinityScoring.Platforms) > 0 {
+		return c.Status.NodeAffinityScoring.Platforms
+	}
+	return c.Spec.Plugins.NodeAffinityScoring.Platforms
+}
+
+// GetSchedulingGateDeadline returns the configured scheduling gate deadline or DefaultSchedulingGateDeadline if
+// it is not set.
+func (c *ClusterPodPlacementConfig) GetSchedulingGateDeadline() time.Duration {
+	if c == nil || c.Spec.SchedulingGateDeadline == nil {
+		return DefaultSchedulingGateDeadline
+	}
+	return c.Spec.SchedulingGateDeadline.Duration
+}
+
 //+kubebuilder:object:root=true
 
 // ClusterPodPlacementConfigList contains a list of ClusterPodPlacementConfig
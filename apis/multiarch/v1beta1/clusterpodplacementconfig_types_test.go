@@ -1,9 +1,13 @@
 package v1beta1
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
 )
 
 func Test_conditionFromBool(t *testing.T) {
@@ -111,6 +115,7 @@ func TestClusterPodPlacementConfigStatus_Build(t *testing.T) {
 		podPlacementControllerUpToDate                 bool
 		podPlacementWebhookUpToDate                    bool
 		mutatingWebhookConfigurationAvailable          bool
+		paused                                         bool
 		deprovisioning                                 bool
 		expectDegraded                                 bool
 		expectDeprovisioning                           bool
@@ -206,6 +211,42 @@ func TestClusterPodPlacementConfigStatus_Build(t *testing.T) {
 			expectProgressing:                              true,
 			expectCanDeployMutatingWebhook:                 true,
 		},
+		{
+			name:                                  "Paused",
+			podPlacementControllerAvailable:       true,
+			podPlacementWebhookAvailable:          true,
+			podPlacementControllerUpToDate:        true,
+			podPlacementWebhookUpToDate:           true,
+			mutatingWebhookConfigurationAvailable: false,
+			paused:                                true,
+			deprovisioning:                        false,
+			expectDegraded:                        false,
+			expectDeprovisioning:                  false,
+			expectMutatingWebhookConfigurationNotAvailable: true,
+			expectPodPlacementControllerNotReady:           false,
+			expectPodPlacementWebhookNotReady:              false,
+			expectAvailable:                                true,
+			expectProgressing:                              false,
+			expectCanDeployMutatingWebhook:                 false,
+		},
+		{
+			name:                                  "PausedWithMutatingWebhookConfigurationPendingDeletion",
+			podPlacementControllerAvailable:       true,
+			podPlacementWebhookAvailable:          true,
+			podPlacementControllerUpToDate:        true,
+			podPlacementWebhookUpToDate:           true,
+			mutatingWebhookConfigurationAvailable: true,
+			paused:                                true,
+			deprovisioning:                        false,
+			expectDegraded:                        false,
+			expectDeprovisioning:                  false,
+			expectMutatingWebhookConfigurationNotAvailable: false,
+			expectPodPlacementControllerNotReady:           false,
+			expectPodPlacementWebhookNotReady:              false,
+			expectAvailable:                                true,
+			expectProgressing:                              false,
+			expectCanDeployMutatingWebhook:                 false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -217,6 +258,7 @@ func TestClusterPodPlacementConfigStatus_Build(t *testing.T) {
 				tt.podPlacementControllerUpToDate,
 				tt.podPlacementWebhookUpToDate,
 				tt.mutatingWebhookConfigurationAvailable,
+				tt.paused,
 				tt.deprovisioning,
 			)
 
@@ -247,3 +289,177 @@ func TestClusterPodPlacementConfigStatus_Build(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterPodPlacementConfig_GetSchedulingGateDeadline(t *testing.T) {
+	tests := []struct {
+		name string
+		cppc *ClusterPodPlacementConfig
+		want time.Duration
+	}{
+		{
+			name: "nil ClusterPodPlacementConfig",
+			cppc: nil,
+			want: DefaultSchedulingGateDeadline,
+		},
+		{
+			name: "deadline not set",
+			cppc: &ClusterPodPlacementConfig{},
+			want: DefaultSchedulingGateDeadline,
+		},
+		{
+			name: "deadline set",
+			cppc: &ClusterPodPlacementConfig{
+				Spec: ClusterPodPlacementConfigSpec{
+					SchedulingGateDeadline: &v1.Duration{Duration: 30 * time.Minute},
+				},
+			},
+			want: 30 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cppc.GetSchedulingGateDeadline(); got != tt.want {
+				t.Errorf("GetSchedulingGateDeadline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterPodPlacementConfig_NodeAffinityScoringPlatforms(t *testing.T) {
+	staticPlatforms := []plugins.NodeAffinityScoringPlatformTerm{{Architecture: "arm64", Weight: 50}}
+	computedPlatforms := []plugins.NodeAffinityScoringPlatformTerm{{Architecture: "arm64", Weight: 100}}
+	tests := []struct {
+		name string
+		mode plugins.NodeAffinityScoringMode
+		// status is the NodeAffinityScoring status of the ClusterPodPlacementConfig
+		status *NodeAffinityScoringStatus
+		want   []plugins.NodeAffinityScoringPlatformTerm
+	}{
+		{
+			name:   "static mode",
+			mode:   plugins.NodeAffinityScoringModeStatic,
+			status: &NodeAffinityScoringStatus{Platforms: computedPlatforms},
+			want:   staticPlatforms,
+		},
+		{
+			name: "dynamic mode before the first computation",
+			mode: plugins.NodeAffinityScoringModeDynamic,
+			want: staticPlatforms,
+		},
+		{
+			name:   "dynamic mode",
+			mode:   plugins.NodeAffinityScoringModeDynamic,
+			status: &NodeAffinityScoringStatus{Platforms: computedPlatforms},
+			want:   computedPlatforms,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cppc := &ClusterPodPlacementConfig{
+				Spec: ClusterPodPlacementConfigSpec{
+					Plugins: &plugins.Plugins{
+						NodeAffinityScoring: &plugins.NodeAffinityScoring{
+							BasePlugin: plugins.BasePlugin{Enabled: true},
+							Platforms:  staticPlatforms,
+							Mode:       tt.mode,
+						},
+					},
+				},
+				Status: ClusterPodPlacementConfigStatus{NodeAffinityScoring: tt.status},
+			}
+			if got := cppc.NodeAffinityScoringPlatforms(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NodeAffinityScoringPlatforms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInjectedContainerRule_Matches(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  InjectedContainerRule
+		image string
+		want  bool
+	}{
+		{
+			name:  "name matches",
+			rule:  InjectedContainerRule{ContainerName: "istio-proxy"},
+			image: "docker.io/istio/proxyv2:1.20",
+			want:  true,
+		},
+		{
+			name:  "image prefix matches",
+			rule:  InjectedContainerRule{ImagePrefix: "docker.io/istio/"},
+			image: "docker.io/istio/proxyv2:1.20",
+			want:  true,
+		},
+		{
+			name:  "name matches but image prefix does not",
+			rule:  InjectedContainerRule{ContainerName: "istio-proxy", ImagePrefix: "quay.io/"},
+			image: "docker.io/istio/proxyv2:1.20",
+			want:  false,
+		},
+		{
+			name:  "empty rule",
+			rule:  InjectedContainerRule{Policy: InjectedContainerPolicyInclude},
+			image: "docker.io/istio/proxyv2:1.20",
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches("istio-proxy", tt.image); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageDigestPinning_Excludes(t *testing.T) {
+	pinning := &ImageDigestPinning{
+		ExcludedNamespaces:    []string{"excluded"},
+		ExcludedImagePrefixes: []string{"quay.io/my-org/", ""},
+	}
+	tests := []struct {
+		name      string
+		pinning   *ImageDigestPinning
+		namespace string
+		image     string
+		want      bool
+	}{
+		{
+			name:      "pin-digest mode disabled",
+			namespace: "default",
+			image:     "docker.io/library/nginx:latest",
+			want:      true,
+		},
+		{
+			name:      "not excluded",
+			pinning:   pinning,
+			namespace: "default",
+			image:     "docker.io/library/nginx:latest",
+			want:      false,
+		},
+		{
+			name:      "excluded namespace",
+			pinning:   pinning,
+			namespace: "excluded",
+			image:     "docker.io/library/nginx:latest",
+			want:      true,
+		},
+		{
+			name:      "excluded image prefix",
+			pinning:   pinning,
+			namespace: "default",
+			image:     "quay.io/my-org/app:v1",
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pinning.Excludes(tt.namespace, tt.image); got != tt.want {
+				t.Errorf("Excludes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -19,10 +19,25 @@ package v1beta1
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
 )
 
 // +kubebuilder:webhook:path=/validate-multiarch-openshift-io-v1beta1-clusterpodplacementconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=multiarch.openshift.io,resources=clusterpodplacementconfigs,verbs=create;update,versions=v1beta1,name=validate-clusterpodplacementconfig.multiarch.openshift.io,admissionReviewVersions=v1
@@ -30,40 +45,205 @@ import (
 func (c *ClusterPodPlacementConfig) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
-		WithValidator(&ClusterPodPlacementConfigValidator{}).
+		// The API reader avoids caching all the nodes and namespaces in the operator for the rare validations.
+		WithValidator(&ClusterPodPlacementConfigValidator{reader: mgr.GetAPIReader()}).
 		Complete()
 }
 
+// MinSchedulingGateDeadline is the lowest accepted value for .spec.schedulingGateDeadline. Lower values would
+// let the watchdog race with the pod placement controller on pods that are still being inspected.
+const MinSchedulingGateDeadline = time.Minute
+
+// reservedNamespacePrefixes are the prefixes of the namespaces the pod placement operand never processes.
+var reservedNamespacePrefixes = []string{"openshift-", "kube-"}
+
+// ClusterPodPlacementConfigValidator rejects the invalid ClusterPodPlacementConfigs and warns about the settings that
+// have no effect. The warnings that depend on the state of the cluster, e.g., on the architectures of its nodes, are
+// only returned when the reader is set.
+// +kubebuilder:object:generate=false
 type ClusterPodPlacementConfigValidator struct {
+	reader client.Reader
 }
 
 func (v *ClusterPodPlacementConfigValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (warnings admission.Warnings, err error) {
-	return v.validate(obj)
+	return v.validate(ctx, obj)
 }
 
 func (v *ClusterPodPlacementConfigValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (warnings admission.Warnings, err error) {
-	return v.validate(newObj)
+	return v.validate(ctx, newObj)
 }
 
 func (v *ClusterPodPlacementConfigValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (warnings admission.Warnings, err error) {
 	return nil, nil
 }
 
-func (v *ClusterPodPlacementConfigValidator) validate(obj runtime.Object) (warnings admission.Warnings, err error) {
+func (v *ClusterPodPlacementConfigValidator) validate(ctx context.Context, obj runtime.Object) (warnings admission.Warnings, err error) {
 	cppc, ok := obj.(*ClusterPodPlacementConfig)
 	if !ok {
 		return nil, errors.New("not a ClusterPodPlacementConfig")
 	}
-	if cppc.Spec.Plugins == nil || cppc.Spec.Plugins.NodeAffinityScoring == nil {
-		return nil, nil
+	if cppc.Spec.SchedulingGateDeadline != nil && cppc.Spec.SchedulingGateDeadline.Duration < MinSchedulingGateDeadline {
+		return nil, fmt.Errorf(".spec.schedulingGateDeadline must be at least %s", MinSchedulingGateDeadline)
+	}
+	namespaceSelector, err := metav1.LabelSelectorAsSelector(cppc.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf(".spec.namespaceSelector is invalid: %w", err)
+	}
+	if err := cppc.Spec.Plugins.Validate(); err != nil {
+		return nil, err
+	}
+	if timeout := cppc.Spec.ValidatingPolicy.GetInspectionTimeout(); timeout < 0 || timeout > MaxValidatingPolicyInspectionTimeout {
+		return nil, fmt.Errorf(".spec.validatingPolicy.inspectionTimeout must be between 0 and %s", MaxValidatingPolicyInspectionTimeout)
+	}
+	if err := cppc.Spec.Operands.Validate(); err != nil {
+		return nil, err
 	}
-	// Verify unique Architecture terms
-	platforms := make(map[string]struct{})
-	for _, term := range cppc.Spec.Plugins.NodeAffinityScoring.Platforms {
-		if _, ok := platforms[term.Architecture]; ok {
-			return nil, errors.New("duplicate architecture in the .spec.plugins.nodeAffinityScoring.platforms list")
+	for i, rule := range cppc.Spec.InjectedContainerRules {
+		if rule.ContainerName == "" && rule.ImagePrefix == "" {
+			return nil, fmt.Errorf(".spec.injectedContainerRules[%d] must set at least one of containerName and imagePrefix", i)
 		}
-		platforms[term.Architecture] = struct{}{}
 	}
-	return nil, nil
+	if duplicates := duplicatedItems(cppc.Spec.AllowedArchitectures); len(duplicates) > 0 {
+		return nil, fmt.Errorf(".spec.allowedArchitectures lists more than once the architectures %s",
+			strings.Join(duplicates, ", "))
+	}
+	if cppc.Spec.ImageDigestPinning != nil {
+		for _, namespace := range cppc.Spec.ImageDigestPinning.ExcludedNamespaces {
+			if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+				return nil, fmt.Errorf(".spec.imageDigestPinning.excludedNamespaces: invalid namespace %q: %s",
+					namespace, strings.Join(errs, "; "))
+			}
+		}
+	}
+	warnings = append(warnings, pluginsWarnings(cppc)...)
+	if v.reader != nil {
+		warnings = append(warnings, v.nodeArchitecturesWarnings(ctx, cppc)...)
+		if cppc.Spec.NamespaceSelector != nil && !namespaceSelector.Empty() {
+			warnings = append(warnings, v.namespaceSelectorWarnings(ctx, namespaceSelector)...)
+		}
+	}
+	return warnings, nil
+}
+
+// pluginsWarnings returns the warnings about the settings of the plugins that have no effect.
+func pluginsWarnings(cppc *ClusterPodPlacementConfig) admission.Warnings {
+	var warnings admission.Warnings
+	p := cppc.Spec.Plugins
+	if p == nil {
+		return nil
+	}
+	if p.NodeInventory != nil && !p.NodeInventory.IsEnabled() &&
+		(p.NodeInventory.EligibleNodesOnly || p.NodeInventory.KeepGatedUntilNodeAvailable) {
+		warnings = append(warnings, "the NodeInventory plugin is disabled: eligibleNodesOnly and "+
+			"keepGatedUntilNodeAvailable have no effect")
+	}
+	if p.NodeAffinityScoring != nil && p.NodeAffinityScoring.CostLabel != "" && !p.NodeAffinityScoring.IsDynamic() {
+		warnings = append(warnings, "nodeAffinityScoring.costLabel has no effect in the Static mode")
+	}
+	if p.EmulationFallback != nil && p.EmulationFallback.InspectionTimeout != nil &&
+		p.EmulationFallback.InspectionTimeout.Duration > plugins.MaxEmulationFallbackInspectionTimeout {
+		warnings = append(warnings, fmt.Sprintf("emulationFallback.inspectionTimeout is capped to %s",
+			plugins.MaxEmulationFallbackInspectionTimeout))
+	}
+	if len(cppc.Spec.AllowedArchitectures) > 0 {
+		for _, architecture := range configuredArchitectures(p) {
+			if !slices.Contains(cppc.Spec.AllowedArchitectures, architecture) {
+				warnings = append(warnings, fmt.Sprintf("the architecture %s is configured in the plugins but it "+
+					"is not in .spec.allowedArchitectures: no pod will run on it", architecture))
+			}
+		}
+	}
+	return warnings
+}
+
+// nodeArchitecturesWarnings warns about the architectures configured in the plugins that no node in the cluster has.
+func (v *ClusterPodPlacementConfigValidator) nodeArchitecturesWarnings(ctx context.Context,
+	cppc *ClusterPodPlacementConfig) admission.Warnings {
+	architectures := configuredArchitectures(cppc.Spec.Plugins)
+	if len(architectures) == 0 {
+		return nil
+	}
+	nodes := &metav1.PartialObjectMetadataList{}
+	nodes.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("NodeList"))
+	if err := v.reader.List(ctx, nodes); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "Unable to list the nodes to validate the ClusterPodPlacementConfig")
+		return nil
+	}
+	nodeArchitectures := sets.New[string]()
+	for _, node := range nodes.Items {
+		nodeArchitectures.Insert(node.Labels[utils.ArchLabel])
+	}
+	var warnings admission.Warnings
+	for _, architecture := range architectures {
+		if !nodeArchitectures.Has(architecture) {
+			warnings = append(warnings, fmt.Sprintf("no node in the cluster has the architecture %s configured in "+
+				"the plugins: its settings have no effect until such a node joins the cluster", architecture))
+		}
+	}
+	return warnings
+}
+
+// namespaceSelectorWarnings warns about the namespaces selected by the namespaceSelector that the pod placement
+// operand never processes.
+func (v *ClusterPodPlacementConfigValidator) namespaceSelectorWarnings(ctx context.Context,
+	namespaceSelector labels.Selector) admission.Warnings {
+	namespaces := &metav1.PartialObjectMetadataList{}
+	namespaces.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("NamespaceList"))
+	if err := v.reader.List(ctx, namespaces); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "Unable to list the namespaces to validate the ClusterPodPlacementConfig")
+		return nil
+	}
+	var reserved []string
+	for _, namespace := range namespaces.Items {
+		if isReservedNamespace(namespace.Name) && namespaceSelector.Matches(labels.Set(namespace.Labels)) {
+			reserved = append(reserved, namespace.Name)
+		}
+	}
+	if len(reserved) == 0 {
+		return nil
+	}
+	return admission.Warnings{fmt.Sprintf(".spec.namespaceSelector selects the namespaces %s, that are excluded "+
+		"from the architecture-aware pod placement anyway", strings.Join(reserved, ", "))}
+}
+
+func isReservedNamespace(namespace string) bool {
+	if namespace == utils.Namespace() {
+		return true
+	}
+	for _, prefix := range reservedNamespacePrefixes {
+		if strings.HasPrefix(namespace, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// configuredArchitectures returns the architectures configured in the enabled plugins.
+func configuredArchitectures(p *plugins.Plugins) []string {
+	architectures := sets.New[string]()
+	if p == nil {
+		return nil
+	}
+	if p.PluginEnabled(common.NodeAffinityScoringPluginName) {
+		for _, term := range p.NodeAffinityScoring.Platforms {
+			architectures.Insert(term.Architecture)
+		}
+	}
+	if p.PluginEnabled(common.ArchitectureTolerationsPluginName) {
+		for _, term := range p.ArchitectureTolerations.Platforms {
+			architectures.Insert(term.Architecture)
+		}
+	}
+	return sets.List(architectures)
+}
+
+func duplicatedItems(items []string) []string {
+	seen, duplicates := sets.New[string](), sets.New[string]()
+	for _, item := range items {
+		if seen.Has(item) {
+			duplicates.Insert(item)
+		}
+		seen.Insert(item)
+	}
+	return sets.List(duplicates)
 }
@@ -0,0 +1,183 @@
+package v1beta1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+// fakeMetadataReader lists the metadata of the given nodes and namespaces.
+type fakeMetadataReader struct {
+	client.Reader
+	nodes      []metav1.PartialObjectMetadata
+	namespaces []metav1.PartialObjectMetadata
+}
+
+func (r *fakeMetadataReader) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	metadataList := list.(*metav1.PartialObjectMetadataList)
+	if metadataList.GetObjectKind().GroupVersionKind().Kind == "NodeList" {
+		metadataList.Items = r.nodes
+	} else {
+		metadataList.Items = r.namespaces
+	}
+	return nil
+}
+
+func objectMetadata(name string, labels map[string]string) metav1.PartialObjectMetadata {
+	return metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestClusterPodPlacementConfigValidator_validate(t *testing.T) {
+	reader := &fakeMetadataReader{
+		nodes: []metav1.PartialObjectMetadata{
+			objectMetadata("amd64-node", map[string]string{utils.ArchLabel: utils.ArchitectureAmd64}),
+			objectMetadata("arm64-node", map[string]string{utils.ArchLabel: utils.ArchitectureArm64}),
+		},
+		namespaces: []metav1.PartialObjectMetadata{
+			objectMetadata("payments", map[string]string{"placement": "enabled", "team": "payments"}),
+			objectMetadata("openshift-monitoring", map[string]string{"placement": "enabled"}),
+			objectMetadata("kube-system", nil),
+		},
+	}
+	scoring := func(architectures ...string) *plugins.NodeAffinityScoring {
+		n := &plugins.NodeAffinityScoring{BasePlugin: plugins.BasePlugin{Enabled: true}}
+		for _, architecture := range architectures {
+			n.Platforms = append(n.Platforms, plugins.NodeAffinityScoringPlatformTerm{Architecture: architecture, Weight: 10})
+		}
+		return n
+	}
+	tests := []struct {
+		name         string
+		spec         ClusterPodPlacementConfigSpec
+		wantErr      bool
+		wantWarnings int
+	}{
+		{
+			name: "empty spec",
+		},
+		{
+			name: "invalid namespace selector",
+			spec: ClusterPodPlacementConfigSpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "placement", Operator: metav1.LabelSelectorOpIn},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "namespace selector including reserved namespaces",
+			spec: ClusterPodPlacementConfigSpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"placement": "enabled"}},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name: "namespace selector excluding reserved namespaces",
+			spec: ClusterPodPlacementConfigSpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+			},
+		},
+		{
+			name: "scheduling gate deadline too short",
+			spec: ClusterPodPlacementConfigSpec{
+				SchedulingGateDeadline: &metav1.Duration{Duration: time.Second},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated allowed architectures",
+			spec: ClusterPodPlacementConfigSpec{
+				AllowedArchitectures: []string{utils.ArchitectureAmd64, utils.ArchitectureAmd64},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid namespace excluded from the image digest pinning",
+			spec: ClusterPodPlacementConfigSpec{
+				ImageDigestPinning: &ImageDigestPinning{ExcludedNamespaces: []string{"Invalid_Namespace"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid cost label",
+			spec: ClusterPodPlacementConfigSpec{
+				Plugins: &plugins.Plugins{NodeAffinityScoring: &plugins.NodeAffinityScoring{
+					Platforms: []plugins.NodeAffinityScoringPlatformTerm{{Architecture: utils.ArchitectureAmd64, Weight: 1}},
+					Mode:      plugins.NodeAffinityScoringModeDynamic,
+					CostLabel: "invalid label/cost/",
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "non-positive emulation fallback inspection timeout",
+			spec: ClusterPodPlacementConfigSpec{
+				Plugins: &plugins.Plugins{EmulationFallback: &plugins.EmulationFallback{
+					RuntimeClassName:  "emulation",
+					InspectionTimeout: &metav1.Duration{},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "scoring architectures available in the cluster",
+			spec: ClusterPodPlacementConfigSpec{
+				Plugins: &plugins.Plugins{NodeAffinityScoring: scoring(utils.ArchitectureAmd64, utils.ArchitectureArm64)},
+			},
+		},
+		{
+			name: "scoring architectures that no node has",
+			spec: ClusterPodPlacementConfigSpec{
+				Plugins: &plugins.Plugins{NodeAffinityScoring: scoring(utils.ArchitectureAmd64, utils.ArchitecturePpc64le,
+					utils.ArchitectureS390x)},
+			},
+			wantWarnings: 2,
+		},
+		{
+			name: "scoring architectures that are not allowed",
+			spec: ClusterPodPlacementConfigSpec{
+				AllowedArchitectures: []string{utils.ArchitectureAmd64},
+				Plugins:              &plugins.Plugins{NodeAffinityScoring: scoring(utils.ArchitectureAmd64, utils.ArchitectureArm64)},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name: "settings without effect",
+			spec: ClusterPodPlacementConfigSpec{
+				Plugins: &plugins.Plugins{
+					NodeInventory: &plugins.NodeInventory{KeepGatedUntilNodeAvailable: true},
+					NodeAffinityScoring: &plugins.NodeAffinityScoring{
+						Platforms: []plugins.NodeAffinityScoringPlatformTerm{{Architecture: utils.ArchitectureAmd64, Weight: 1}},
+						CostLabel: "example.com/cost",
+					},
+					EmulationFallback: &plugins.EmulationFallback{
+						RuntimeClassName:  "emulation",
+						InspectionTimeout: &metav1.Duration{Duration: time.Minute},
+					},
+				},
+			},
+			wantWarnings: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			v := &ClusterPodPlacementConfigValidator{reader: reader}
+			warnings, err := v.validate(context.TODO(), &ClusterPodPlacementConfig{Spec: tt.spec})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(warnings).To(HaveLen(tt.wantWarnings), "unexpected warnings: %v", warnings)
+		})
+	}
+}
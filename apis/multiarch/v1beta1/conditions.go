@@ -10,6 +10,7 @@ const (
 	DegradedType                             = "Degraded"
 	ProgressingType                          = "Progressing"
 	DeprovisioningType                       = "Deprovisioning"
+	PausedType                               = "Paused"
 
 	MutatingWebhookConfigurationReadyMsg = "The mutating webhook configuration is %sready."
 	PodPlacementControllerRolledOutMsg   = "The pod placement controller is %sfully rolled out."
@@ -20,5 +21,8 @@ const (
 	DeprovisioningMsg                    = "The cluster pod placement config operand is %sbeing deprovisioned. %s"
 	PendingDeprovisioningMsg             = "Some pods may still have the " + utils.SchedulingGateName +
 		"scheduling gate. The pod placement controller is updating them and will terminate."
+	PausedMsg        = "The cluster pod placement config operand is %spaused. %s"
+	PendingPausedMsg = "The new pods are not gated. The pod placement controller keeps processing the pods that " +
+		"still have the " + utils.SchedulingGateName + " scheduling gate."
 	AllComponentsReady = "AllComponentsReady"
 )
@@ -37,5 +37,7 @@ var (
 
 const ClusterPodPlacementConfigResource = "clusterpodplacementconfigs"
 const ClusterPodPlacementConfigKind = "ClusterPodPlacementConfig"
+const PodPlacementConfigResource = "podplacementconfigs"
 const ENoExecEventKind = "ENoExecEvent"
 const ENoExecEventResource = "enoexecevents"
+const ImageRewriteRuleResource = "imagerewriterules"
@@ -0,0 +1,150 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"text/template"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ImageRewriteRuleSpec defines the desired state of ImageRewriteRule
+type ImageRewriteRuleSpec struct {
+	// ImagePattern is a regular expression matched against the whole image references of the containers, e.g.,
+	// "^quay.io/vendor/foo:(?P<tag>[^@]+)$". Its named capture groups can be used in the templates of the variants.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ImagePattern string `json:"imagePattern"`
+
+	// Variants are the single-architecture images the matching image references can be rewritten to.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=architecture
+	Variants []ImageVariant `json:"variants"`
+}
+
+// ImageVariant is the template of the image reference of the variant of an image for an architecture.
+type ImageVariant struct {
+	// Architecture is the architecture supported by the variant.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=arm64;amd64;ppc64le;s390x
+	Architecture string `json:"architecture"`
+
+	// Image is a Go template producing the image reference of the variant, e.g., "quay.io/vendor/foo:{{ .tag }}-arm64".
+	// The named capture groups of the imagePattern and the architecture, as {{ .architecture }}, can be used.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+}
+
+// ImageRewriteRule maps the image references of the images that are published as separate per-architecture tags or
+// repositories, instead of a manifest list, to their variants. The pod placement controller considers such images as
+// supporting the architectures of their variants and rewrites them to the variant of the architecture chosen for
+// the pods.
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=imagerewriterules,scope=Cluster
+type ImageRewriteRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ImageRewriteRuleSpec `json:"spec,omitempty"`
+}
+
+// Validate checks that the imagePattern of the rule compiles and that the templates of its variants parse and only
+// use the named capture groups of the imagePattern and the architecture.
+func (r *ImageRewriteRule) Validate() error {
+	pattern, err := regexp.Compile(r.Spec.ImagePattern)
+	if err != nil {
+		return fmt.Errorf(".spec.imagePattern is invalid: %w", err)
+	}
+	data := map[string]string{}
+	for _, name := range pattern.SubexpNames() {
+		if name != "" {
+			data[name] = name
+		}
+	}
+	for i, variant := range r.Spec.Variants {
+		tmpl, err := variantTemplate(variant)
+		if err != nil {
+			return fmt.Errorf(".spec.variants[%d].image is invalid: %w", i, err)
+		}
+		data["architecture"] = variant.Architecture
+		if err := tmpl.Execute(io.Discard, data); err != nil {
+			return fmt.Errorf(".spec.variants[%d].image is invalid: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Variants returns the image references of the variants of the image, keyed by architecture, or nil if the image
+// does not match the imagePattern of the rule.
+func (r *ImageRewriteRule) Variants(image string) (map[string]string, error) {
+	pattern, err := regexp.Compile(r.Spec.ImagePattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid imagePattern of the ImageRewriteRule %s: %w", r.Name, err)
+	}
+	match := pattern.FindStringSubmatch(image)
+	if match == nil {
+		return nil, nil
+	}
+	data := map[string]string{}
+	for i, name := range pattern.SubexpNames() {
+		if name != "" {
+			data[name] = match[i]
+		}
+	}
+	variants := make(map[string]string, len(r.Spec.Variants))
+	for _, variant := range r.Spec.Variants {
+		tmpl, err := variantTemplate(variant)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image template of the %s variant of the ImageRewriteRule %s: %w",
+				variant.Architecture, r.Name, err)
+		}
+		data["architecture"] = variant.Architecture
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("unable to render the %s variant of the ImageRewriteRule %s: %w",
+				variant.Architecture, r.Name, err)
+		}
+		variants[variant.Architecture] = buf.String()
+	}
+	return variants, nil
+}
+
+// variantTemplate parses the image template of the variant. Executing it fails on the keys that are not set.
+func variantTemplate(variant ImageVariant) (*template.Template, error) {
+	return template.New(variant.Architecture).Option("missingkey=error").Parse(variant.Image)
+}
+
+//+kubebuilder:object:root=true
+
+// ImageRewriteRuleList contains a list of ImageRewriteRule
+type ImageRewriteRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageRewriteRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ImageRewriteRule{}, &ImageRewriteRuleList{})
+}
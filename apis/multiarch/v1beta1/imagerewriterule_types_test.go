@@ -0,0 +1,114 @@
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestImageRewriteRule_Variants(t *testing.T) {
+	variants := []ImageVariant{
+		{Architecture: "amd64", Image: "quay.io/vendor/foo:{{ .tag }}"},
+		{Architecture: "arm64", Image: "quay.io/vendor/foo:{{ .tag }}-{{ .architecture }}"},
+		{Architecture: "ppc64le", Image: "quay.io/vendor/foo-ppc64le:{{ .tag }}"},
+	}
+	tests := []struct {
+		name    string
+		spec    ImageRewriteRuleSpec
+		image   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "matching image",
+			spec:  ImageRewriteRuleSpec{ImagePattern: "^quay.io/vendor/foo:(?P<tag>[^@]+)$", Variants: variants},
+			image: "quay.io/vendor/foo:1.2",
+			want: map[string]string{
+				"amd64":   "quay.io/vendor/foo:1.2",
+				"arm64":   "quay.io/vendor/foo:1.2-arm64",
+				"ppc64le": "quay.io/vendor/foo-ppc64le:1.2",
+			},
+		},
+		{
+			name:  "image not matching",
+			spec:  ImageRewriteRuleSpec{ImagePattern: "^quay.io/vendor/foo:(?P<tag>[^@]+)$", Variants: variants},
+			image: "quay.io/vendor/bar:1.2",
+		},
+		{
+			name:    "invalid pattern",
+			spec:    ImageRewriteRuleSpec{ImagePattern: "^quay.io/vendor/foo:(?P<tag>[^@]+$", Variants: variants},
+			image:   "quay.io/vendor/foo:1.2",
+			wantErr: true,
+		},
+		{
+			name:    "template using an unknown group",
+			spec:    ImageRewriteRuleSpec{ImagePattern: "^quay.io/vendor/foo:(?P<version>[^@]+)$", Variants: variants},
+			image:   "quay.io/vendor/foo:1.2",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &ImageRewriteRule{ObjectMeta: v1.ObjectMeta{Name: "foo"}, Spec: tt.spec}
+			got, err := rule.Variants(tt.image)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Variants() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Variants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageRewriteRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    ImageRewriteRuleSpec
+		wantErr bool
+	}{
+		{
+			name: "valid rule",
+			spec: ImageRewriteRuleSpec{
+				ImagePattern: "^quay.io/vendor/foo:(?P<tag>[^@]+)$",
+				Variants: []ImageVariant{
+					{Architecture: "arm64", Image: "quay.io/vendor/foo:{{ .tag }}-{{ .architecture }}"},
+				},
+			},
+		},
+		{
+			name: "invalid pattern",
+			spec: ImageRewriteRuleSpec{
+				ImagePattern: "^quay.io/vendor/foo:(?P<tag>[^@]+$",
+				Variants:     []ImageVariant{{Architecture: "arm64", Image: "quay.io/vendor/foo:{{ .tag }}"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "template not parsing",
+			spec: ImageRewriteRuleSpec{
+				ImagePattern: "^quay.io/vendor/foo:(?P<tag>[^@]+)$",
+				Variants:     []ImageVariant{{Architecture: "arm64", Image: "quay.io/vendor/foo:{{ .tag }"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "template using an unknown group",
+			spec: ImageRewriteRuleSpec{
+				ImagePattern: "^quay.io/vendor/foo:(?P<version>[^@]+)$",
+				Variants:     []ImageVariant{{Architecture: "arm64", Image: "quay.io/vendor/foo:{{ .tag }}"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &ImageRewriteRule{ObjectMeta: v1.ObjectMeta{Name: "foo"}, Spec: tt.spec}
+			if err := rule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
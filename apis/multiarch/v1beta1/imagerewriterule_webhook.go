@@ -0,0 +1,60 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"errors"
+
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-multiarch-openshift-io-v1beta1-imagerewriterule,mutating=false,failurePolicy=fail,sideEffects=None,groups=multiarch.openshift.io,resources=imagerewriterules,verbs=create;update,versions=v1beta1,name=validate-imagerewriterule.multiarch.openshift.io,admissionReviewVersions=v1
+
+func (r *ImageRewriteRule) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&ImageRewriteRuleValidator{}).
+		Complete()
+}
+
+// ImageRewriteRuleValidator rejects the ImageRewriteRules whose imagePattern does not compile or whose variants
+// templates cannot be rendered with the named capture groups of the imagePattern.
+// +kubebuilder:object:generate=false
+type ImageRewriteRuleValidator struct{}
+
+func (v *ImageRewriteRuleValidator) ValidateCreate(_ context.Context, obj runtime.Object) (warnings admission.Warnings, err error) {
+	return nil, v.validate(obj)
+}
+
+func (v *ImageRewriteRuleValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (warnings admission.Warnings, err error) {
+	return nil, v.validate(newObj)
+}
+
+func (v *ImageRewriteRuleValidator) ValidateDelete(_ context.Context, _ runtime.Object) (warnings admission.Warnings, err error) {
+	return nil, nil
+}
+
+func (v *ImageRewriteRuleValidator) validate(obj runtime.Object) error {
+	rule, ok := obj.(*ImageRewriteRule)
+	if !ok {
+		return errors.New("not an ImageRewriteRule")
+	}
+	return rule.Validate()
+}
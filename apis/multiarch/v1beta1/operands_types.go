@@ -0,0 +1,325 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"net"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reservedOperandEnvVars are the environment variables set by the operator that cannot be overridden.
+var reservedOperandEnvVars = []string{"NAMESPACE", "NODE_NAME"}
+
+// Operands customizes the workloads of the operand components. The unset fields keep the defaults of the operator.
+type Operands struct {
+	// PodPlacementController customizes the Deployment of the pod placement controller. It defaults to 2 replicas.
+	// +optional
+	PodPlacementController *OperandDeploymentConfig `json:"podPlacementController,omitempty"`
+
+	// PodPlacementWebhook customizes the Deployment of the pod placement webhook. It defaults to 3 replicas.
+	// +optional
+	PodPlacementWebhook *OperandDeploymentConfig `json:"podPlacementWebhook,omitempty"`
+
+	// PodPlacementWebhookAutoscaling scales the Deployment of the pod placement webhook horizontally. When it is set,
+	// the replicas of the podPlacementWebhook are ignored.
+	// +optional
+	PodPlacementWebhookAutoscaling *WebhookAutoscaling `json:"podPlacementWebhookAutoscaling,omitempty"`
+
+	// ENoExecEventController customizes the Deployment of the controller of the ExecFormatErrorMonitor plugin.
+	// It defaults to 2 replicas.
+	// +optional
+	ENoExecEventController *OperandDeploymentConfig `json:"enoexecEventController,omitempty"`
+
+	// ENoExecEventDaemon customizes the DaemonSet of the daemon of the ExecFormatErrorMonitor plugin.
+	// +optional
+	ENoExecEventDaemon *OperandConfig `json:"enoexecEventDaemon,omitempty"`
+
+	// NetworkPolicies restricts the network traffic of the pods of the operand components with NetworkPolicies.
+	// +optional
+	NetworkPolicies *OperandNetworkPolicies `json:"networkPolicies,omitempty"`
+}
+
+// OperandConfig customizes the pods of an operand component.
+type OperandConfig struct {
+	// Resources replaces the resource requests and limits of the container of the component.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector constrains the pods of the component to the nodes with the given labels, in addition to the
+	// node affinity of the operator restricting them to the supported architectures.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are added to the pods of the component.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PriorityClassName replaces the priority class of the pods of the component.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Env sets additional environment variables in the container of the component, e.g., the HTTP proxy settings.
+	// The variables with the same name as the ones set by the operator replace them, except for NAMESPACE and
+	// NODE_NAME.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+}
+
+// OperandDeploymentConfig customizes the Deployment of an operand component.
+type OperandDeploymentConfig struct {
+	OperandConfig `json:",inline"`
+
+	// Replicas is the number of replicas of the Deployment of the component.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// TopologySpreadConstraints replaces the topology spread constraints of the pods of the component. The
+	// constraints without a label selector select the pods of the component.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+// AutoscalingMetric is the metric the pod placement webhook is scaled on.
+// +kubebuilder:validation:Enum=CPU;ResponseTime
+type AutoscalingMetric string
+
+const (
+	// AutoscalingMetricCPU scales the webhook on the average CPU utilization of its pods.
+	AutoscalingMetricCPU AutoscalingMetric = "CPU"
+	// AutoscalingMetricResponseTime scales the webhook on the mto_ppo_wh_response_time_seconds_average metric of its
+	// pods, i.e., their average response time in the last two minutes. The metric is derived from the
+	// mto_ppo_wh_response_time_seconds histogram and has to be served by the custom metrics API, e.g., by the
+	// Prometheus Adapter with the rule in config/prometheus/adapter/rules.yaml.
+	AutoscalingMetricResponseTime AutoscalingMetric = "ResponseTime"
+)
+
+// WebhookAutoscaling configures the HorizontalPodAutoscaler of the pod placement webhook.
+type WebhookAutoscaling struct {
+	// MinReplicas is the lower limit of the replicas of the webhook. It defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit of the replicas of the webhook.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Metric is the metric the webhook is scaled on.
+	// +kubebuilder:default=CPU
+	// +optional
+	Metric AutoscalingMetric `json:"metric,omitempty"`
+
+	// TargetCPUUtilizationPercentage is the target average CPU utilization of the pods of the webhook, as a
+	// percentage of their CPU requests, when the metric is CPU. It defaults to 80.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// TargetResponseTime is the target average response time of the pods of the webhook, when the metric is
+	// ResponseTime. It defaults to 100ms.
+	// +optional
+	TargetResponseTime *metav1.Duration `json:"targetResponseTime,omitempty"`
+}
+
+// OperandNetworkPolicies configures the NetworkPolicies of the operand components.
+type OperandNetworkPolicies struct {
+	// Enabled deploys a NetworkPolicy for each operand component. The webhook accepts connections on its serving port
+	// from the endpoints of the API server and, on OpenShift, from the host network, the metrics ports accept
+	// connections from the monitoring namespaces, and the egress is restricted to the DNS, the endpoints of the API
+	// server and, for the components inspecting the images, the registries.
+	Enabled bool `json:"enabled"`
+
+	// MetricsNamespaceSelector selects the namespaces allowed to scrape the metrics of the operand components.
+	// It defaults to the namespaces labeled with network.openshift.io/policy-group=monitoring.
+	// +optional
+	MetricsNamespaceSelector *metav1.LabelSelector `json:"metricsNamespaceSelector,omitempty"`
+
+	// RegistryCIDRs restricts the egress of the pod placement controller and webhook towards the image registries
+	// to the given CIDRs. When it is empty, the egress towards any destination is allowed.
+	// +listType=set
+	// +optional
+	RegistryCIDRs []string `json:"registryCIDRs,omitempty"`
+}
+
+// GetPodPlacementController returns the customization of the pod placement controller, or nil if none is set.
+func (o *Operands) GetPodPlacementController() *OperandDeploymentConfig {
+	if o == nil {
+		return nil
+	}
+	return o.PodPlacementController
+}
+
+// GetPodPlacementWebhook returns the customization of the pod placement webhook, or nil if none is set.
+func (o *Operands) GetPodPlacementWebhook() *OperandDeploymentConfig {
+	if o == nil {
+		return nil
+	}
+	return o.PodPlacementWebhook
+}
+
+// GetPodPlacementWebhookAutoscaling returns the autoscaling of the pod placement webhook, or nil if it is disabled.
+func (o *Operands) GetPodPlacementWebhookAutoscaling() *WebhookAutoscaling {
+	if o == nil {
+		return nil
+	}
+	return o.PodPlacementWebhookAutoscaling
+}
+
+// GetENoExecEventController returns the customization of the ENoExecEvent controller, or nil if none is set.
+func (o *Operands) GetENoExecEventController() *OperandDeploymentConfig {
+	if o == nil {
+		return nil
+	}
+	return o.ENoExecEventController
+}
+
+// GetNetworkPolicies returns the configuration of the NetworkPolicies of the operand components, or nil if they
+// are disabled.
+func (o *Operands) GetNetworkPolicies() *OperandNetworkPolicies {
+	if o == nil || o.NetworkPolicies == nil || !o.NetworkPolicies.Enabled {
+		return nil
+	}
+	return o.NetworkPolicies
+}
+
+// GetENoExecEventDaemon returns the customization of the ENoExecEvent daemon, or nil if none is set.
+func (o *Operands) GetENoExecEventDaemon() *OperandConfig {
+	if o == nil {
+		return nil
+	}
+	return o.ENoExecEventDaemon
+}
+
+// Validate returns an error if the customization of any operand component is invalid.
+func (o *Operands) Validate() error {
+	if o == nil {
+		return nil
+	}
+	if err := o.PodPlacementController.validate(".spec.operands.podPlacementController"); err != nil {
+		return err
+	}
+	if err := o.PodPlacementWebhook.validate(".spec.operands.podPlacementWebhook"); err != nil {
+		return err
+	}
+	if err := o.PodPlacementWebhookAutoscaling.validate(".spec.operands.podPlacementWebhookAutoscaling"); err != nil {
+		return err
+	}
+	if err := o.ENoExecEventController.validate(".spec.operands.enoexecEventController"); err != nil {
+		return err
+	}
+	if err := o.ENoExecEventDaemon.validate(".spec.operands.enoexecEventDaemon"); err != nil {
+		return err
+	}
+	return o.NetworkPolicies.validate(".spec.operands.networkPolicies")
+}
+
+func (n *OperandNetworkPolicies) validate(path string) error {
+	if n == nil {
+		return nil
+	}
+	if n.MetricsNamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(n.MetricsNamespaceSelector); err != nil {
+			return fmt.Errorf("%s.metricsNamespaceSelector is invalid: %w", path, err)
+		}
+	}
+	for i, cidr := range n.RegistryCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("%s.registryCIDRs[%d] is not a valid CIDR: %w", path, i, err)
+		}
+	}
+	return nil
+}
+
+func (c *OperandDeploymentConfig) validate(path string) error {
+	if c == nil {
+		return nil
+	}
+	if c.Replicas != nil && *c.Replicas < 1 {
+		return fmt.Errorf("%s.replicas must be at least 1", path)
+	}
+	for i, constraint := range c.TopologySpreadConstraints {
+		switch {
+		case constraint.MaxSkew < 1:
+			return fmt.Errorf("%s.topologySpreadConstraints[%d].maxSkew must be at least 1", path, i)
+		case constraint.TopologyKey == "":
+			return fmt.Errorf("%s.topologySpreadConstraints[%d].topologyKey must be set", path, i)
+		case constraint.WhenUnsatisfiable != corev1.DoNotSchedule && constraint.WhenUnsatisfiable != corev1.ScheduleAnyway:
+			return fmt.Errorf("%s.topologySpreadConstraints[%d].whenUnsatisfiable must be %s or %s", path, i,
+				corev1.DoNotSchedule, corev1.ScheduleAnyway)
+		}
+	}
+	return c.OperandConfig.validate(path)
+}
+
+func (a *WebhookAutoscaling) validate(path string) error {
+	if a == nil {
+		return nil
+	}
+	switch {
+	case a.MaxReplicas < 1:
+		return fmt.Errorf("%s.maxReplicas must be at least 1", path)
+	case a.MinReplicas != nil && *a.MinReplicas < 1:
+		return fmt.Errorf("%s.minReplicas must be at least 1", path)
+	case a.MinReplicas != nil && *a.MinReplicas > a.MaxReplicas:
+		return fmt.Errorf("%s.minReplicas must be less than or equal to maxReplicas", path)
+	case a.TargetCPUUtilizationPercentage != nil && *a.TargetCPUUtilizationPercentage < 1:
+		return fmt.Errorf("%s.targetCPUUtilizationPercentage must be at least 1", path)
+	case a.TargetResponseTime != nil && a.TargetResponseTime.Duration <= 0:
+		return fmt.Errorf("%s.targetResponseTime must be positive", path)
+	}
+	return nil
+}
+
+func (c *OperandConfig) validate(path string) error {
+	if c == nil {
+		return nil
+	}
+	if c.Resources != nil {
+		for name, limit := range c.Resources.Limits {
+			if request, ok := c.Resources.Requests[name]; ok && request.Cmp(limit) > 0 {
+				return fmt.Errorf("%s.resources.requests[%s] must be less than or equal to the limit", path, name)
+			}
+		}
+	}
+	for i, toleration := range c.Tolerations {
+		if toleration.Operator == corev1.TolerationOpExists && toleration.Value != "" {
+			return fmt.Errorf("%s.tolerations[%d].value must be empty when the operator is Exists", path, i)
+		}
+		if toleration.Key == "" && toleration.Operator != corev1.TolerationOpExists {
+			return fmt.Errorf("%s.tolerations[%d].operator must be Exists when the key is empty", path, i)
+		}
+	}
+	seen := map[string]struct{}{}
+	for i, env := range c.Env {
+		if env.Name == "" {
+			return fmt.Errorf("%s.env[%d].name must be set", path, i)
+		}
+		if slices.Contains(reservedOperandEnvVars, env.Name) {
+			return fmt.Errorf("%s.env[%d].name %q is reserved", path, i, env.Name)
+		}
+		if _, ok := seen[env.Name]; ok {
+			return fmt.Errorf("%s.env[%d].name %q is duplicated", path, i, env.Name)
+		}
+		seen[env.Name] = struct{}{}
+	}
+	return nil
+}
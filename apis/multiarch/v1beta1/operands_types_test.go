@@ -0,0 +1,162 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestOperands_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		operands *Operands
+		wantErr  bool
+	}{
+		{
+			name: "nil operands",
+		},
+		{
+			name: "valid operands",
+			operands: &Operands{
+				PodPlacementWebhook: &OperandDeploymentConfig{
+					OperandConfig: OperandConfig{
+						Resources: &corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+							Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+						},
+						Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+						Env:         []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}},
+					},
+					Replicas: ptr.To(int32(5)),
+					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+						MaxSkew:           1,
+						TopologyKey:       "topology.kubernetes.io/zone",
+						WhenUnsatisfiable: corev1.ScheduleAnyway,
+					}},
+				},
+				ENoExecEventDaemon: &OperandConfig{NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""}},
+			},
+		},
+		{
+			name: "valid webhook autoscaling",
+			operands: &Operands{
+				PodPlacementWebhookAutoscaling: &WebhookAutoscaling{
+					MinReplicas:        ptr.To(int32(3)),
+					MaxReplicas:        10,
+					Metric:             AutoscalingMetricResponseTime,
+					TargetResponseTime: &metav1.Duration{Duration: 50 * time.Millisecond},
+				},
+			},
+		},
+		{
+			name: "webhook autoscaling with min replicas greater than max replicas",
+			operands: &Operands{
+				PodPlacementWebhookAutoscaling: &WebhookAutoscaling{MinReplicas: ptr.To(int32(5)), MaxReplicas: 3},
+			},
+			wantErr: true,
+		},
+		{
+			name: "webhook autoscaling without max replicas",
+			operands: &Operands{
+				PodPlacementWebhookAutoscaling: &WebhookAutoscaling{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero replicas",
+			operands: &Operands{
+				PodPlacementController: &OperandDeploymentConfig{Replicas: ptr.To(int32(0))},
+			},
+			wantErr: true,
+		},
+		{
+			name: "requests greater than the limits",
+			operands: &Operands{
+				ENoExecEventController: &OperandDeploymentConfig{OperandConfig: OperandConfig{
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+					},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid topology spread constraint",
+			operands: &Operands{
+				PodPlacementWebhook: &OperandDeploymentConfig{
+					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+						MaxSkew:           1,
+						WhenUnsatisfiable: corev1.DoNotSchedule,
+					}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "toleration with the Exists operator and a value",
+			operands: &Operands{
+				ENoExecEventDaemon: &OperandConfig{Tolerations: []corev1.Toleration{
+					{Key: "dedicated", Operator: corev1.TolerationOpExists, Value: "infra"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "reserved environment variable",
+			operands: &Operands{
+				ENoExecEventDaemon: &OperandConfig{Env: []corev1.EnvVar{{Name: "NODE_NAME", Value: "node"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated environment variable",
+			operands: &Operands{
+				PodPlacementController: &OperandDeploymentConfig{OperandConfig: OperandConfig{Env: []corev1.EnvVar{
+					{Name: "HTTP_PROXY", Value: "http://proxy:3128"},
+					{Name: "HTTP_PROXY", Value: "http://other-proxy:3128"},
+				}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid network policies",
+			operands: &Operands{NetworkPolicies: &OperandNetworkPolicies{
+				Enabled:       true,
+				RegistryCIDRs: []string{"10.0.0.0/16", "fd00::/64"},
+				MetricsNamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"kubernetes.io/metadata.name": "openshift-monitoring"},
+				},
+			}},
+		},
+		{
+			name: "invalid registry CIDR",
+			operands: &Operands{NetworkPolicies: &OperandNetworkPolicies{
+				Enabled:       true,
+				RegistryCIDRs: []string{"10.0.0.1"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "invalid metrics namespace selector",
+			operands: &Operands{NetworkPolicies: &OperandNetworkPolicies{
+				Enabled: true,
+				MetricsNamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "team", Operator: metav1.LabelSelectorOpIn},
+				}},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.operands.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
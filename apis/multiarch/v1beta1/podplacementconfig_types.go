@@ -43,6 +43,14 @@ type PodPlacementConfigSpec struct {
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=255
 	Priority uint8 `json:"priority,omitempty"`
+
+	// AllowedArchitectures is the list of the architectures the selected pods are allowed to run on. The architectures
+	// supported by the images of a pod, or selected by the user in its nodeSelector or nodeAffinity, are intersected
+	// with it. It overrides the allowedArchitectures of the ClusterPodPlacementConfig. When empty, the one of the
+	// ClusterPodPlacementConfig applies.
+	// +optional
+	// +kubebuilder:validation:items:Enum=arm64;amd64;ppc64le;s390x
+	AllowedArchitectures []string `json:"allowedArchitectures,omitempty"`
 }
 
 // PodPlacementConfig defines the configuration for the architecture aware pod placement operand in a given namespace for a subset of its pods based on the provided labelSelector.
@@ -22,6 +22,7 @@ package v1beta1
 
 import (
 	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -98,6 +99,46 @@ func (in *ClusterPodPlacementConfigSpec) DeepCopyInto(out *ClusterPodPlacementCo
 		*out = new(plugins.Plugins)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SchedulingGateDeadline != nil {
+		in, out := &in.SchedulingGateDeadline, &out.SchedulingGateDeadline
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.InjectedContainerRules != nil {
+		in, out := &in.InjectedContainerRules, &out.InjectedContainerRules
+		*out = make([]InjectedContainerRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.ValidatingPolicy != nil {
+		in, out := &in.ValidatingPolicy, &out.ValidatingPolicy
+		*out = new(ValidatingPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedArchitectures != nil {
+		in, out := &in.AllowedArchitectures, &out.AllowedArchitectures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageDigestPinning != nil {
+		in, out := &in.ImageDigestPinning, &out.ImageDigestPinning
+		*out = new(ImageDigestPinning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GlobalPullSecretRef != nil {
+		in, out := &in.GlobalPullSecretRef, &out.GlobalPullSecretRef
+		*out = new(corev1.SecretReference)
+		**out = **in
+	}
+	if in.CABundleConfigMapRef != nil {
+		in, out := &in.CABundleConfigMapRef, &out.CABundleConfigMapRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Operands != nil {
+		in, out := &in.Operands, &out.Operands
+		*out = new(Operands)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPodPlacementConfigSpec.
@@ -120,6 +161,16 @@ func (in *ClusterPodPlacementConfigStatus) DeepCopyInto(out *ClusterPodPlacement
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Stats != nil {
+		in, out := &in.Stats, &out.Stats
+		*out = new(PodPlacementStats)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeAffinityScoring != nil {
+		in, out := &in.NodeAffinityScoring, &out.NodeAffinityScoring
+		*out = new(NodeAffinityScoringStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPodPlacementConfigStatus.
@@ -132,21 +183,6 @@ func (in *ClusterPodPlacementConfigStatus) DeepCopy() *ClusterPodPlacementConfig
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterPodPlacementConfigValidator) DeepCopyInto(out *ClusterPodPlacementConfigValidator) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPodPlacementConfigValidator.
-func (in *ClusterPodPlacementConfigValidator) DeepCopy() *ClusterPodPlacementConfigValidator {
-	if in == nil {
-		return nil
-	}
-	out := new(ClusterPodPlacementConfigValidator)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ENoExecEvent) DeepCopyInto(out *ENoExecEvent) {
 	*out = *in
@@ -236,6 +272,299 @@ func (in *ENoExecEventStatus) DeepCopy() *ENoExecEventStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageDigestPinning) DeepCopyInto(out *ImageDigestPinning) {
+	*out = *in
+	if in.ExcludedNamespaces != nil {
+		in, out := &in.ExcludedNamespaces, &out.ExcludedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedImagePrefixes != nil {
+		in, out := &in.ExcludedImagePrefixes, &out.ExcludedImagePrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageDigestPinning.
+func (in *ImageDigestPinning) DeepCopy() *ImageDigestPinning {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageDigestPinning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRewriteRule) DeepCopyInto(out *ImageRewriteRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRewriteRule.
+func (in *ImageRewriteRule) DeepCopy() *ImageRewriteRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRewriteRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageRewriteRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRewriteRuleList) DeepCopyInto(out *ImageRewriteRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ImageRewriteRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRewriteRuleList.
+func (in *ImageRewriteRuleList) DeepCopy() *ImageRewriteRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRewriteRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageRewriteRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRewriteRuleSpec) DeepCopyInto(out *ImageRewriteRuleSpec) {
+	*out = *in
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make([]ImageVariant, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRewriteRuleSpec.
+func (in *ImageRewriteRuleSpec) DeepCopy() *ImageRewriteRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRewriteRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVariant) DeepCopyInto(out *ImageVariant) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVariant.
+func (in *ImageVariant) DeepCopy() *ImageVariant {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVariant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectedContainerRule) DeepCopyInto(out *InjectedContainerRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectedContainerRule.
+func (in *InjectedContainerRule) DeepCopy() *InjectedContainerRule {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectedContainerRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeAffinityScoringStatus) DeepCopyInto(out *NodeAffinityScoringStatus) {
+	*out = *in
+	if in.Platforms != nil {
+		in, out := &in.Platforms, &out.Platforms
+		*out = make([]plugins.NodeAffinityScoringPlatformTerm, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAffinityScoringStatus.
+func (in *NodeAffinityScoringStatus) DeepCopy() *NodeAffinityScoringStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeAffinityScoringStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandConfig) DeepCopyInto(out *OperandConfig) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandConfig.
+func (in *OperandConfig) DeepCopy() *OperandConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandDeploymentConfig) DeepCopyInto(out *OperandDeploymentConfig) {
+	*out = *in
+	in.OperandConfig.DeepCopyInto(&out.OperandConfig)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandDeploymentConfig.
+func (in *OperandDeploymentConfig) DeepCopy() *OperandDeploymentConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandDeploymentConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandNetworkPolicies) DeepCopyInto(out *OperandNetworkPolicies) {
+	*out = *in
+	if in.MetricsNamespaceSelector != nil {
+		in, out := &in.MetricsNamespaceSelector, &out.MetricsNamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegistryCIDRs != nil {
+		in, out := &in.RegistryCIDRs, &out.RegistryCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandNetworkPolicies.
+func (in *OperandNetworkPolicies) DeepCopy() *OperandNetworkPolicies {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandNetworkPolicies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Operands) DeepCopyInto(out *Operands) {
+	*out = *in
+	if in.PodPlacementController != nil {
+		in, out := &in.PodPlacementController, &out.PodPlacementController
+		*out = new(OperandDeploymentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodPlacementWebhook != nil {
+		in, out := &in.PodPlacementWebhook, &out.PodPlacementWebhook
+		*out = new(OperandDeploymentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodPlacementWebhookAutoscaling != nil {
+		in, out := &in.PodPlacementWebhookAutoscaling, &out.PodPlacementWebhookAutoscaling
+		*out = new(WebhookAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ENoExecEventController != nil {
+		in, out := &in.ENoExecEventController, &out.ENoExecEventController
+		*out = new(OperandDeploymentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ENoExecEventDaemon != nil {
+		in, out := &in.ENoExecEventDaemon, &out.ENoExecEventDaemon
+		*out = new(OperandConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = new(OperandNetworkPolicies)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Operands.
+func (in *Operands) DeepCopy() *Operands {
+	if in == nil {
+		return nil
+	}
+	out := new(Operands)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodPlacementConfig) DeepCopyInto(out *PodPlacementConfig) {
 	*out = *in
@@ -308,6 +637,11 @@ func (in *PodPlacementConfigSpec) DeepCopyInto(out *PodPlacementConfigSpec) {
 		*out = new(plugins.LocalPlugins)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AllowedArchitectures != nil {
+		in, out := &in.AllowedArchitectures, &out.AllowedArchitectures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodPlacementConfigSpec.
@@ -334,3 +668,94 @@ func (in *PodPlacementConfigStatus) DeepCopy() *PodPlacementConfigStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodPlacementStats) DeepCopyInto(out *PodPlacementStats) {
+	*out = *in
+	if in.TopFailingRegistries != nil {
+		in, out := &in.TopFailingRegistries, &out.TopFailingRegistries
+		*out = make([]RegistryInspectionFailures, len(*in))
+		copy(*out, *in)
+	}
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodPlacementStats.
+func (in *PodPlacementStats) DeepCopy() *PodPlacementStats {
+	if in == nil {
+		return nil
+	}
+	out := new(PodPlacementStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryInspectionFailures) DeepCopyInto(out *RegistryInspectionFailures) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryInspectionFailures.
+func (in *RegistryInspectionFailures) DeepCopy() *RegistryInspectionFailures {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryInspectionFailures)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidatingPolicy) DeepCopyInto(out *ValidatingPolicy) {
+	*out = *in
+	if in.InspectionTimeout != nil {
+		in, out := &in.InspectionTimeout, &out.InspectionTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidatingPolicy.
+func (in *ValidatingPolicy) DeepCopy() *ValidatingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidatingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookAutoscaling) DeepCopyInto(out *WebhookAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetResponseTime != nil {
+		in, out := &in.TargetResponseTime, &out.TargetResponseTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookAutoscaling.
+func (in *WebhookAutoscaling) DeepCopy() *WebhookAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -30,7 +30,10 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/dynamic"
@@ -68,6 +71,7 @@ import (
 	"github.com/openshift/multiarch-tuning-operator/controllers/operator"
 	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement"
 	"github.com/openshift/multiarch-tuning-operator/controllers/podplacementconfig"
+	"github.com/openshift/multiarch-tuning-operator/pkg/image"
 	"github.com/openshift/multiarch-tuning-operator/pkg/informers/clusterpodplacementconfig"
 	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
 )
@@ -87,6 +91,7 @@ var (
 	certDir,
 	globalPullSecretNamespace,
 	globalPullSecretName,
+	operatorTLSMode,
 	registryCertificatesConfigMapName string
 	enableLeaderElection,
 	enableClusterPodPlacementConfigOperandWebHook,
@@ -113,12 +118,20 @@ func main() {
 	}
 
 	// Build the leader election ID deterministically and based on the flags
-	leaderID := "208d7abd.multiarch.openshift.io"
+	leaderID := utils.LeaderElectionID
 	if enableOperator {
 		leaderID = fmt.Sprintf("operator-%s", leaderID)
+		// The operator watches the EndpointSlices of the API server only: the NetworkPolicies of the operand components
+		// follow its endpoints.
+		cacheOpts.ByObject = map[client.Object]cache.ByObject{
+			&discoveryv1.EndpointSlice{}: {
+				Namespaces: map[string]cache.Config{metav1.NamespaceDefault: {}},
+				Label:      labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: "kubernetes"}),
+			},
+		}
 	}
 	if enableClusterPodPlacementConfigOperandControllers {
-		leaderID = fmt.Sprintf("ppc-controllers-%s", leaderID)
+		leaderID = utils.PodPlacementControllerLeaderElectionID
 		// We need to watch the pods with the status.phase equal to Pending to be able to update the nodeAffinity.
 		// We can discard the other pods because they are already scheduled.
 		cacheOpts.ByObject = map[client.Object]cache.ByObject{
@@ -218,7 +231,7 @@ func RunOperator(mgr ctrl.Manager) {
 	gvk, _ := apiutil.GVKForObject(&multiarchv1beta1.ClusterPodPlacementConfig{}, mgr.GetScheme())
 
 	// Set up the reconciler
-	must((&operator.ClusterPodPlacementConfigReconciler{
+	reconciler := &operator.ClusterPodPlacementConfigReconciler{
 		Client:        mgr.GetClient(),
 		Scheme:        mgr.GetScheme(),
 		ClientSet:     clientset,
@@ -234,23 +247,51 @@ func RunOperator(mgr ctrl.Manager) {
 			},
 			clock.RealClock{},
 		),
-	}).SetupWithManager(mgr), unableToCreateController, controllerKey, "ClusterPodPlacementConfig")
+		OperatorTLSMode: multiarchv1beta1.TLSMode(operatorTLSMode),
+	}
+	must(reconciler.SetupWithManager(mgr), unableToCreateController, controllerKey, "ClusterPodPlacementConfig")
+
+	// The serving certificates of the operator are ensured before the manager starts, independently of the
+	// ClusterPodPlacementConfig: it cannot be created until the validating webhook of the operator serves.
+	// The cache of the manager is not started yet: the bootstrap reads and writes through a direct client.
+	directClient, err := client.New(config, client.Options{Scheme: mgr.GetScheme()})
+	must(err, "unable to create the client for the bootstrap of the serving certificates")
+	bootstrapReconciler := *reconciler
+	bootstrapReconciler.Client = directClient
+	must(bootstrapReconciler.BootstrapOperatorServingCertificates(context.Background(), certDir),
+		"unable to bootstrap the serving certificates of the operator")
+	must(mgr.Add(operator.NewOperatorServingCertificatesRotator(reconciler)),
+		unableToAddRunnable, runnableKey, "OperatorServingCertificatesRotator")
 	must((&multiarchv1beta1.ClusterPodPlacementConfig{}).SetupWebhookWithManager(mgr), unableToCreateController,
 		controllerKey, "ClusterPodPlacementConfigConversionWebhook")
+	must((&multiarchv1beta1.ImageRewriteRule{}).SetupWebhookWithManager(mgr), unableToCreateController,
+		controllerKey, "ImageRewriteRuleValidatingWebhook")
 }
 
 func RunClusterPodPlacementConfigOperandControllers(mgr ctrl.Manager) {
 	config := ctrl.GetConfigOrDie()
 	clientset := kubernetes.NewForConfigOrDie(config)
 
-	must((&podplacement.PodReconciler{
-		Client:    mgr.GetClient(),
-		Scheme:    mgr.GetScheme(),
-		ClientSet: clientset,
-		Recorder:  mgr.GetEventRecorderFor(utils.OperatorName),
-	}).SetupWithManager(mgr),
+	statusReporter := podplacement.NewCPPCStatusReporter(mgr.GetClient(), mgr.GetAPIReader(),
+		image.FacadeSingleton().Stats)
+	must(mgr.Add(statusReporter), unableToAddRunnable, runnableKey, "CPPCStatusReporter")
+
+	podReconciler := &podplacement.PodReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		ClientSet:      clientset,
+		Recorder:       mgr.GetEventRecorderFor(utils.OperatorName),
+		StatusReporter: statusReporter,
+	}
+	must(podReconciler.SetupWithManager(mgr),
 		unableToCreateController, controllerKey, "PodReconciler")
 
+	must(mgr.Add(podplacement.NewGatedPodsWatchdog(podReconciler, mgr.GetAPIReader())),
+		unableToAddRunnable, runnableKey, "GatedPodsWatchdog")
+
+	must(mgr.Add(podplacement.NewNodeAffinityWeightsComputer(mgr.GetClient(), mgr.GetAPIReader())),
+		unableToAddRunnable, runnableKey, "NodeAffinityWeightsComputer")
+
 	must(mgr.Add(podplacement.NewGlobalPullSecretSyncer(clientset, globalPullSecretNamespace, globalPullSecretName)),
 		unableToAddRunnable, runnableKey, "GlobalPullSecretSyncer")
 }
@@ -268,9 +309,17 @@ func RunClusterPodPlacementConfigOperandWebHook(mgr ctrl.Manager) {
 		}
 		ants.Release()
 	})
-	handler := podplacement.NewPodSchedulingGateMutatingWebHook(mgr.GetClient(), clientset, mgr.GetScheme(),
-		mgr.GetEventRecorderFor(utils.OperatorName), pool)
+	controllerLiveness := podplacement.NewControllerLivenessChecker(clientset, utils.Namespace(),
+		utils.PodPlacementControllerLeaderElectionID)
+	must(mgr.Add(controllerLiveness), unableToAddRunnable, runnableKey, "ControllerLivenessChecker")
+	// The global pull secret is used by the EmulationFallback plugin to inspect the images at admission time.
+	must(mgr.Add(podplacement.NewGlobalPullSecretSyncer(clientset, globalPullSecretNamespace, globalPullSecretName)),
+		unableToAddRunnable, runnableKey, "GlobalPullSecretSyncer")
+	handler := podplacement.NewPodSchedulingGateMutatingWebHook(mgr.GetClient(), mgr.GetAPIReader(), clientset,
+		mgr.GetScheme(), mgr.GetEventRecorderFor(utils.OperatorName), pool, controllerLiveness)
 	mgr.GetWebhookServer().Register("/add-pod-scheduling-gate", &webhook.Admission{Handler: handler})
+	mgr.GetWebhookServer().Register(utils.PodValidatingWebhookPath, &webhook.Admission{
+		Handler: podplacement.NewPodArchitecturePolicyValidatingWebHook(mgr.GetClient(), clientset, mgr.GetScheme())})
 }
 
 func RunPodPlacementConfigWebHook(mgr ctrl.Manager) {
@@ -297,6 +346,11 @@ func validateFlags() error {
 	if btoi(enableOperator)+btoi(enableClusterPodPlacementConfigOperandControllers)+btoi(enableClusterPodPlacementConfigOperandWebHook)+btoi(enableENoExecEventControllers) > 1 {
 		return errors.New("only one of the following flags can be set: --enable-operator, --enable-ppc-controllers, --enable-ppc-webhook, --enable-enoexec-event-controllers")
 	}
+	switch multiarchv1beta1.TLSMode(operatorTLSMode) {
+	case "", multiarchv1beta1.TLSModeServiceCA, multiarchv1beta1.TLSModeCertManager, multiarchv1beta1.TLSModeSelfManaged:
+	default:
+		return fmt.Errorf("invalid value for --operator-tls-mode: %q", operatorTLSMode)
+	}
 	return nil
 }
 
@@ -307,6 +361,8 @@ func bindFlags() {
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&certDir, "cert-dir", "/var/run/manager/tls", "The directory where the TLS certs are stored")
+	flag.StringVar(&operatorTLSMode, "operator-tls-mode", "", "The TLS mode of the serving certificates of the operator "+
+		"when the ClusterPodPlacementConfig does not set one. Only SelfManaged makes the operator issue them")
 	// TODO: Change the defaults to match a local secret; the OCP specific settings will be provided by the operator
 	flag.StringVar(&globalPullSecretNamespace, "global-pull-secret-namespace", "openshift-config", "The namespace where the global pull secret is stored")
 	flag.StringVar(&globalPullSecretName, "global-pull-secret-name", "pull-secret", "The name of the global pull secret")
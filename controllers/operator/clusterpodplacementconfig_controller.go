@@ -22,7 +22,11 @@ import (
 
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,9 +36,13 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/openshift/library-go/pkg/operator/events"
 
@@ -44,6 +52,7 @@ import (
 	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
 	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
 	multiarchv1beta1 "github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/servingcerts"
 	"github.com/openshift/multiarch-tuning-operator/pkg/testing/framework"
 	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
 )
@@ -55,6 +64,9 @@ type ClusterPodPlacementConfigReconciler struct {
 	Scheme        *runtime.Scheme
 	ClientSet     *kubernetes.Clientset
 	Recorder      events.Recorder
+	// OperatorTLSMode is the TLS mode of the serving certificates of the operator when the ClusterPodPlacementConfig
+	// does not set one, see isOperatorTLSModeSelfManaged.
+	OperatorTLSMode multiarchv1beta1.TLSMode
 }
 
 const (
@@ -73,6 +85,7 @@ const (
 //+kubebuilder:rbac:groups=multiarch.openshift.io,resources=clusterpodplacementconfigs/finalizers,verbs=update
 //+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;update;patch;create;delete;list;watch
 //+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations/status,verbs=get
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;update;patch;create;delete;list;watch
 
 //+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;update
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;update;patch;create;delete
@@ -82,6 +95,10 @@ const (
 //+kubebuilder:rbac:groups=core,resources=services/status,verbs=get
 //+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=list;watch
 
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;update;patch;create;delete
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts/status,verbs=get
@@ -101,6 +118,9 @@ const (
 
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;update;patch;create;delete
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;update;patch;create;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;update;patch;create
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=issuers;certificates,verbs=get;list;watch;update;patch;create;delete
 
 // Reconcile reconciles the ClusterPodPlacementConfig object against the actual cluster state, and then
 // perform operations to make the cluster state reflect the state specified by
@@ -186,7 +206,20 @@ func (r *ClusterPodPlacementConfigReconciler) Reconcile(ctx context.Context, req
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, r.reconcile(ctx, clusterPodPlacementConfig)
+	// The operand objects are adapted to the cluster they run on, e.g., OpenShift or clusters serving the cert-manager APIs
+	platform, err := r.operandPlatform(ctx, clusterPodPlacementConfig)
+	if err != nil {
+		log.Error(err, "Unable to resolve the operand platform")
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcile(ctx, clusterPodPlacementConfig, platform); err != nil {
+		return ctrl.Result{}, err
+	}
+	if platform.tlsMode == multiarchv1beta1.TLSModeSelfManaged {
+		// The reconciliation is periodically repeated to rotate the self-managed serving certificates before they expire.
+		return ctrl.Result{RequeueAfter: servingCertificatesResyncPeriod}, nil
+	}
+	return ctrl.Result{}, nil
 }
 
 func (r *ClusterPodPlacementConfigReconciler) ensureNamespaceLabels(ctx context.Context) error {
@@ -223,8 +256,13 @@ func (r *ClusterPodPlacementConfigReconciler) ensureNamespaceLabels(ctx context.
 // that may otherwise be restricted. For more details, see following bugs:
 // https://issues.redhat.com/browse/OCPBUGS-55013
 // https://issues.redhat.com/browse/MULTIARCH-5405
-func (r *ClusterPodPlacementConfigReconciler) getCorrectHostmountAnyUIDSCC(ctx context.Context) (string, *corev1.SELinuxOptions, error) {
+// The SCCs only exist on OpenShift: no SCC is returned for the other clusters.
+func (r *ClusterPodPlacementConfigReconciler) getCorrectHostmountAnyUIDSCC(ctx context.Context, isOpenShift bool) (string, *corev1.SELinuxOptions, error) {
 	log := ctrllog.FromContext(ctx)
+	if !isOpenShift {
+		log.V(1).Info("The cluster is not OpenShift, no hostmount SCC is required")
+		return "", nil, nil
+	}
 	log.V(1).Info("Ensuring using the correct hostmount scc for podplacementconfig")
 
 	minor, err := framework.GetClusterMinorVersion(r.ClientSet)
@@ -292,7 +330,7 @@ func (r *ClusterPodPlacementConfigReconciler) dependentsStatusToClusterPodPlacem
 		isDeploymentAvailable(podPlacementController), isDeploymentAvailable(podPlacementWebhook),
 		isDeploymentUpToDate(podPlacementController), isDeploymentUpToDate(podPlacementWebhook),
 		// err == nil means the MutatingWebhookConfiguration is available
-		err == nil, !config.DeletionTimestamp.IsZero())
+		err == nil, config.Spec.Paused, !config.DeletionTimestamp.IsZero())
 	return nil
 }
 
@@ -316,14 +354,30 @@ func (r *ClusterPodPlacementConfigReconciler) handleDelete(ctx context.Context,
 			NamespacedTypedClient: r.ClientSet.AdmissionregistrationV1().MutatingWebhookConfigurations(),
 			ObjName:               utils.PodMutatingWebhookConfigurationName,
 		},
+		{
+			NamespacedTypedClient: r.ClientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations(),
+			ObjName:               utils.PodValidatingWebhookConfigurationName,
+		},
 		{
 			NamespacedTypedClient: r.ClientSet.CoreV1().Services(utils.Namespace()),
 			ObjName:               utils.PodPlacementWebhookName,
 		},
+		{
+			NamespacedTypedClient: r.ClientSet.AutoscalingV2().HorizontalPodAutoscalers(utils.Namespace()),
+			ObjName:               utils.PodPlacementWebhookName,
+		},
+		{
+			NamespacedTypedClient: r.ClientSet.NetworkingV1().NetworkPolicies(utils.Namespace()),
+			ObjName:               utils.PodPlacementWebhookName,
+		},
 		{
 			NamespacedTypedClient: r.ClientSet.AppsV1().Deployments(utils.Namespace()),
 			ObjName:               utils.PodPlacementWebhookName,
 		},
+		{
+			NamespacedTypedClient: r.ClientSet.PolicyV1().PodDisruptionBudgets(utils.Namespace()),
+			ObjName:               utils.PodPlacementWebhookName,
+		},
 		{
 			NamespacedTypedClient: r.ClientSet.RbacV1().ClusterRoles(),
 			ObjName:               utils.PodPlacementWebhookName,
@@ -332,6 +386,14 @@ func (r *ClusterPodPlacementConfigReconciler) handleDelete(ctx context.Context,
 			NamespacedTypedClient: r.ClientSet.RbacV1().ClusterRoleBindings(),
 			ObjName:               utils.PodPlacementWebhookName,
 		},
+		{
+			NamespacedTypedClient: r.ClientSet.RbacV1().Roles(utils.Namespace()),
+			ObjName:               utils.PodPlacementWebhookName,
+		},
+		{
+			NamespacedTypedClient: r.ClientSet.RbacV1().RoleBindings(utils.Namespace()),
+			ObjName:               utils.PodPlacementWebhookName,
+		},
 		{
 			NamespacedTypedClient: r.ClientSet.CoreV1().ServiceAccounts(utils.Namespace()),
 			ObjName:               utils.PodPlacementWebhookName,
@@ -339,7 +401,10 @@ func (r *ClusterPodPlacementConfigReconciler) handleDelete(ctx context.Context,
 	}
 	log.Info("Deleting the pod placement operand's resources")
 	// NOTE: err aggregates non-nil errors, excluding NotFound errors
-	if err := utils.DeleteResources(ctx, objsToDelete); err != nil {
+	if err := errorutils.NewAggregate([]error{
+		utils.DeleteResources(ctx, objsToDelete),
+		r.deleteGlobalPullSecretReaders(ctx, ""),
+	}); err != nil {
 		log.Error(err, "Unable to delete resources")
 		return err
 	}
@@ -411,10 +476,18 @@ func (r *ClusterPodPlacementConfigReconciler) handleDelete(ctx context.Context,
 			NamespacedTypedClient: r.ClientSet.CoreV1().Services(utils.Namespace()),
 			ObjName:               utils.PodPlacementControllerName,
 		},
+		{
+			NamespacedTypedClient: r.ClientSet.NetworkingV1().NetworkPolicies(utils.Namespace()),
+			ObjName:               utils.PodPlacementControllerName,
+		},
 		{
 			NamespacedTypedClient: r.ClientSet.AppsV1().Deployments(utils.Namespace()),
 			ObjName:               utils.PodPlacementControllerName,
 		},
+		{
+			NamespacedTypedClient: r.ClientSet.PolicyV1().PodDisruptionBudgets(utils.Namespace()),
+			ObjName:               utils.PodPlacementControllerName,
+		},
 		{
 			NamespacedTypedClient: r.ClientSet.RbacV1().ClusterRoles(),
 			ObjName:               utils.PodPlacementControllerName,
@@ -572,6 +645,10 @@ func (r *ClusterPodPlacementConfigReconciler) handleEnoexecDelete(ctx context.Co
 			NamespacedTypedClient: r.ClientSet.CoreV1().Services(utils.Namespace()),
 			ObjName:               utils.EnoexecControllerName,
 		},
+		{
+			NamespacedTypedClient: r.ClientSet.NetworkingV1().NetworkPolicies(utils.Namespace()),
+			ObjName:               utils.EnoexecControllerName,
+		},
 	}
 
 	if utils.IsResourceAvailable(ctx, r.DynamicClient, monitoringv1.SchemeGroupVersion.WithResource("servicemonitors")) {
@@ -604,7 +681,8 @@ func (r *ClusterPodPlacementConfigReconciler) handleEnoexecDelete(ctx context.Co
 }
 
 // reconcile reconciles the ClusterPodPlacementConfig operand's resources.
-func (r *ClusterPodPlacementConfigReconciler) reconcile(ctx context.Context, clusterPodPlacementConfig *multiarchv1beta1.ClusterPodPlacementConfig) error {
+func (r *ClusterPodPlacementConfigReconciler) reconcile(ctx context.Context, clusterPodPlacementConfig *multiarchv1beta1.ClusterPodPlacementConfig,
+	platform operandPlatform) error {
 	log := ctrllog.FromContext(ctx)
 	if int8(utils.AtomicLevel.Level()) != int8(clusterPodPlacementConfig.Spec.LogVerbosity.ToZapLevelInt()) {
 		log.Info("Setting log level", "level", -clusterPodPlacementConfig.Spec.LogVerbosity.ToZapLevelInt())
@@ -614,14 +692,22 @@ func (r *ClusterPodPlacementConfigReconciler) reconcile(ctx context.Context, clu
 		log.Error(err, "Unable to ensure namespace labels")
 		return errorutils.NewAggregate([]error{err, r.updateStatus(ctx, clusterPodPlacementConfig)})
 	}
-	clusterPodPlacementConfigObjects, err := r.buildPodPlacementConfigObjects(clusterPodPlacementConfig, ctx)
+	if clusterPodPlacementConfig.Spec.Operands.GetNetworkPolicies() != nil {
+		apiServer, err := r.apiServerEndpoints(ctx)
+		if err != nil {
+			log.Error(err, "Unable to resolve the endpoints of the API server for the network policies")
+			return errorutils.NewAggregate([]error{err, r.updateStatus(ctx, clusterPodPlacementConfig)})
+		}
+		platform.apiServer = apiServer
+	}
+	clusterPodPlacementConfigObjects, err := r.buildPodPlacementConfigObjects(clusterPodPlacementConfig, ctx, platform)
 	if err != nil {
 		return err
 	}
 
 	execFormatErrorObjects := []client.Object{}
 	if clusterPodPlacementConfig.PluginsEnabled(common.ExecFormatErrorMonitorPluginName) {
-		execFormatErrorObjects, err = r.buildENoExecEventObjects(ctx, clusterPodPlacementConfig)
+		execFormatErrorObjects, err = r.buildENoExecEventObjects(ctx, clusterPodPlacementConfig, platform)
 		if err != nil {
 			return err
 		}
@@ -634,6 +720,20 @@ func (r *ClusterPodPlacementConfigReconciler) reconcile(ctx context.Context, clu
 
 	objects := append(clusterPodPlacementConfigObjects, execFormatErrorObjects...)
 
+	if err := r.reconcileWebhookAutoscaling(ctx, clusterPodPlacementConfig, objects); err != nil {
+		log.Error(err, "Unable to reconcile the autoscaling of the pod placement webhook")
+		return errorutils.NewAggregate([]error{err, r.updateStatus(ctx, clusterPodPlacementConfig)})
+	}
+	if autoscaling := clusterPodPlacementConfig.Spec.Operands.GetPodPlacementWebhookAutoscaling(); autoscaling != nil {
+		objects = append(objects, buildWebhookHorizontalPodAutoscaler(autoscaling))
+	}
+	if clusterPodPlacementConfig.Spec.Operands.GetNetworkPolicies() == nil {
+		if err := r.deleteNetworkPolicies(ctx); err != nil {
+			log.Error(err, "Unable to delete the network policies of the operand components")
+			return errorutils.NewAggregate([]error{err, r.updateStatus(ctx, clusterPodPlacementConfig)})
+		}
+	}
+
 	// We ensure the MutatingWebHookConfiguration is created and present only if the operand is ready to serve the admission request and add/remove the scheduling gate.
 	shouldEnsureMWC := clusterPodPlacementConfig.Status.CanDeployMutatingWebhook()
 	shouldDeleteMWC := !shouldEnsureMWC && !clusterPodPlacementConfig.Status.IsMutatingWebhookConfigurationNotAvailable()
@@ -641,10 +741,23 @@ func (r *ClusterPodPlacementConfigReconciler) reconcile(ctx context.Context, clu
 		objects = append(objects, buildMutatingWebhookConfiguration(clusterPodPlacementConfig))
 	}
 	if shouldDeleteMWC {
-		log.Info("Deleting the mutating webhook configuration as the operand is not ready to serve the admission request or remove the scheduling gate")
+		reason := "the operand is not ready to serve the admission request or remove the scheduling gate"
+		if clusterPodPlacementConfig.Status.IsPaused() {
+			reason = "the operand is paused"
+		}
+		log.Info("Deleting the mutating webhook configuration as " + reason)
 		_ = r.ClientSet.AdmissionregistrationV1().MutatingWebhookConfigurations().Delete(ctx, utils.PodMutatingWebhookConfigurationName, metav1.DeleteOptions{})
 	}
 
+	// The ValidatingWebhookConfiguration follows the lifecycle of the MutatingWebhookConfiguration, and it is
+	// deployed only when a validating policy is configured: the validating policy is not enforced while paused.
+	if shouldEnsureMWC && clusterPodPlacementConfig.Spec.ValidatingPolicy != nil {
+		objects = append(objects, buildValidatingWebhookConfiguration(clusterPodPlacementConfig))
+	} else if err := r.ClientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(ctx,
+		utils.PodValidatingWebhookConfigurationName, metav1.DeleteOptions{}); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "Unable to delete the validating webhook configuration")
+	}
+
 	// If the servicemonitors.monitoring.coreos.com CRD is available, we create the ServiceMonitor objects
 	if utils.IsResourceAvailable(ctx, r.DynamicClient, monitoringv1.SchemeGroupVersion.WithResource("servicemonitors")) {
 		log.V(1).Info("Creating ServiceMonitors")
@@ -656,6 +769,21 @@ func (r *ClusterPodPlacementConfigReconciler) reconcile(ctx context.Context, clu
 	} else {
 		log.V(1).Info("servicemonitoring.monitoring.coreos.com is not available. Skipping the creation of the ServiceMonitors")
 	}
+	var servingCA *servingcerts.KeyPair
+	if platform.tlsMode == multiarchv1beta1.TLSModeSelfManaged {
+		if servingCA, platform.servingCABundle, err = r.ensureSelfManagedCA(ctx); err != nil {
+			log.Error(err, "Unable to ensure the CA of the self-managed serving certificates")
+			return errorutils.NewAggregate([]error{err, r.updateStatus(ctx, clusterPodPlacementConfig)})
+		}
+	}
+	objects = platform.applyPlatform(objects)
+	if platform.tlsMode != multiarchv1beta1.TLSModeCertManager &&
+		utils.IsResourceAvailable(ctx, r.DynamicClient, certManagerCertificatesGVR) {
+		if err := r.deleteCertManagerObjects(ctx); err != nil {
+			log.Error(err, "Unable to delete the cert-manager objects")
+		}
+	}
+
 	errs := make([]error, 0)
 	for _, o := range objects {
 		if err := ctrl.SetControllerReference(clusterPodPlacementConfig, o, r.Scheme); err != nil {
@@ -672,10 +800,79 @@ func (r *ClusterPodPlacementConfigReconciler) reconcile(ctx context.Context, clu
 		log.Error(err, "Unable to apply resources")
 		return errorutils.NewAggregate([]error{err, r.updateStatus(ctx, clusterPodPlacementConfig)})
 	}
+	// The access to the global pull secret is revoked in its previous namespace once it is granted in the new one,
+	// and everywhere when the webhook no longer inspects the images.
+	globalPullSecretReadersNamespace := platform.globalPullSecret.Namespace
+	if !webhookInspectsImages(clusterPodPlacementConfig) {
+		globalPullSecretReadersNamespace = ""
+	}
+	if err := r.deleteGlobalPullSecretReaders(ctx, globalPullSecretReadersNamespace); err != nil {
+		log.Error(err, "Unable to delete the stale access to the global pull secret")
+		return errorutils.NewAggregate([]error{err, r.updateStatus(ctx, clusterPodPlacementConfig)})
+	}
+
+	// The serving certificates are issued after the CA bundle is injected into the webhook configurations, so that
+	// they are trusted as soon as they are loaded.
+	if servingCA != nil {
+		errs := []error{r.ensureSelfManagedServingCertificates(ctx, clusterPodPlacementConfig, servingCA,
+			platform.servingCABundle, servicesSecrets(objects))}
+		// The serving certificates of the operator are also ensured by the OperatorServingCertificatesRotator:
+		// they are refreshed here as soon as the CA rotates.
+		if r.isOperatorTLSModeSelfManaged(clusterPodPlacementConfig) {
+			errs = append(errs, r.ensureOperatorServingCertificates(ctx, servingCA, platform.servingCABundle))
+		}
+		if err := errorutils.NewAggregate(errs); err != nil {
+			log.Error(err, "Unable to ensure the self-managed serving certificates")
+			return errorutils.NewAggregate([]error{err, r.updateStatus(ctx, clusterPodPlacementConfig)})
+		}
+	}
 
 	return r.updateStatus(ctx, clusterPodPlacementConfig)
 }
 
+// reconcileWebhookAutoscaling hands the replicas of the pod placement webhook over to its HorizontalPodAutoscaler when
+// the autoscaling is enabled: the Deployment keeps the replicas set by the autoscaler, or starts with the minimum
+// replicas. Otherwise, the HorizontalPodAutoscaler is deleted.
+func (r *ClusterPodPlacementConfigReconciler) reconcileWebhookAutoscaling(ctx context.Context,
+	clusterPodPlacementConfig *multiarchv1beta1.ClusterPodPlacementConfig, objects []client.Object) error {
+	autoscaling := clusterPodPlacementConfig.Spec.Operands.GetPodPlacementWebhookAutoscaling()
+	if autoscaling == nil {
+		return client.IgnoreNotFound(r.ClientSet.AutoscalingV2().HorizontalPodAutoscalers(utils.Namespace()).Delete(ctx,
+			utils.PodPlacementWebhookName, metav1.DeleteOptions{}))
+	}
+	existing, err := r.ClientSet.AppsV1().Deployments(utils.Namespace()).Get(ctx, utils.PodPlacementWebhookName,
+		metav1.GetOptions{})
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	replicas := int32(defaultWebhookAutoscalingMinReplicas)
+	if autoscaling.MinReplicas != nil {
+		replicas = *autoscaling.MinReplicas
+	}
+	if err == nil && existing.Spec.Replicas != nil {
+		replicas = *existing.Spec.Replicas
+	}
+	for _, o := range objects {
+		if d, ok := o.(*appsv1.Deployment); ok && d.Name == utils.PodPlacementWebhookName {
+			d.Spec.Replicas = utils.NewPtr(replicas)
+		}
+	}
+	return nil
+}
+
+// deleteNetworkPolicies deletes the NetworkPolicies of the operand components when they are disabled.
+func (r *ClusterPodPlacementConfigReconciler) deleteNetworkPolicies(ctx context.Context) error {
+	errs := make([]error, 0)
+	for _, name := range []string{utils.PodPlacementControllerName, utils.PodPlacementWebhookName,
+		utils.EnoexecControllerName} {
+		if err := utils.DeleteResource(ctx, r.ClientSet.NetworkingV1().NetworkPolicies(utils.Namespace()),
+			name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errorutils.NewAggregate(errs)
+}
+
 // updateStatus updates the status of the ClusterPodPlacementConfig object.
 // It returns an error if the object is progressing or the status update fails. Otherwise, it returns nil.
 // When it returns an error, the caller should requeue the request, unless the Reconciler is handling the deletion of the object.
@@ -703,10 +900,10 @@ func (r *ClusterPodPlacementConfigReconciler) updateStatus(ctx context.Context,
 	return err
 }
 
-func (r *ClusterPodPlacementConfigReconciler) buildPodPlacementConfigObjects(clusterPodPlacementConfig *multiarchv1beta1.ClusterPodPlacementConfig, ctx context.Context) ([]client.Object, error) {
+func (r *ClusterPodPlacementConfigReconciler) buildPodPlacementConfigObjects(clusterPodPlacementConfig *multiarchv1beta1.ClusterPodPlacementConfig, ctx context.Context, platform operandPlatform) ([]client.Object, error) {
 	log := ctrllog.FromContext(ctx)
 
-	requiredSCCHostmountAnyUID, seLinuxOptionsType, err := r.getCorrectHostmountAnyUIDSCC(ctx)
+	requiredSCCHostmountAnyUID, seLinuxOptionsType, err := r.getCorrectHostmountAnyUIDSCC(ctx, platform.isOpenShift)
 	if err != nil {
 		log.Error(err, "Unable to set correct hostmount SCC", "requiredSCCHostmoundAnyUID", requiredSCCHostmountAnyUID)
 		return []client.Object{}, errorutils.NewAggregate([]error{err, r.updateStatus(ctx, clusterPodPlacementConfig)})
@@ -716,7 +913,7 @@ func (r *ClusterPodPlacementConfigReconciler) buildPodPlacementConfigObjects(clu
 		// when updates to the ClusterPodPlacementConfig are made.
 		buildService(utils.PodPlacementControllerName),
 		buildService(utils.PodPlacementWebhookName),
-		buildClusterRoleController(), buildClusterRoleWebhook(), buildRoleController(),
+		buildClusterRoleController(), buildClusterRoleWebhook(webhookInspectsImages(clusterPodPlacementConfig)), buildRoleController(), buildRoleWebhook(),
 		buildServiceAccount(utils.PodPlacementWebhookName), buildServiceAccount(utils.PodPlacementControllerName),
 		buildClusterRoleBinding(utils.PodPlacementControllerName, rbacv1.RoleRef{
 			APIGroup: rbacv1.GroupName,
@@ -750,15 +947,35 @@ func (r *ClusterPodPlacementConfigReconciler) buildPodPlacementConfigObjects(clu
 				Namespace: utils.Namespace(),
 			},
 		}),
+		buildRoleBinding(utils.PodPlacementWebhookName, rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     roleKind,
+			Name:     utils.PodPlacementWebhookName,
+		}, []rbacv1.Subject{
+			{
+				Kind: serviceAccountKind,
+				Name: utils.PodPlacementWebhookName,
+			},
+		}),
 		buildControllerDeployment(clusterPodPlacementConfig, requiredSCCHostmountAnyUID, seLinuxOptionsType),
-		buildWebhookDeployment(clusterPodPlacementConfig),
+		buildWebhookDeployment(clusterPodPlacementConfig, requiredSCCHostmountAnyUID, seLinuxOptionsType),
+		buildPodDisruptionBudget(utils.PodPlacementControllerName),
+		buildPodDisruptionBudget(utils.PodPlacementWebhookName),
+	}
+	// Both the controller and the webhook inspect the images of the pods.
+	if networkPolicies := clusterPodPlacementConfig.Spec.Operands.GetNetworkPolicies(); networkPolicies != nil {
+		objects = append(objects,
+			buildNetworkPolicy(utils.PodPlacementControllerName, networkPolicies, platform, false, true),
+			buildNetworkPolicy(utils.PodPlacementWebhookName, networkPolicies, platform, true, true),
+		)
 	}
 	return objects, nil
 }
 
 // buildENoExecEventObjects if the ExecFormatErrorMonitor plugin is enabled create the deployment to start the controller
 // if it does not already exist
-func (r *ClusterPodPlacementConfigReconciler) buildENoExecEventObjects(ctx context.Context, clusterPodPlacementConfig *multiarchv1beta1.ClusterPodPlacementConfig) ([]client.Object, error) {
+func (r *ClusterPodPlacementConfigReconciler) buildENoExecEventObjects(ctx context.Context, clusterPodPlacementConfig *multiarchv1beta1.ClusterPodPlacementConfig,
+	platform operandPlatform) ([]client.Object, error) {
 	log := ctrllog.FromContext(ctx)
 	logVerbosityLevel := clusterPodPlacementConfig.Spec.LogVerbosity.ToZapLevelInt()
 
@@ -834,8 +1051,13 @@ func (r *ClusterPodPlacementConfigReconciler) buildENoExecEventObjects(ctx conte
 				},
 			},
 		),
-		buildDeploymentENoExecEventHandler(logVerbosityLevel),
-		buildDaemonSetENoExecEvent(utils.EnoexecDaemonSet, utils.EnoexecDaemonSet, logVerbosityLevel),
+		buildDeploymentENoExecEventHandler(logVerbosityLevel,
+			clusterPodPlacementConfig.Spec.Operands.GetENoExecEventController()),
+		buildDaemonSetENoExecEvent(utils.EnoexecDaemonSet, utils.EnoexecDaemonSet, logVerbosityLevel,
+			clusterPodPlacementConfig.Spec.Operands.GetENoExecEventDaemon()),
+	}
+	if networkPolicies := clusterPodPlacementConfig.Spec.Operands.GetNetworkPolicies(); networkPolicies != nil {
+		objects = append(objects, buildNetworkPolicy(utils.EnoexecControllerName, networkPolicies, platform, false, false))
 	}
 	// If the servicemonitors.monitoring.coreos.com CRD is available, we create the ServiceMonitor objects
 	if utils.IsResourceAvailable(ctx, r.DynamicClient, monitoringv1.SchemeGroupVersion.WithResource("servicemonitors")) {
@@ -886,7 +1108,16 @@ func (r *ClusterPodPlacementConfigReconciler) SetupWithManager(mgr ctrl.Manager)
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
 		Owns(&corev1.ServiceAccount{}).
-		Owns(&admissionv1.MutatingWebhookConfiguration{})
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&admissionv1.MutatingWebhookConfiguration{}).
+		Owns(&admissionv1.ValidatingWebhookConfiguration{}).
+		// The NetworkPolicies of the operand components follow the endpoints of the API server.
+		Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(
+			func(context.Context, client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: common.SingletonResourceObjectName}}}
+			}), builder.WithPredicates(predicate.NewPredicateFuncs(isAPIServerEndpointSlice)))
 	if utils.IsResourceAvailable(context.Background(), r.DynamicClient,
 		monitoringv1.SchemeGroupVersion.WithResource("servicemonitors")) {
 		c = c.Owns(&monitoringv1.ServiceMonitor{}).Owns(&monitoringv1.PrometheusRule{})
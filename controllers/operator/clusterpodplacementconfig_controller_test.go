@@ -18,12 +18,21 @@ package operator
 
 import (
 	"fmt"
+	"time"
 
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -31,6 +40,7 @@ import (
 
 	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
 	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/servingcerts"
 	"github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
 	"github.com/openshift/multiarch-tuning-operator/pkg/testing/framework"
 	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
@@ -172,6 +182,8 @@ var _ = Describe("Controllers/ClusterPodPlacementConfig/ClusterPodPlacementConfi
 				Entry("ClusterRoleBinding", builder.NewClusterRoleBinding().WithName(utils.PodPlacementWebhookName).Build()),
 				Entry("Role", builder.NewRole().WithName(utils.PodPlacementControllerName).WithNamespace(utils.Namespace()).Build()),
 				Entry("RoleBinding", builder.NewRoleBinding().WithName(utils.PodPlacementControllerName).WithNamespace(utils.Namespace()).Build()),
+				Entry("Webhook Role", builder.NewRole().WithName(utils.PodPlacementWebhookName).WithNamespace(utils.Namespace()).Build()),
+				Entry("Webhook RoleBinding", builder.NewRoleBinding().WithName(utils.PodPlacementWebhookName).WithNamespace(utils.Namespace()).Build()),
 				Entry("ServiceAccount", builder.NewServiceAccount().WithName(utils.PodPlacementWebhookName).WithNamespace(utils.Namespace()).Build()),
 			)
 			It("should reconcile a service if changed", func() {
@@ -315,8 +327,168 @@ var _ = Describe("Controllers/ClusterPodPlacementConfig/ClusterPodPlacementConfi
 					}), mw)
 					g.Expect(err).NotTo(HaveOccurred(), "failed to get mutating webhook configuration "+utils.PodMutatingWebhookConfigurationName, err)
 					g.Expect(mw.Webhooks[0].NamespaceSelector).To(Equal(ppc.Spec.NamespaceSelector))
+					g.Expect(mw.Webhooks[0].ReinvocationPolicy).To(Equal(utils.NewPtr(admissionv1.IfNeededReinvocationPolicy)),
+						"the webhook should be reinvoked when other webhooks modify the pods")
 				}).Should(Succeed(), "the deployment "+utils.PodPlacementControllerName+" should be updated")
 			})
+			It("should manage the pod disruption budgets and the autoscaler of the webhook", func() {
+				for _, name := range []string{utils.PodPlacementControllerName, utils.PodPlacementWebhookName} {
+					By("verifying the pod disruption budget of " + name)
+					Eventually(func(g Gomega) {
+						pdb := &policyv1.PodDisruptionBudget{}
+						err := k8sClient.Get(ctx, crclient.ObjectKey{Name: name, Namespace: utils.Namespace()}, pdb)
+						g.Expect(err).NotTo(HaveOccurred(), "failed to get the pod disruption budget "+name, err)
+						g.Expect(pdb.Spec.MaxUnavailable).To(Equal(utils.NewPtr(intstr.FromInt32(1))))
+					}).Should(Succeed(), "the pod disruption budget "+name+" should be created")
+				}
+				By("enabling the autoscaling of the webhook")
+				Eventually(func(g Gomega) {
+					ppc := &v1beta1.ClusterPodPlacementConfig{}
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: common.SingletonResourceObjectName}, ppc)
+					g.Expect(err).NotTo(HaveOccurred(), "failed to get ClusterPodPlacementConfig", err)
+					ppc.Spec.Operands = &v1beta1.Operands{
+						PodPlacementWebhookAutoscaling: &v1beta1.WebhookAutoscaling{MaxReplicas: 6},
+					}
+					g.Expect(k8sClient.Update(ctx, ppc)).To(Succeed())
+				}).Should(Succeed(), "the ClusterPodPlacementConfig should be updated")
+				Eventually(func(g Gomega) {
+					hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: utils.PodPlacementWebhookName, Namespace: utils.Namespace()}, hpa)
+					g.Expect(err).NotTo(HaveOccurred(), "failed to get the horizontal pod autoscaler", err)
+					g.Expect(hpa.Spec.MaxReplicas).To(Equal(int32(6)))
+				}).Should(Succeed(), "the horizontal pod autoscaler should be created")
+				By("scaling the webhook as the autoscaler would do")
+				Eventually(func(g Gomega) {
+					d := &appsv1.Deployment{}
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: utils.PodPlacementWebhookName, Namespace: utils.Namespace()}, d)
+					g.Expect(err).NotTo(HaveOccurred(), "failed to get deployment "+utils.PodPlacementWebhookName, err)
+					d.Spec.Replicas = utils.NewPtr(int32(5))
+					g.Expect(k8sClient.Update(ctx, d)).To(Succeed())
+				}).Should(Succeed(), "the deployment should be scaled")
+				triggerReconcile()
+				Consistently(func(g Gomega) {
+					d := &appsv1.Deployment{}
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: utils.PodPlacementWebhookName, Namespace: utils.Namespace()}, d)
+					g.Expect(err).NotTo(HaveOccurred(), "failed to get deployment "+utils.PodPlacementWebhookName, err)
+					g.Expect(d.Spec.Replicas).To(Equal(utils.NewPtr(int32(5))), "the replicas set by the autoscaler should be kept")
+				}, 3*time.Second).Should(Succeed())
+				By("disabling the autoscaling of the webhook")
+				Eventually(func(g Gomega) {
+					ppc := &v1beta1.ClusterPodPlacementConfig{}
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: common.SingletonResourceObjectName}, ppc)
+					g.Expect(err).NotTo(HaveOccurred(), "failed to get ClusterPodPlacementConfig", err)
+					ppc.Spec.Operands = nil
+					g.Expect(k8sClient.Update(ctx, ppc)).To(Succeed())
+				}).Should(Succeed(), "the ClusterPodPlacementConfig should be updated")
+				Eventually(func(g Gomega) {
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: utils.PodPlacementWebhookName, Namespace: utils.Namespace()},
+						&autoscalingv2.HorizontalPodAutoscaler{})
+					g.Expect(errors.IsNotFound(err)).To(BeTrue(), "the horizontal pod autoscaler should be deleted", err)
+					d := &appsv1.Deployment{}
+					err = k8sClient.Get(ctx, crclient.ObjectKey{Name: utils.PodPlacementWebhookName, Namespace: utils.Namespace()}, d)
+					g.Expect(err).NotTo(HaveOccurred(), "failed to get deployment "+utils.PodPlacementWebhookName, err)
+					g.Expect(d.Spec.Replicas).To(Equal(utils.NewPtr(int32(3))), "the default replicas should be restored")
+				}).Should(Succeed(), "the autoscaling should be disabled")
+				validateReconcile()
+			})
+			It("should revoke the access to the global pull secret in its previous namespace", func() {
+				const namespace = "global-pull-secret"
+				Expect(crclient.IgnoreAlreadyExists(k8sClient.Create(ctx, &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{Name: namespace},
+				}))).To(Succeed())
+				name := utils.PodPlacementWebhookName + globalPullSecretReaderSuffix
+				previousNamespace := ""
+				By("enabling the validating policy, so that the webhook inspects the images")
+				Eventually(func(g Gomega) {
+					ppc := &v1beta1.ClusterPodPlacementConfig{}
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: common.SingletonResourceObjectName}, ppc)
+					g.Expect(err).NotTo(HaveOccurred(), "failed to get ClusterPodPlacementConfig", err)
+					ppc.Spec.ValidatingPolicy = &v1beta1.ValidatingPolicy{Mode: v1beta1.RequireCommonArchitecture}
+					g.Expect(k8sClient.Update(ctx, ppc)).To(Succeed())
+				}).Should(Succeed(), "the ClusterPodPlacementConfig should be updated")
+				Eventually(func(g Gomega) {
+					roles := &rbacv1.RoleList{}
+					g.Expect(k8sClient.List(ctx, roles, crclient.HasLabels{globalPullSecretReaderLabel})).To(Succeed())
+					g.Expect(roles.Items).To(ConsistOf(HaveField("Name", name)))
+					previousNamespace = roles.Items[0].Namespace
+				}).Should(Succeed(), "the role granting the access to the global pull secret should be created")
+				By("moving the global pull secret to another namespace")
+				Eventually(func(g Gomega) {
+					ppc := &v1beta1.ClusterPodPlacementConfig{}
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: common.SingletonResourceObjectName}, ppc)
+					g.Expect(err).NotTo(HaveOccurred(), "failed to get ClusterPodPlacementConfig", err)
+					ppc.Spec.GlobalPullSecretRef = &corev1.SecretReference{Namespace: namespace, Name: "credentials"}
+					g.Expect(k8sClient.Update(ctx, ppc)).To(Succeed())
+				}).Should(Succeed(), "the ClusterPodPlacementConfig should be updated")
+				Eventually(func(g Gomega) {
+					roles := &rbacv1.RoleList{}
+					g.Expect(k8sClient.List(ctx, roles, crclient.HasLabels{globalPullSecretReaderLabel})).To(Succeed())
+					g.Expect(roles.Items).To(ConsistOf(SatisfyAll(HaveField("Name", name), HaveField("Namespace", namespace))))
+					roleBindings := &rbacv1.RoleBindingList{}
+					g.Expect(k8sClient.List(ctx, roleBindings, crclient.HasLabels{globalPullSecretReaderLabel})).To(Succeed())
+					g.Expect(roleBindings.Items).To(ConsistOf(SatisfyAll(HaveField("Name", name), HaveField("Namespace", namespace))))
+				}).Should(Succeed(), "the access to the global pull secret should be revoked in "+previousNamespace)
+				By("disabling the validating policy")
+				Eventually(func(g Gomega) {
+					ppc := &v1beta1.ClusterPodPlacementConfig{}
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: common.SingletonResourceObjectName}, ppc)
+					g.Expect(err).NotTo(HaveOccurred(), "failed to get ClusterPodPlacementConfig", err)
+					ppc.Spec.ValidatingPolicy = nil
+					g.Expect(k8sClient.Update(ctx, ppc)).To(Succeed())
+				}).Should(Succeed(), "the ClusterPodPlacementConfig should be updated")
+				Eventually(func(g Gomega) {
+					roles := &rbacv1.RoleList{}
+					g.Expect(k8sClient.List(ctx, roles, crclient.HasLabels{globalPullSecretReaderLabel})).To(Succeed())
+					g.Expect(roles.Items).To(BeEmpty())
+					roleBindings := &rbacv1.RoleBindingList{}
+					g.Expect(k8sClient.List(ctx, roleBindings, crclient.HasLabels{globalPullSecretReaderLabel})).To(Succeed())
+					g.Expect(roleBindings.Items).To(BeEmpty())
+				}).Should(Succeed(), "the access to the global pull secret should be revoked when the webhook does not inspect the images")
+			})
+			It("should rebuild the network policies when the endpoints of the API server change", func() {
+				By("enabling the network policies")
+				Eventually(func(g Gomega) {
+					ppc := &v1beta1.ClusterPodPlacementConfig{}
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: common.SingletonResourceObjectName}, ppc)
+					g.Expect(err).NotTo(HaveOccurred(), "failed to get ClusterPodPlacementConfig", err)
+					ppc.Spec.Operands = &v1beta1.Operands{
+						NetworkPolicies: &v1beta1.OperandNetworkPolicies{Enabled: true},
+					}
+					g.Expect(k8sClient.Update(ctx, ppc)).To(Succeed())
+				}).Should(Succeed(), "the ClusterPodPlacementConfig should be updated")
+				apiServerEgress := func(g Gomega) networkingv1.NetworkPolicyEgressRule {
+					np := &networkingv1.NetworkPolicy{}
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: utils.PodPlacementWebhookName, Namespace: utils.Namespace()}, np)
+					g.Expect(err).NotTo(HaveOccurred(), "failed to get the network policy", err)
+					g.Expect(np.Spec.Egress).To(HaveLen(3))
+					return np.Spec.Egress[1]
+				}
+				Eventually(func(g Gomega) {
+					g.Expect(apiServerEgress(g).To).NotTo(BeEmpty())
+				}).Should(Succeed(), "the network policy should allow the egress to the API server")
+				By("adding an endpoint to the API server")
+				endpointSlice := &discoveryv1.EndpointSlice{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kubernetes-extra",
+						Namespace: metav1.NamespaceDefault,
+						Labels:    map[string]string{discoveryv1.LabelServiceName: apiServerServiceName},
+					},
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"192.0.2.10"}}},
+					Ports:       []discoveryv1.EndpointPort{{Port: utils.NewPtr(int32(6443))}},
+				}
+				Expect(k8sClient.Create(ctx, endpointSlice)).To(Succeed())
+				Eventually(func(g Gomega) {
+					egress := apiServerEgress(g)
+					g.Expect(egress.To).To(ContainElement(HaveField("IPBlock.CIDR", "192.0.2.10/32")))
+					g.Expect(egress.Ports).To(ContainElement(HaveField("Port.IntVal", int32(6443))))
+				}).Should(Succeed(), "the network policy should allow the egress to the new endpoint")
+				By("removing the endpoint from the API server")
+				Expect(k8sClient.Delete(ctx, endpointSlice)).To(Succeed())
+				Eventually(func(g Gomega) {
+					g.Expect(apiServerEgress(g).To).NotTo(ContainElement(HaveField("IPBlock.CIDR", "192.0.2.10/32")))
+				}).Should(Succeed(), "the network policy should not allow the egress to the removed endpoint")
+			})
 			It("Should have ClusterPodPlacementConfig finalizers", func() {
 				ppc := &v1beta1.ClusterPodPlacementConfig{}
 				err := k8sClient.Get(ctx, crclient.ObjectKeyFromObject(&v1beta1.ClusterPodPlacementConfig{
@@ -436,6 +608,49 @@ var _ = Describe("Controllers/ClusterPodPlacementConfig/ClusterPodPlacementConfi
 					)).Should(Succeed(), "the ClusterPodPlacementConfig should have the correct conditions")
 			})
 		})
+		When("the operand is paused", func() {
+			It("should remove the mutating webhook configuration and keep the deployments", func() {
+				setPaused := func(paused bool) {
+					Eventually(func(g Gomega) {
+						ppc := &v1beta1.ClusterPodPlacementConfig{}
+						err := k8sClient.Get(ctx, crclient.ObjectKey{Name: common.SingletonResourceObjectName}, ppc)
+						g.Expect(err).NotTo(HaveOccurred(), "failed to get ClusterPodPlacementConfig", err)
+						ppc.Spec.Paused = paused
+						g.Expect(k8sClient.Update(ctx, ppc)).To(Succeed())
+					}).Should(Succeed(), "the ClusterPodPlacementConfig should be updated")
+				}
+				By("pausing the operand")
+				setPaused(true)
+				Eventually(func(g Gomega) {
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: utils.PodMutatingWebhookConfigurationName},
+						&admissionv1.MutatingWebhookConfiguration{})
+					g.Expect(errors.IsNotFound(err)).To(BeTrue(), "the mutating webhook configuration should be deleted", err)
+				}).Should(Succeed(), "the mutating webhook configuration should be deleted")
+				Eventually(
+					framework.VerifyConditions(ctx, k8sClient,
+						framework.NewConditionTypeStatusTuple(v1beta1.AvailableType, corev1.ConditionTrue),
+						framework.NewConditionTypeStatusTuple(v1beta1.ProgressingType, corev1.ConditionFalse),
+						framework.NewConditionTypeStatusTuple(v1beta1.DegradedType, corev1.ConditionFalse),
+						framework.NewConditionTypeStatusTuple(v1beta1.PausedType, corev1.ConditionTrue),
+					)).Should(Succeed(), "the ClusterPodPlacementConfig should have the correct conditions")
+				for _, name := range []string{utils.PodPlacementControllerName, utils.PodPlacementWebhookName} {
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: name, Namespace: utils.Namespace()}, &appsv1.Deployment{})
+					Expect(err).NotTo(HaveOccurred(), "the deployment "+name+" should be kept", err)
+				}
+				By("resuming the operand")
+				setPaused(false)
+				Eventually(func(g Gomega) {
+					err := k8sClient.Get(ctx, crclient.ObjectKey{Name: utils.PodMutatingWebhookConfigurationName},
+						&admissionv1.MutatingWebhookConfiguration{})
+					g.Expect(err).NotTo(HaveOccurred(), "failed to get the mutating webhook configuration", err)
+				}).Should(Succeed(), "the mutating webhook configuration should be created")
+				Eventually(
+					framework.VerifyConditions(ctx, k8sClient,
+						framework.NewConditionTypeStatusTuple(v1beta1.AvailableType, corev1.ConditionTrue),
+						framework.NewConditionTypeStatusTuple(v1beta1.PausedType, corev1.ConditionFalse),
+					)).Should(Succeed(), "the ClusterPodPlacementConfig should have the correct conditions")
+			})
+		})
 		When("the pod placement controller is not available", func() {
 			It("should be degraded, progressing and no mutating webhook should be present", func() {
 				patchDeploymentStatus(utils.PodPlacementControllerName, NewGomegaWithT(GinkgoT()), func(d *appsv1.Deployment) {
@@ -564,6 +779,101 @@ var _ = Describe("Controllers/ClusterPodPlacementConfig/ClusterPodPlacementConfi
 				)).Should(Succeed(), "the ClusterPodPlacementConfig should converge to normal conditions")
 			})
 		})
+		When("the serving certificates are self-managed", func() {
+			It("should issue the serving certificates and inject the CA bundle", func() {
+				Eventually(func(g Gomega) {
+					ca, bundle := getSelfManagedCA(g)
+					for _, name := range []string{utils.PodPlacementControllerName, utils.PodPlacementWebhookName} {
+						certificate := getServingCertificate(g, name)
+						g.Expect(certificate.IsValidServingCertificate(ca.Certificate, servingCertificateDNSNames(name), time.Now())).To(BeTrue(),
+							"the serving certificate of "+name+" should be signed by the CA")
+					}
+					mwc := &admissionv1.MutatingWebhookConfiguration{}
+					g.Expect(k8sClient.Get(ctx, crclient.ObjectKey{Name: utils.PodMutatingWebhookConfigurationName}, mwc)).To(Succeed())
+					g.Expect(mwc.Webhooks[0].ClientConfig.CABundle).To(Equal(bundle), "the CA bundle should be injected")
+				}).Should(Succeed(), "the self-managed serving certificates should be issued")
+			})
+			It("should not manage the serving certificates of the operator unless explicitly self-managed", func() {
+				r := &ClusterPodPlacementConfigReconciler{
+					Client:        k8sClient,
+					Scheme:        k8sClient.Scheme(),
+					ClientSet:     kubernetes.NewForConfigOrDie(cfg),
+					DynamicClient: dynamic.NewForConfigOrDie(cfg),
+				}
+				Expect(r.EnsureOperatorServingCertificates(ctx)).To(BeFalse(),
+					"the defaulted SelfManaged TLS mode of the operand should not apply to the operator")
+			})
+			It("should ensure the CA of the operator without owning it by the ClusterPodPlacementConfig", func() {
+				r := &ClusterPodPlacementConfigReconciler{
+					Client:          k8sClient,
+					Scheme:          k8sClient.Scheme(),
+					ClientSet:       kubernetes.NewForConfigOrDie(cfg),
+					DynamicClient:   dynamic.NewForConfigOrDie(cfg),
+					OperatorTLSMode: v1beta1.TLSModeSelfManaged,
+				}
+				Expect(r.EnsureOperatorServingCertificates(ctx)).To(BeTrue())
+				secret := &corev1.Secret{}
+				Expect(k8sClient.Get(ctx, crclient.ObjectKey{Name: selfManagedCASecretName, Namespace: utils.Namespace()}, secret)).To(Succeed())
+				Expect(secret.OwnerReferences).To(BeEmpty(), "the CA should outlive the ClusterPodPlacementConfig")
+			})
+			It("should rotate the serving certificates before they expire", func() {
+				var expiring *servingcerts.KeyPair
+				Eventually(func(g Gomega) {
+					ca, _ := getSelfManagedCA(g)
+					var err error
+					expiring, err = ca.NewServingCertificate(servingCertificateDNSNames(utils.PodPlacementWebhookName), time.Now(), time.Minute)
+					g.Expect(err).NotTo(HaveOccurred())
+					keyPEM, err := expiring.KeyPEM()
+					g.Expect(err).NotTo(HaveOccurred())
+					secret := &corev1.Secret{}
+					g.Expect(k8sClient.Get(ctx, crclient.ObjectKey{Name: utils.PodPlacementWebhookName, Namespace: utils.Namespace()}, secret)).To(Succeed())
+					secret.Data[corev1.TLSCertKey] = expiring.CertificatePEM()
+					secret.Data[corev1.TLSPrivateKeyKey] = keyPEM
+					g.Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+				}).Should(Succeed(), "the serving certificate should be replaced with an expiring one")
+				triggerReconcile()
+				Eventually(func(g Gomega) {
+					ca, _ := getSelfManagedCA(g)
+					certificate := getServingCertificate(g, utils.PodPlacementWebhookName)
+					g.Expect(certificate.Certificate.Equal(expiring.Certificate)).To(BeFalse(), "the serving certificate should be rotated")
+					g.Expect(certificate.IsValidServingCertificate(ca.Certificate, servingCertificateDNSNames(utils.PodPlacementWebhookName), time.Now())).To(BeTrue())
+				}).Should(Succeed(), "the expiring serving certificate should be rotated")
+			})
+			It("should rotate the CA and keep trusting the previous one", func() {
+				var previous *servingcerts.KeyPair
+				Eventually(func(g Gomega) {
+					// The expiring CA needs to be rotated: less than a third of its lifetime is left.
+					var err error
+					previous, err = servingcerts.NewCA("expiring-ca", time.Now().Add(-50*time.Minute), time.Hour)
+					g.Expect(err).NotTo(HaveOccurred())
+					keyPEM, err := previous.KeyPEM()
+					g.Expect(err).NotTo(HaveOccurred())
+					secret := &corev1.Secret{}
+					g.Expect(k8sClient.Get(ctx, crclient.ObjectKey{Name: selfManagedCASecretName, Namespace: utils.Namespace()}, secret)).To(Succeed())
+					secret.Data[corev1.TLSCertKey] = previous.CertificatePEM()
+					secret.Data[corev1.TLSPrivateKeyKey] = keyPEM
+					secret.Data[caBundleKey] = previous.CertificatePEM()
+					g.Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+				}).Should(Succeed(), "the CA should be replaced with an expiring one")
+				triggerReconcile()
+				Eventually(func(g Gomega) {
+					ca, bundle := getSelfManagedCA(g)
+					g.Expect(ca.Certificate.Equal(previous.Certificate)).To(BeFalse(), "the CA should be rotated")
+					certificates, err := servingcerts.ParseCertificates(bundle)
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(certificates).To(HaveLen(2), "the CA bundle should include the previous CA")
+					g.Expect(certificates[1].Equal(previous.Certificate)).To(BeTrue())
+					mwc := &admissionv1.MutatingWebhookConfiguration{}
+					g.Expect(k8sClient.Get(ctx, crclient.ObjectKey{Name: utils.PodMutatingWebhookConfigurationName}, mwc)).To(Succeed())
+					g.Expect(mwc.Webhooks[0].ClientConfig.CABundle).To(Equal(bundle))
+					for _, name := range []string{utils.PodPlacementControllerName, utils.PodPlacementWebhookName} {
+						certificate := getServingCertificate(g, name)
+						g.Expect(certificate.IsValidServingCertificate(ca.Certificate, servingCertificateDNSNames(name), time.Now())).To(BeTrue(),
+							"the serving certificate of "+name+" should be signed by the new CA")
+					}
+				}).Should(Succeed(), "the CA should be rotated")
+			})
+		})
 		AfterAll(func() {
 			err := k8sClient.Delete(ctx, builder.NewClusterPodPlacementConfig().WithName(common.SingletonResourceObjectName).Build())
 			Expect(crclient.IgnoreNotFound(err)).NotTo(HaveOccurred(), "failed to delete ClusterPodPlacementConfig", err)
@@ -693,6 +1003,44 @@ func setDeploymentReady(name string, g Gomega) {
 
 // validateReconcile
 // NOTE: this can be used only in integratoin tests as it changes the status of deployments
+// getSelfManagedCA returns the CA of the self-managed serving certificates and the CA bundle.
+func getSelfManagedCA(g Gomega) (*servingcerts.KeyPair, []byte) {
+	secret := &corev1.Secret{}
+	g.Expect(k8sClient.Get(ctx, crclient.ObjectKey{Name: selfManagedCASecretName, Namespace: utils.Namespace()}, secret)).To(Succeed())
+	ca, err := servingcerts.ParseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	g.Expect(err).NotTo(HaveOccurred())
+	return ca, secret.Data[caBundleKey]
+}
+
+// getServingCertificate returns the serving certificate stored in the Secret named after the given Service.
+func getServingCertificate(g Gomega, name string) *servingcerts.KeyPair {
+	secret := &corev1.Secret{}
+	g.Expect(k8sClient.Get(ctx, crclient.ObjectKey{Name: name, Namespace: utils.Namespace()}, secret)).To(Succeed())
+	certificate, err := servingcerts.ParseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	g.Expect(err).NotTo(HaveOccurred())
+	return certificate
+}
+
+func servingCertificateDNSNames(name string) []string {
+	return []string{
+		fmt.Sprintf("%s.%s.svc", name, utils.Namespace()),
+		fmt.Sprintf("%s.%s.svc.cluster.local", name, utils.Namespace()),
+	}
+}
+
+// triggerReconcile updates an annotation of the ClusterPodPlacementConfig to trigger its reconciliation.
+func triggerReconcile() {
+	Eventually(func(g Gomega) {
+		cppc := &v1beta1.ClusterPodPlacementConfig{}
+		g.Expect(k8sClient.Get(ctx, crclient.ObjectKey{Name: common.SingletonResourceObjectName}, cppc)).To(Succeed())
+		if cppc.Annotations == nil {
+			cppc.Annotations = map[string]string{}
+		}
+		cppc.Annotations["test.multiarch.openshift.io/reconcile"] = time.Now().String()
+		g.Expect(k8sClient.Update(ctx, cppc)).To(Succeed())
+	}).Should(Succeed(), "the ClusterPodPlacementConfig should be updated")
+}
+
 func validateReconcile(pluginObjectsSet ...framework.PluginObjectsSet) {
 	for _, name := range []string{utils.PodPlacementControllerName, utils.PodPlacementWebhookName} {
 		Eventually(func(g Gomega) {
@@ -107,8 +107,9 @@ func buildRoleENoExecEventDaemonSet() *rbacv1.Role {
 }
 
 // buildDaemonSet returns the DaemonSet object for ENoExecEvent
-func buildDaemonSetENoExecEvent(serviceAccount string, name string, logVerbosity int, args ...string) *appsv1.DaemonSet {
-	return &appsv1.DaemonSet{
+func buildDaemonSetENoExecEvent(serviceAccount string, name string, logVerbosity int, operand *v1beta1.OperandConfig,
+	args ...string) *appsv1.DaemonSet {
+	ds := &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      utils.EnoexecDaemonSet,
 			Namespace: utils.Namespace(),
@@ -222,10 +223,12 @@ func buildDaemonSetENoExecEvent(serviceAccount string, name string, logVerbosity
 			},
 		},
 	}
+	applyOperandConfig(&ds.Spec.Template.Spec, operand)
+	return ds
 }
 
 // buildEnoexecDeployment returns a minimal Deployment object matching your YAML
-func buildDeploymentENoExecEventHandler(logVerbosity int) *appsv1.Deployment {
+func buildDeploymentENoExecEventHandler(logVerbosity int, operand *v1beta1.OperandDeploymentConfig) *appsv1.Deployment {
 	d := buildDeployment(logVerbosity, utils.EnoexecControllerName, 2, utils.EnoexecControllerName, "",
 		"--leader-elect", "--enable-enoexec-event-controllers",
 	)
@@ -251,6 +254,7 @@ func buildDeploymentENoExecEventHandler(logVerbosity int) *appsv1.Deployment {
 	d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, additionalVolumes...)
 	d.Spec.Template.Spec.Containers[0].VolumeMounts = append(d.Spec.Template.Spec.Containers[0].VolumeMounts, additionalMounts...)
 
+	applyOperandDeploymentConfig(d, operand)
 	return d
 }
 
@@ -6,6 +6,8 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,6 +15,7 @@ import (
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
 	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
 )
 
@@ -30,6 +33,11 @@ const (
 	roleKind           = "Role"
 	clusterRoleKind    = "ClusterRole"
 
+	networkPolicyGroupLabel      = "network.openshift.io/policy-group"
+	networkPolicyGroupMonitoring = "monitoring"
+	// networkPolicyHostNetworkLabel labels the namespaces selecting the host network traffic in OpenShift.
+	networkPolicyHostNetworkLabel = "policy-group.network.openshift.io/host-network"
+
 	// xref: https://github.com/openshift/enhancements/blob/9b5d8a964fc/enhancements/authentication/custom-scc-preemption-prevention.md
 	requiredSCCAnnotation   = "openshift.io/required-scc"
 	requiredSCCRestrictedV2 = "restricted-v2"
@@ -44,9 +52,6 @@ func buildService(name string) *corev1.Service {
 				utils.OperandLabelKey:   operandName,
 				utils.ControllerNameKey: name,
 			},
-			Annotations: map[string]string{
-				"service.beta.openshift.io/serving-cert-secret-name": name,
-			},
 		},
 		Spec: corev1.ServiceSpec{
 			Ports: []corev1.ServicePort{
@@ -71,6 +76,92 @@ func buildService(name string) *corev1.Service {
 	}
 }
 
+// buildNetworkPolicy creates the NetworkPolicy of the pods exposed by the Service with the same name. The metrics
+// port accepts connections from the monitoring namespaces and, when servesWebhook is true, the webhook port accepts
+// connections from the API server: from its endpoints and, on OpenShift, from the host network, as the API server
+// usually runs on it. The egress is restricted to the DNS and the endpoints of the API server, and, when
+// inspectsImages is true, to the image registries.
+func buildNetworkPolicy(name string, config *v1beta1.OperandNetworkPolicies, platform operandPlatform,
+	servesWebhook, inspectsImages bool) *networkingv1.NetworkPolicy {
+	metricsNamespaceSelector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{networkPolicyGroupLabel: networkPolicyGroupMonitoring},
+	}
+	if config.MetricsNamespaceSelector != nil {
+		metricsNamespaceSelector = config.MetricsNamespaceSelector.DeepCopy()
+	}
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		{
+			From:  []networkingv1.NetworkPolicyPeer{{NamespaceSelector: metricsNamespaceSelector}},
+			Ports: networkPolicyPorts(corev1.ProtocolTCP, 8443),
+		},
+	}
+	if servesWebhook {
+		apiServerPeers := platform.apiServer.peers()
+		if platform.isOpenShift {
+			apiServerPeers = append(apiServerPeers, networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{networkPolicyHostNetworkLabel: ""},
+				},
+			})
+		}
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			From:  apiServerPeers,
+			Ports: networkPolicyPorts(corev1.ProtocolTCP, 9443),
+		})
+	}
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{
+			Ports: append(networkPolicyPorts(corev1.ProtocolUDP, 53, 5353),
+				networkPolicyPorts(corev1.ProtocolTCP, 53, 5353)...),
+		},
+		{
+			To:    platform.apiServer.peers(),
+			Ports: networkPolicyPorts(corev1.ProtocolTCP, platform.apiServer.ports...),
+		},
+	}
+	if inspectsImages {
+		registries := networkingv1.NetworkPolicyEgressRule{}
+		for _, cidr := range config.RegistryCIDRs {
+			registries.To = append(registries.To, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+			})
+		}
+		egress = append(egress, registries)
+	}
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: utils.Namespace(),
+			Labels: map[string]string{
+				utils.OperandLabelKey:   operandName,
+				utils.ControllerNameKey: name,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					utils.OperandLabelKey:   operandName,
+					utils.ControllerNameKey: name,
+				},
+			},
+			Ingress:     ingress,
+			Egress:      egress,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}
+}
+
+func networkPolicyPorts(protocol corev1.Protocol, ports ...int32) []networkingv1.NetworkPolicyPort {
+	policyPorts := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		policyPorts = append(policyPorts, networkingv1.NetworkPolicyPort{
+			Protocol: utils.NewPtr(protocol),
+			Port:     utils.NewPtr(intstr.FromInt32(port)),
+		})
+	}
+	return policyPorts
+}
+
 func buildDeployment(logVerbosity int,
 	name string, replicas int32, serviceAccount string, finalizer string, args ...string) *appsv1.Deployment {
 	finalizers := make([]string, 0)
@@ -137,6 +228,24 @@ func buildDeployment(logVerbosity int,
 								},
 							},
 						},
+						// The replicas are spread across the nodes and the zones, so that a node drain or a zone
+						// outage does not take down all of them at once.
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+								{
+									Weight: 100,
+									PodAffinityTerm: corev1.PodAffinityTerm{
+										LabelSelector: &metav1.LabelSelector{
+											MatchLabels: map[string]string{
+												utils.OperandLabelKey:   operandName,
+												utils.ControllerNameKey: name,
+											},
+										},
+										TopologyKey: "kubernetes.io/hostname",
+									},
+								},
+							},
+						},
 					},
 					Containers: []corev1.Container{
 						{
@@ -219,7 +328,7 @@ func buildDeployment(logVerbosity int,
 							// Generic volume mounts that all deployments need
 							VolumeMounts: []corev1.VolumeMount{
 								{
-									Name:      "trusted-ca",
+									Name:      trustedCAVolumeName,
 									MountPath: "/etc/pki/ca-trust/extracted/pem",
 									ReadOnly:  true,
 								},
@@ -244,15 +353,27 @@ func buildDeployment(logVerbosity int,
 							},
 							MatchLabelKeys: []string{"pod-template-hash"},
 						},
+						{
+							MaxSkew:           1,
+							TopologyKey:       "topology.kubernetes.io/zone",
+							WhenUnsatisfiable: corev1.ScheduleAnyway,
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									utils.OperandLabelKey:   operandName,
+									utils.ControllerNameKey: name,
+								},
+							},
+							MatchLabelKeys: []string{"pod-template-hash"},
+						},
 					},
 					// Generic volumes that all deployments need
 					Volumes: []corev1.Volume{
 						{
-							Name: "trusted-ca",
+							Name: trustedCAVolumeName,
 							VolumeSource: corev1.VolumeSource{
 								ConfigMap: &corev1.ConfigMapVolumeSource{
 									LocalObjectReference: corev1.LocalObjectReference{
-										Name: "multiarch-tuning-operator-trusted-ca",
+										Name: defaultTrustedCAConfigMapName,
 									},
 									Items: []corev1.KeyToPath{
 										{
@@ -270,6 +391,30 @@ func buildDeployment(logVerbosity int,
 	}
 }
 
+// buildPodDisruptionBudget creates the PodDisruptionBudget of the Deployment with the given name. At most one pod is
+// disrupted at a time, e.g., while the nodes are drained.
+func buildPodDisruptionBudget(name string) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: utils.Namespace(),
+			Labels: map[string]string{
+				utils.OperandLabelKey:   operandName,
+				utils.ControllerNameKey: name,
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: utils.NewPtr(intstr.FromInt32(1)),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					utils.OperandLabelKey:   operandName,
+					utils.ControllerNameKey: name,
+				},
+			},
+		},
+	}
+}
+
 func buildClusterRole(name string, rules []rbacv1.PolicyRule) *rbacv1.ClusterRole {
 	return &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
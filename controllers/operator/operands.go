@@ -0,0 +1,70 @@
+package operator
+
+import (
+	"maps"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+// applyOperandConfig customizes the pods of an operand component with the settings of the ClusterPodPlacementConfig.
+// The first container of the pod spec is the one of the component.
+func applyOperandConfig(podSpec *corev1.PodSpec, config *v1beta1.OperandConfig) {
+	if config == nil {
+		return
+	}
+	container := &podSpec.Containers[0]
+	if config.Resources != nil {
+		container.Resources = *config.Resources.DeepCopy()
+	}
+	if len(config.NodeSelector) > 0 {
+		podSpec.NodeSelector = maps.Clone(config.NodeSelector)
+	}
+	for i := range config.Tolerations {
+		podSpec.Tolerations = append(podSpec.Tolerations, *config.Tolerations[i].DeepCopy())
+	}
+	if config.PriorityClassName != "" {
+		podSpec.PriorityClassName = config.PriorityClassName
+	}
+	for i := range config.Env {
+		env := *config.Env[i].DeepCopy()
+		if j := slices.IndexFunc(container.Env, func(e corev1.EnvVar) bool {
+			return e.Name == env.Name
+		}); j >= 0 {
+			container.Env[j] = env
+			continue
+		}
+		container.Env = append(container.Env, env)
+	}
+}
+
+// applyOperandDeploymentConfig customizes the Deployment of an operand component with the settings of the
+// ClusterPodPlacementConfig.
+func applyOperandDeploymentConfig(d *appsv1.Deployment, config *v1beta1.OperandDeploymentConfig) {
+	if config == nil {
+		return
+	}
+	applyOperandConfig(&d.Spec.Template.Spec, &config.OperandConfig)
+	if config.Replicas != nil {
+		d.Spec.Replicas = utils.NewPtr(*config.Replicas)
+	}
+	if len(config.TopologySpreadConstraints) == 0 {
+		return
+	}
+	constraints := make([]corev1.TopologySpreadConstraint, 0, len(config.TopologySpreadConstraints))
+	for i := range config.TopologySpreadConstraints {
+		constraint := *config.TopologySpreadConstraints[i].DeepCopy()
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = &metav1.LabelSelector{
+				MatchLabels: maps.Clone(d.Spec.Selector.MatchLabels),
+			}
+		}
+		constraints = append(constraints, constraint)
+	}
+	d.Spec.Template.Spec.TopologySpreadConstraints = constraints
+}
@@ -0,0 +1,78 @@
+package operator
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+func TestApplyOperandDeploymentConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+	}
+	cppc := &v1beta1.ClusterPodPlacementConfig{
+		Spec: v1beta1.ClusterPodPlacementConfigSpec{
+			Operands: &v1beta1.Operands{
+				PodPlacementWebhook: &v1beta1.OperandDeploymentConfig{
+					OperandConfig: v1beta1.OperandConfig{
+						Resources:         &resources,
+						NodeSelector:      map[string]string{"node-role.kubernetes.io/infra": ""},
+						Tolerations:       []corev1.Toleration{{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists}},
+						PriorityClassName: "high-priority",
+						Env: []corev1.EnvVar{
+							{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+							{Name: "GOMAXPROCS", Value: "2"},
+						},
+					},
+					Replicas: utils.NewPtr(int32(6)),
+					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+						MaxSkew:           1,
+						TopologyKey:       "topology.kubernetes.io/zone",
+						WhenUnsatisfiable: corev1.DoNotSchedule,
+					}},
+				},
+			},
+		},
+	}
+	d := buildWebhookDeployment(cppc, "", nil)
+	podSpec := d.Spec.Template.Spec
+	g.Expect(*d.Spec.Replicas).To(Equal(int32(6)))
+	g.Expect(podSpec.Containers[0].Resources).To(Equal(resources))
+	g.Expect(podSpec.NodeSelector).To(Equal(map[string]string{"node-role.kubernetes.io/infra": ""}))
+	g.Expect(podSpec.Tolerations).To(HaveLen(1))
+	g.Expect(podSpec.PriorityClassName).To(Equal("high-priority"))
+	g.Expect(podSpec.Containers[0].Env).To(ContainElements(
+		corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+		corev1.EnvVar{Name: "GOMAXPROCS", Value: "2"},
+		HaveField("Name", "NAMESPACE"),
+	))
+	g.Expect(podSpec.Containers[0].Env).To(HaveEach(Not(Equal(corev1.EnvVar{Name: "HTTPS_PROXY"}))),
+		"the HTTPS_PROXY variable set by the operator should be replaced")
+	g.Expect(podSpec.TopologySpreadConstraints).To(HaveLen(1))
+	g.Expect(podSpec.TopologySpreadConstraints[0].LabelSelector.MatchLabels).To(Equal(d.Spec.Selector.MatchLabels),
+		"the constraint should select the pods of the component by default")
+	g.Expect(cppc.Spec.Operands.PodPlacementWebhook.TopologySpreadConstraints[0].LabelSelector).To(BeNil(),
+		"the ClusterPodPlacementConfig should not be modified")
+
+	controller := buildControllerDeployment(cppc, "", nil)
+	g.Expect(*controller.Spec.Replicas).To(Equal(int32(2)), "the controller should keep the default replicas")
+	g.Expect(controller.Spec.Template.Spec.PriorityClassName).To(Equal(priorityClassName))
+}
+
+func TestApplyOperandConfig_daemonSet(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ds := buildDaemonSetENoExecEvent(utils.EnoexecDaemonSet, utils.EnoexecDaemonSet, 0, &v1beta1.OperandConfig{
+		NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+		Env:          []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}},
+	})
+	g.Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/os": "linux"}))
+	g.Expect(ds.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "HTTP_PROXY", Value: "http://proxy:3128"}))
+}
@@ -0,0 +1,433 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+const (
+	servingCertSecretNameAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+	injectCABundleAnnotation        = "service.beta.openshift.io/inject-cabundle"
+	injectCAFromAnnotation          = "cert-manager.io/inject-ca-from"
+
+	trustedCAVolumeName             = "trusted-ca"
+	defaultTrustedCAConfigMapName   = "multiarch-tuning-operator-trusted-ca"
+	openShiftGlobalPullSecretNS     = "openshift-config"
+	defaultGlobalPullSecretName     = "pull-secret"
+	certManagerSelfSignedIssuerName = "multiarch-tuning-operator-selfsigned-issuer"
+	globalPullSecretReaderSuffix    = "-global-pull-secret"
+	apiServerServiceName            = "kubernetes"
+	// globalPullSecretReaderLabel labels the Roles and RoleBindings granting the access to the global pull secret, so
+	// that the ones left in its previous namespace are found when the namespace changes.
+	globalPullSecretReaderLabel = "multiarch.openshift.io/global-pull-secret-reader"
+)
+
+var (
+	certManagerIssuerGVK       = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Issuer"}
+	certManagerCertificateGVK  = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+	certManagerIssuerGVR       = certManagerIssuerGVK.GroupVersion().WithResource("issuers")
+	certManagerCertificatesGVR = certManagerCertificateGVK.GroupVersion().WithResource("certificates")
+)
+
+// operandPlatform holds the settings of the operand that depend on the cluster it runs on: the provider of the
+// serving certificates, the global pull secret and the ConfigMap with the CA bundle of the registries.
+type operandPlatform struct {
+	// isOpenShift is true when the operand runs on OpenShift, e.g., where the SecurityContextConstraints apply.
+	isOpenShift      bool
+	tlsMode          v1beta1.TLSMode
+	globalPullSecret corev1.SecretReference
+	// caBundleConfigMap is empty when the CA bundle shipped in the operand image is used.
+	caBundleConfigMap string
+	// servingCABundle is the CA bundle of the self-managed serving certificates, injected into the webhook
+	// configurations in the SelfManaged TLS mode.
+	servingCABundle []byte
+	// apiServer holds the endpoints of the API server the NetworkPolicies of the operand components allow the
+	// traffic with. It is only resolved when the NetworkPolicies are enabled.
+	apiServer apiServerEndpoints
+}
+
+// apiServerEndpoints are the addresses and the ports of the endpoints of the default/kubernetes Service, i.e., of the
+// instances of the API server.
+type apiServerEndpoints struct {
+	addresses []string
+	ports     []int32
+}
+
+// peers returns the NetworkPolicyPeers selecting the addresses of the API server.
+func (e apiServerEndpoints) peers() []networkingv1.NetworkPolicyPeer {
+	peers := make([]networkingv1.NetworkPolicyPeer, 0, len(e.addresses))
+	for _, address := range e.addresses {
+		prefixLength := 128
+		if addr, err := netip.ParseAddr(address); err == nil && addr.Is4() {
+			prefixLength = 32
+		}
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{CIDR: fmt.Sprintf("%s/%d", address, prefixLength)},
+		})
+	}
+	return peers
+}
+
+// resolveOperandPlatform resolves the operandPlatform from the spec of the ClusterPodPlacementConfig, defaulting the
+// unset fields according to whether the cluster is OpenShift and whether it serves the cert-manager APIs.
+func resolveOperandPlatform(cppc *v1beta1.ClusterPodPlacementConfig, isOpenShift, certManagerAvailable bool) operandPlatform {
+	platform := operandPlatform{
+		isOpenShift: isOpenShift,
+		tlsMode:     cppc.Spec.TLSMode,
+		globalPullSecret: corev1.SecretReference{
+			Namespace: utils.Namespace(),
+			Name:      defaultGlobalPullSecretName,
+		},
+	}
+	if isOpenShift {
+		platform.globalPullSecret.Namespace = openShiftGlobalPullSecretNS
+		platform.caBundleConfigMap = defaultTrustedCAConfigMapName
+	}
+	if platform.tlsMode == "" {
+		switch {
+		case isOpenShift:
+			platform.tlsMode = v1beta1.TLSModeServiceCA
+		case certManagerAvailable:
+			platform.tlsMode = v1beta1.TLSModeCertManager
+		default:
+			platform.tlsMode = v1beta1.TLSModeSelfManaged
+		}
+	}
+	if ref := cppc.Spec.GlobalPullSecretRef; ref != nil {
+		if ref.Name != "" {
+			platform.globalPullSecret.Name = ref.Name
+		}
+		if ref.Namespace != "" {
+			platform.globalPullSecret.Namespace = ref.Namespace
+		}
+	}
+	if ref := cppc.Spec.CABundleConfigMapRef; ref != nil && ref.Name != "" {
+		platform.caBundleConfigMap = ref.Name
+	}
+	return platform
+}
+
+// operandPlatform detects the APIs served by the cluster and resolves the operandPlatform for the given
+// ClusterPodPlacementConfig. It fails when the cluster cannot be told to be OpenShift or not: the settings of the other
+// clusters must not be applied to OpenShift because of a transient discovery error.
+func (r *ClusterPodPlacementConfigReconciler) operandPlatform(ctx context.Context,
+	cppc *v1beta1.ClusterPodPlacementConfig) (operandPlatform, error) {
+	isOpenShift, err := utils.IsOpenShift(r.ClientSet.Discovery())
+	if err != nil {
+		return operandPlatform{}, fmt.Errorf("unable to detect whether the cluster is OpenShift: %w", err)
+	}
+	certManagerAvailable := utils.IsResourceAvailable(ctx, r.DynamicClient, certManagerCertificatesGVR)
+	platform := resolveOperandPlatform(cppc, isOpenShift, certManagerAvailable)
+	ctrllog.FromContext(ctx).V(1).Info("Resolved the operand platform", "isOpenShift", isOpenShift,
+		"certManagerAvailable", certManagerAvailable, "tlsMode", platform.tlsMode,
+		"globalPullSecret", platform.globalPullSecret, "caBundleConfigMap", platform.caBundleConfigMap)
+	return platform, nil
+}
+
+// apiServerEndpoints lists the EndpointSlices of the default/kubernetes Service to resolve the endpoints of the API
+// server. The ClusterPodPlacementConfig is reconciled again when they change, see SetupWithManager.
+func (r *ClusterPodPlacementConfigReconciler) apiServerEndpoints(ctx context.Context) (apiServerEndpoints, error) {
+	endpointSlices, err := r.ClientSet.DiscoveryV1().EndpointSlices(metav1.NamespaceDefault).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, apiServerServiceName),
+	})
+	if err != nil {
+		return apiServerEndpoints{}, err
+	}
+	addresses, ports := sets.New[string](), sets.New[int32]()
+	for _, endpointSlice := range endpointSlices.Items {
+		for _, endpoint := range endpointSlice.Endpoints {
+			addresses.Insert(endpoint.Addresses...)
+		}
+		for _, port := range endpointSlice.Ports {
+			if port.Port != nil {
+				ports.Insert(*port.Port)
+			}
+		}
+	}
+	if addresses.Len() == 0 || ports.Len() == 0 {
+		return apiServerEndpoints{}, fmt.Errorf("no endpoints found for the %s/%s Service",
+			metav1.NamespaceDefault, apiServerServiceName)
+	}
+	return apiServerEndpoints{addresses: sets.List(addresses), ports: sets.List(ports)}, nil
+}
+
+// isAPIServerEndpointSlice returns whether the object is an EndpointSlice of the default/kubernetes Service.
+func isAPIServerEndpointSlice(o client.Object) bool {
+	return o.GetNamespace() == metav1.NamespaceDefault && o.GetLabels()[discoveryv1.LabelServiceName] == apiServerServiceName
+}
+
+// applyPlatform adapts the operand objects to the platform and returns them with the additional objects the
+// platform requires, i.e., the cert-manager Issuer and Certificates in the CertManager TLS mode and the Role granting
+// the pod placement webhook the access to the global pull secret, when it inspects the images.
+func (p operandPlatform) applyPlatform(objects []client.Object) []client.Object {
+	var certificates, globalPullSecretReaders []client.Object
+	for _, o := range objects {
+		switch t := o.(type) {
+		case *corev1.Service:
+			p.applyServiceAnnotations(&t.ObjectMeta, t.Name)
+			if p.tlsMode == v1beta1.TLSModeCertManager {
+				certificates = append(certificates, buildCertManagerCertificate(t.Name))
+			}
+		case *admissionv1.MutatingWebhookConfiguration:
+			p.applyWebhookConfigurationAnnotations(&t.ObjectMeta)
+			for i := range t.Webhooks {
+				p.applyServingCABundle(&t.Webhooks[i].ClientConfig)
+			}
+		case *admissionv1.ValidatingWebhookConfiguration:
+			p.applyWebhookConfigurationAnnotations(&t.ObjectMeta)
+			for i := range t.Webhooks {
+				p.applyServingCABundle(&t.Webhooks[i].ClientConfig)
+			}
+		case *appsv1.Deployment:
+			p.applyTrustedCA(&t.Spec.Template.Spec)
+			if t.Name == utils.PodPlacementControllerName || t.Name == utils.PodPlacementWebhookName {
+				p.applyGlobalPullSecretArgs(&t.Spec.Template.Spec)
+			}
+			if t.Name == utils.PodPlacementWebhookName && hasRegistriesConfig(&t.Spec.Template.Spec) {
+				globalPullSecretReaders = append(globalPullSecretReaders, p.buildGlobalPullSecretRole(t.Name),
+					p.buildGlobalPullSecretRoleBinding(t.Name))
+			}
+		}
+	}
+	if len(certificates) > 0 {
+		objects = append(append(objects, buildCertManagerSelfSignedIssuer()), certificates...)
+	}
+	return append(objects, globalPullSecretReaders...)
+}
+
+// buildGlobalPullSecretRole creates the Role granting the read access to the global pull secret only, in its
+// namespace, for the components that are not allowed to list the secrets cluster-wide.
+func (p operandPlatform) buildGlobalPullSecretRole(name string) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + globalPullSecretReaderSuffix,
+			Namespace: p.globalPullSecret.Namespace,
+			Labels: map[string]string{
+				utils.OperandLabelKey:       operandName,
+				utils.ControllerNameKey:     name,
+				globalPullSecretReaderLabel: name,
+			},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{""},
+				Resources:     []string{"secrets"},
+				ResourceNames: []string{p.globalPullSecret.Name},
+				Verbs:         []string{GET, LIST, WATCH},
+			},
+		},
+	}
+}
+
+// buildGlobalPullSecretRoleBinding binds the Role built by buildGlobalPullSecretRole to the service account of the
+// given component.
+func (p operandPlatform) buildGlobalPullSecretRoleBinding(name string) *rbacv1.RoleBinding {
+	roleBinding := buildRoleBinding(name+globalPullSecretReaderSuffix, rbacv1.RoleRef{
+		APIGroup: rbacv1.GroupName,
+		Kind:     roleKind,
+		Name:     name + globalPullSecretReaderSuffix,
+	}, []rbacv1.Subject{
+		{
+			Kind:      serviceAccountKind,
+			Name:      name,
+			Namespace: utils.Namespace(),
+		},
+	})
+	roleBinding.Namespace = p.globalPullSecret.Namespace
+	roleBinding.Labels[utils.ControllerNameKey] = name
+	roleBinding.Labels[globalPullSecretReaderLabel] = name
+	return roleBinding
+}
+
+// deleteGlobalPullSecretReaders deletes the Roles and RoleBindings granting the access to the global pull secret that
+// are not in the given namespace, e.g., the ones left in the previous namespace of the global pull secret. All of them
+// are deleted when the namespace is empty.
+func (r *ClusterPodPlacementConfigReconciler) deleteGlobalPullSecretReaders(ctx context.Context, namespace string) error {
+	log := ctrllog.FromContext(ctx)
+	listOptions := metav1.ListOptions{LabelSelector: globalPullSecretReaderLabel}
+	roles, err := r.ClientSet.RbacV1().Roles(metav1.NamespaceAll).List(ctx, listOptions)
+	if err != nil {
+		return err
+	}
+	roleBindings, err := r.ClientSet.RbacV1().RoleBindings(metav1.NamespaceAll).List(ctx, listOptions)
+	if err != nil {
+		return err
+	}
+	toDelete := make([]utils.ToDeleteRef, 0)
+	for _, role := range roles.Items {
+		if role.Namespace != namespace {
+			toDelete = append(toDelete, utils.ToDeleteRef{
+				NamespacedTypedClient: r.ClientSet.RbacV1().Roles(role.Namespace),
+				ObjName:               role.Name,
+			})
+		}
+	}
+	for _, roleBinding := range roleBindings.Items {
+		if roleBinding.Namespace != namespace {
+			toDelete = append(toDelete, utils.ToDeleteRef{
+				NamespacedTypedClient: r.ClientSet.RbacV1().RoleBindings(roleBinding.Namespace),
+				ObjName:               roleBinding.Name,
+			})
+		}
+	}
+	if len(toDelete) == 0 {
+		return nil
+	}
+	log.Info("Deleting the access to the global pull secret outside of its namespace", "namespace", namespace)
+	return utils.DeleteResources(ctx, toDelete)
+}
+
+// applyServiceAnnotations sets the annotation requesting the service CA operator to issue the serving certificate in
+// the ServiceCA TLS mode, and requests its removal otherwise.
+func (p operandPlatform) applyServiceAnnotations(meta *metav1.ObjectMeta, name string) {
+	if p.tlsMode == v1beta1.TLSModeServiceCA {
+		setAnnotation(meta, servingCertSecretNameAnnotation, name)
+		return
+	}
+	removeAnnotation(meta, servingCertSecretNameAnnotation)
+}
+
+// applyWebhookConfigurationAnnotations sets the annotation requesting the injection of the CA bundle by the service CA
+// operator or by the cert-manager CA injector, according to the TLS mode, and requests the removal of the other one.
+func (p operandPlatform) applyWebhookConfigurationAnnotations(meta *metav1.ObjectMeta) {
+	removeAnnotation(meta, injectCABundleAnnotation)
+	removeAnnotation(meta, injectCAFromAnnotation)
+	switch p.tlsMode {
+	case v1beta1.TLSModeServiceCA:
+		setAnnotation(meta, injectCABundleAnnotation, "true")
+	case v1beta1.TLSModeCertManager:
+		setAnnotation(meta, injectCAFromAnnotation, fmt.Sprintf("%s/%s", utils.Namespace(), utils.PodPlacementWebhookName))
+	}
+}
+
+// applyServingCABundle sets the CA bundle of the self-managed serving certificates in the SelfManaged TLS mode. In the
+// other modes, the CA bundle is left empty and the one injected by the service CA operator or by cert-manager is kept
+// when the webhook configuration is applied.
+func (p operandPlatform) applyServingCABundle(clientConfig *admissionv1.WebhookClientConfig) {
+	if p.tlsMode == v1beta1.TLSModeSelfManaged {
+		clientConfig.CABundle = p.servingCABundle
+	}
+}
+
+// applyTrustedCA mounts the ConfigMap with the CA bundle of the registries, or drops the trusted-ca volume when the
+// CA bundle shipped in the operand image is used.
+func (p operandPlatform) applyTrustedCA(podSpec *corev1.PodSpec) {
+	if p.caBundleConfigMap != "" {
+		for i := range podSpec.Volumes {
+			if podSpec.Volumes[i].Name == trustedCAVolumeName && podSpec.Volumes[i].ConfigMap != nil {
+				podSpec.Volumes[i].ConfigMap.Name = p.caBundleConfigMap
+			}
+		}
+		return
+	}
+	podSpec.Volumes = slices.DeleteFunc(podSpec.Volumes, func(v corev1.Volume) bool {
+		return v.Name == trustedCAVolumeName
+	})
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].VolumeMounts = slices.DeleteFunc(podSpec.Containers[i].VolumeMounts,
+			func(m corev1.VolumeMount) bool {
+				return m.Name == trustedCAVolumeName
+			})
+	}
+}
+
+// applyGlobalPullSecretArgs passes the reference to the global pull secret to the containers of the operand.
+func (p operandPlatform) applyGlobalPullSecretArgs(podSpec *corev1.PodSpec) {
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].Args = append(podSpec.Containers[i].Args,
+			fmt.Sprintf("--global-pull-secret-namespace=%s", p.globalPullSecret.Namespace),
+			fmt.Sprintf("--global-pull-secret-name=%s", p.globalPullSecret.Name))
+	}
+}
+
+// deleteCertManagerObjects deletes the cert-manager Issuer and Certificates created for the operand Services when
+// the TLS mode is no longer CertManager.
+func (r *ClusterPodPlacementConfigReconciler) deleteCertManagerObjects(ctx context.Context) error {
+	issuers := utils.NewDynamicDeleter(r.DynamicClient.Resource(certManagerIssuerGVR).Namespace(utils.Namespace()))
+	certificates := utils.NewDynamicDeleter(r.DynamicClient.Resource(certManagerCertificatesGVR).Namespace(utils.Namespace()))
+	return utils.DeleteResources(ctx, []utils.ToDeleteRef{
+		{NamespacedTypedClient: certificates, ObjName: utils.PodPlacementControllerName},
+		{NamespacedTypedClient: certificates, ObjName: utils.PodPlacementWebhookName},
+		{NamespacedTypedClient: certificates, ObjName: utils.EnoexecControllerName},
+		{NamespacedTypedClient: issuers, ObjName: certManagerSelfSignedIssuerName},
+	})
+}
+
+// buildCertManagerSelfSignedIssuer creates the cert-manager Issuer signing the serving certificates of the operand.
+func buildCertManagerSelfSignedIssuer() *unstructured.Unstructured {
+	issuer := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"selfSigned": map[string]interface{}{},
+		},
+	}}
+	issuer.SetGroupVersionKind(certManagerIssuerGVK)
+	issuer.SetName(certManagerSelfSignedIssuerName)
+	issuer.SetNamespace(utils.Namespace())
+	issuer.SetLabels(map[string]string{
+		utils.OperandLabelKey: operandName,
+	})
+	return issuer
+}
+
+// buildCertManagerCertificate creates the cert-manager Certificate of the serving certificate of the given Service.
+// The certificate is stored in the Secret named after the Service, as the service CA operator does.
+func buildCertManagerCertificate(serviceName string) *unstructured.Unstructured {
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"secretName": serviceName,
+			"dnsNames": []interface{}{
+				fmt.Sprintf("%s.%s.svc", serviceName, utils.Namespace()),
+				fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, utils.Namespace()),
+			},
+			"issuerRef": map[string]interface{}{
+				"name": certManagerSelfSignedIssuerName,
+				"kind": certManagerIssuerGVK.Kind,
+			},
+		},
+	}}
+	certificate.SetGroupVersionKind(certManagerCertificateGVK)
+	certificate.SetName(serviceName)
+	certificate.SetNamespace(utils.Namespace())
+	certificate.SetLabels(map[string]string{
+		utils.OperandLabelKey:   operandName,
+		utils.ControllerNameKey: serviceName,
+	})
+	return certificate
+}
+
+func setAnnotation(meta *metav1.ObjectMeta, key, value string) {
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	delete(meta.Annotations, key+"-")
+	meta.Annotations[key] = value
+}
+
+// removeAnnotation requests the removal of the annotation from the object in the cluster: the keys ending with "-"
+// are removed by the resourcemerge package when the object is applied.
+func removeAnnotation(meta *metav1.ObjectMeta, key string) {
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	delete(meta.Annotations, key)
+	meta.Annotations[key+"-"] = ""
+}
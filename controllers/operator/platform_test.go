@@ -0,0 +1,251 @@
+package operator
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+func TestResolveOperandPlatform(t *testing.T) {
+	tests := []struct {
+		name                 string
+		spec                 v1beta1.ClusterPodPlacementConfigSpec
+		isOpenShift          bool
+		certManagerAvailable bool
+		want                 operandPlatform
+	}{
+		{
+			name:                 "openshift defaults",
+			isOpenShift:          true,
+			certManagerAvailable: true,
+			want: operandPlatform{
+				isOpenShift:       true,
+				tlsMode:           v1beta1.TLSModeServiceCA,
+				globalPullSecret:  corev1.SecretReference{Namespace: "openshift-config", Name: "pull-secret"},
+				caBundleConfigMap: defaultTrustedCAConfigMapName,
+			},
+		},
+		{
+			name:                 "kubernetes with cert-manager defaults",
+			certManagerAvailable: true,
+			want: operandPlatform{
+				tlsMode:          v1beta1.TLSModeCertManager,
+				globalPullSecret: corev1.SecretReference{Namespace: utils.Namespace(), Name: "pull-secret"},
+			},
+		},
+		{
+			name: "kubernetes without cert-manager defaults",
+			want: operandPlatform{
+				tlsMode:          v1beta1.TLSModeSelfManaged,
+				globalPullSecret: corev1.SecretReference{Namespace: utils.Namespace(), Name: "pull-secret"},
+			},
+		},
+		{
+			name: "explicit settings",
+			spec: v1beta1.ClusterPodPlacementConfigSpec{
+				TLSMode:              v1beta1.TLSModeSelfManaged,
+				GlobalPullSecretRef:  &corev1.SecretReference{Namespace: "registries", Name: "credentials"},
+				CABundleConfigMapRef: &corev1.LocalObjectReference{Name: "registries-ca"},
+			},
+			isOpenShift: true,
+			want: operandPlatform{
+				isOpenShift:       true,
+				tlsMode:           v1beta1.TLSModeSelfManaged,
+				globalPullSecret:  corev1.SecretReference{Namespace: "registries", Name: "credentials"},
+				caBundleConfigMap: "registries-ca",
+			},
+		},
+		{
+			name: "pull secret name without namespace",
+			spec: v1beta1.ClusterPodPlacementConfigSpec{
+				GlobalPullSecretRef: &corev1.SecretReference{Name: "credentials"},
+			},
+			isOpenShift: true,
+			want: operandPlatform{
+				isOpenShift:       true,
+				tlsMode:           v1beta1.TLSModeServiceCA,
+				globalPullSecret:  corev1.SecretReference{Namespace: "openshift-config", Name: "credentials"},
+				caBundleConfigMap: defaultTrustedCAConfigMapName,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			cppc := &v1beta1.ClusterPodPlacementConfig{Spec: tt.spec}
+			g.Expect(resolveOperandPlatform(cppc, tt.isOpenShift, tt.certManagerAvailable)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestOperandPlatform_applyPlatform(t *testing.T) {
+	tests := []struct {
+		name                    string
+		platform                operandPlatform
+		wantServiceAnnotations  map[string]string
+		wantWebhookAnnotations  map[string]string
+		wantTrustedCAConfigMap  string
+		wantCertManagerObjects  int
+		wantGlobalPullSecretArg string
+		wantCABundle            []byte
+	}{
+		{
+			name: "service-ca",
+			platform: operandPlatform{
+				tlsMode:           v1beta1.TLSModeServiceCA,
+				globalPullSecret:  corev1.SecretReference{Namespace: "openshift-config", Name: "pull-secret"},
+				caBundleConfigMap: defaultTrustedCAConfigMapName,
+			},
+			wantServiceAnnotations: map[string]string{
+				servingCertSecretNameAnnotation: utils.PodPlacementWebhookName,
+			},
+			wantWebhookAnnotations: map[string]string{
+				injectCABundleAnnotation:     "true",
+				injectCAFromAnnotation + "-": "",
+			},
+			wantTrustedCAConfigMap:  defaultTrustedCAConfigMapName,
+			wantGlobalPullSecretArg: "--global-pull-secret-namespace=openshift-config",
+		},
+		{
+			name: "cert-manager",
+			platform: operandPlatform{
+				tlsMode:           v1beta1.TLSModeCertManager,
+				globalPullSecret:  corev1.SecretReference{Namespace: "registries", Name: "credentials"},
+				caBundleConfigMap: "registries-ca",
+			},
+			wantServiceAnnotations: map[string]string{
+				servingCertSecretNameAnnotation + "-": "",
+			},
+			wantWebhookAnnotations: map[string]string{
+				injectCABundleAnnotation + "-": "",
+				injectCAFromAnnotation:         utils.Namespace() + "/" + utils.PodPlacementWebhookName,
+			},
+			wantTrustedCAConfigMap:  "registries-ca",
+			wantCertManagerObjects:  2,
+			wantGlobalPullSecretArg: "--global-pull-secret-namespace=registries",
+		},
+		{
+			name: "self-managed",
+			platform: operandPlatform{
+				tlsMode:          v1beta1.TLSModeSelfManaged,
+				globalPullSecret: corev1.SecretReference{Namespace: "default", Name: "pull-secret"},
+				servingCABundle:  []byte("ca-bundle"),
+			},
+			wantServiceAnnotations: map[string]string{
+				servingCertSecretNameAnnotation + "-": "",
+			},
+			wantWebhookAnnotations: map[string]string{
+				injectCABundleAnnotation + "-": "",
+				injectCAFromAnnotation + "-":   "",
+			},
+			wantGlobalPullSecretArg: "--global-pull-secret-namespace=default",
+			wantCABundle:            []byte("ca-bundle"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			cppc := &v1beta1.ClusterPodPlacementConfig{Spec: v1beta1.ClusterPodPlacementConfigSpec{
+				ValidatingPolicy: &v1beta1.ValidatingPolicy{Mode: v1beta1.RequireCommonArchitecture},
+			}}
+			service := buildService(utils.PodPlacementWebhookName)
+			mwc := buildMutatingWebhookConfiguration(cppc)
+			deployment := buildWebhookDeployment(cppc, "", nil)
+			objects := tt.platform.applyPlatform([]client.Object{service, mwc, deployment})
+
+			g.Expect(service.Annotations).To(Equal(tt.wantServiceAnnotations))
+			g.Expect(mwc.Annotations).To(Equal(tt.wantWebhookAnnotations))
+			g.Expect(mwc.Webhooks[0].ClientConfig.CABundle).To(Equal(tt.wantCABundle))
+			g.Expect(deployment.Spec.Template.Spec.Containers[0].Args).To(ContainElement(tt.wantGlobalPullSecretArg))
+			if tt.wantTrustedCAConfigMap == "" {
+				g.Expect(deployment.Spec.Template.Spec.Volumes).NotTo(ContainElement(
+					HaveField("Name", trustedCAVolumeName)))
+				g.Expect(deployment.Spec.Template.Spec.Containers[0].VolumeMounts).NotTo(ContainElement(
+					HaveField("Name", trustedCAVolumeName)))
+			} else {
+				g.Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(SatisfyAll(
+					HaveField("Name", trustedCAVolumeName),
+					HaveField("ConfigMap.Name", tt.wantTrustedCAConfigMap))))
+			}
+			g.Expect(objects).To(HaveLen(3 + tt.wantCertManagerObjects + 2))
+			for _, o := range objects[3 : 3+tt.wantCertManagerObjects] {
+				g.Expect(o).To(BeAssignableToTypeOf(&unstructured.Unstructured{}))
+				g.Expect(o.GetObjectKind().GroupVersionKind().Group).To(Equal("cert-manager.io"))
+			}
+			role, ok := objects[len(objects)-2].(*rbacv1.Role)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(role.Namespace).To(Equal(tt.platform.globalPullSecret.Namespace))
+			g.Expect(role.Labels).To(HaveKeyWithValue(globalPullSecretReaderLabel, utils.PodPlacementWebhookName))
+			g.Expect(role.Rules).To(ConsistOf(HaveField("ResourceNames",
+				ConsistOf(tt.platform.globalPullSecret.Name))))
+			roleBinding, ok := objects[len(objects)-1].(*rbacv1.RoleBinding)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(roleBinding.Namespace).To(Equal(tt.platform.globalPullSecret.Namespace))
+			g.Expect(roleBinding.Labels).To(HaveKeyWithValue(globalPullSecretReaderLabel, utils.PodPlacementWebhookName))
+			g.Expect(roleBinding.RoleRef.Name).To(Equal(role.Name))
+			g.Expect(roleBinding.Subjects).To(ConsistOf(SatisfyAll(
+				HaveField("Name", utils.PodPlacementWebhookName),
+				HaveField("Namespace", utils.Namespace()))))
+		})
+	}
+}
+
+func TestOperandPlatform_applyPlatform_noImageInspection(t *testing.T) {
+	g := NewGomegaWithT(t)
+	platform := operandPlatform{
+		tlsMode:          v1beta1.TLSModeServiceCA,
+		globalPullSecret: corev1.SecretReference{Namespace: "openshift-config", Name: "pull-secret"},
+	}
+	deployment := buildWebhookDeployment(&v1beta1.ClusterPodPlacementConfig{}, "", nil)
+	objects := platform.applyPlatform([]client.Object{deployment})
+	g.Expect(objects).To(ConsistOf(deployment),
+		"the webhook should not be granted the access to the global pull secret when it does not inspect the images")
+}
+
+func TestIsAPIServerEndpointSlice(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		labels    map[string]string
+		want      bool
+	}{
+		{
+			name:      "api server",
+			namespace: metav1.NamespaceDefault,
+			labels:    map[string]string{discoveryv1.LabelServiceName: apiServerServiceName},
+			want:      true,
+		},
+		{
+			name:      "other service",
+			namespace: metav1.NamespaceDefault,
+			labels:    map[string]string{discoveryv1.LabelServiceName: "other"},
+			want:      false,
+		},
+		{
+			name:      "other namespace",
+			namespace: "openshift-multiarch-tuning-operator",
+			labels:    map[string]string{discoveryv1.LabelServiceName: apiServerServiceName},
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			endpointSlice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: tt.namespace, Labels: tt.labels},
+			}
+			g.Expect(isAPIServerEndpointSlice(endpointSlice)).To(Equal(tt.want))
+		})
+	}
+}
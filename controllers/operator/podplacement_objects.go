@@ -2,20 +2,32 @@ package operator
 
 import (
 	"fmt"
+	"slices"
+	"time"
 
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
 	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
 	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
 )
 
+const (
+	defaultWebhookAutoscalingMinReplicas = 3
+	defaultWebhookTargetCPUUtilization   = 80
+	defaultWebhookTargetResponseTime     = 100 * time.Millisecond
+)
+
 // buildMutatingWebhookConfiguration creates the MutatingWebhookConfiguration for the pod placement webhook.
 func buildMutatingWebhookConfiguration(clusterPodPlacementConfig *v1beta1.ClusterPodPlacementConfig) *admissionv1.MutatingWebhookConfiguration {
 	return &admissionv1.MutatingWebhookConfiguration{
@@ -25,9 +37,6 @@ func buildMutatingWebhookConfiguration(clusterPodPlacementConfig *v1beta1.Cluste
 				utils.OperandLabelKey:   operandName,
 				utils.ControllerNameKey: utils.PodPlacementWebhookName,
 			},
-			Annotations: map[string]string{
-				"service.beta.openshift.io/inject-cabundle": "true",
-			},
 		},
 		Webhooks: []admissionv1.MutatingWebhook{
 			{
@@ -42,7 +51,53 @@ func buildMutatingWebhookConfiguration(clusterPodPlacementConfig *v1beta1.Cluste
 				NamespaceSelector: clusterPodPlacementConfig.Spec.NamespaceSelector,
 				FailurePolicy:     utils.NewPtr(admissionv1.Ignore),
 				SideEffects:       utils.NewPtr(admissionv1.SideEffectClassNone),
-				Name:              utils.PodMutatingWebhookName,
+				// Other mutating webhooks, e.g., service meshes, can add containers after the pod is gated:
+				// reinvoking the webhook lets it record the decisions about the injected containers too.
+				ReinvocationPolicy: utils.NewPtr(admissionv1.IfNeededReinvocationPolicy),
+				Name:               utils.PodMutatingWebhookName,
+				Rules: []admissionv1.RuleWithOperations{
+					{
+						Operations: []admissionv1.OperationType{
+							admissionv1.Create,
+						},
+						Rule: admissionv1.Rule{
+							APIGroups:   []string{""},
+							APIVersions: []string{"v1"},
+							Resources:   []string{"pods"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// buildValidatingWebhookConfiguration creates the ValidatingWebhookConfiguration enforcing the validating policy of
+// the ClusterPodPlacementConfig. The failure policy is Ignore: the pods are admitted when the webhook is unavailable.
+func buildValidatingWebhookConfiguration(clusterPodPlacementConfig *v1beta1.ClusterPodPlacementConfig) *admissionv1.ValidatingWebhookConfiguration {
+	return &admissionv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: utils.PodValidatingWebhookConfigurationName,
+			Labels: map[string]string{
+				utils.OperandLabelKey:   operandName,
+				utils.ControllerNameKey: utils.PodPlacementWebhookName,
+			},
+		},
+		Webhooks: []admissionv1.ValidatingWebhook{
+			{
+				AdmissionReviewVersions: []string{"v1"},
+				ClientConfig: admissionv1.WebhookClientConfig{
+					Service: &admissionv1.ServiceReference{
+						Name:      utils.PodPlacementWebhookName,
+						Namespace: utils.Namespace(),
+						Path:      utils.NewPtr(utils.PodValidatingWebhookPath),
+					},
+				},
+				NamespaceSelector: clusterPodPlacementConfig.Spec.NamespaceSelector,
+				FailurePolicy:     utils.NewPtr(admissionv1.Ignore),
+				SideEffects:       utils.NewPtr(admissionv1.SideEffectClassNone),
+				TimeoutSeconds:    utils.NewPtr(int32(10)),
+				Name:              utils.PodValidatingWebhookName,
 				Rules: []admissionv1.RuleWithOperations{
 					{
 						Operations: []admissionv1.OperationType{
@@ -60,8 +115,17 @@ func buildMutatingWebhookConfiguration(clusterPodPlacementConfig *v1beta1.Cluste
 	}
 }
 
+// webhookInspectsImages returns whether the pod placement webhook inspects the images at admission time, i.e., when
+// the EmulationFallback plugin is enabled or the validating policy is set.
+func webhookInspectsImages(clusterPodPlacementConfig *v1beta1.ClusterPodPlacementConfig) bool {
+	return clusterPodPlacementConfig.Spec.Plugins.PluginEnabled(common.EmulationFallbackPluginName) ||
+		clusterPodPlacementConfig.Spec.ValidatingPolicy != nil
+}
+
 // buildWebhookDeployment creates the specific deployment for the pod-placement-webhook.
-func buildWebhookDeployment(clusterPodPlacementConfig *v1beta1.ClusterPodPlacementConfig) *appsv1.Deployment {
+// When the webhook inspects the images at admission time, it mounts the registries configuration of the nodes as the
+// controller does.
+func buildWebhookDeployment(clusterPodPlacementConfig *v1beta1.ClusterPodPlacementConfig, requiredSCCHostmoundAnyUID string, seLinuxOptionsType *corev1.SELinuxOptions) *appsv1.Deployment {
 	d := buildDeployment(clusterPodPlacementConfig.Spec.LogVerbosity.ToZapLevelInt(), utils.PodPlacementWebhookName, 3, utils.PodPlacementWebhookName, "",
 		"--enable-ppc-webhook", "--enable-cppc-informer",
 	)
@@ -87,19 +151,82 @@ func buildWebhookDeployment(clusterPodPlacementConfig *v1beta1.ClusterPodPlaceme
 	// 3. Append the additional items to the base slices.
 	d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, additionalVolumes...)
 	d.Spec.Template.Spec.Containers[0].VolumeMounts = append(d.Spec.Template.Spec.Containers[0].VolumeMounts, additionalMounts...)
+	if webhookInspectsImages(clusterPodPlacementConfig) {
+		applyRegistriesConfig(d, requiredSCCHostmoundAnyUID, seLinuxOptionsType)
+	}
 
+	applyOperandDeploymentConfig(d, clusterPodPlacementConfig.Spec.Operands.GetPodPlacementWebhook())
 	return d
 }
 
+// buildWebhookHorizontalPodAutoscaler creates the HorizontalPodAutoscaler scaling the pod placement webhook on either
+// the CPU utilization or the response time of its pods.
+func buildWebhookHorizontalPodAutoscaler(autoscaling *v1beta1.WebhookAutoscaling) *autoscalingv2.HorizontalPodAutoscaler {
+	minReplicas := int32(defaultWebhookAutoscalingMinReplicas)
+	if autoscaling.MinReplicas != nil {
+		minReplicas = *autoscaling.MinReplicas
+	}
+	var metric autoscalingv2.MetricSpec
+	if autoscaling.Metric == v1beta1.AutoscalingMetricResponseTime {
+		targetResponseTime := defaultWebhookTargetResponseTime
+		if autoscaling.TargetResponseTime != nil {
+			targetResponseTime = autoscaling.TargetResponseTime.Duration
+		}
+		metric = autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{
+					Name: metrics.AverageResponseTimeMetricName,
+				},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: resource.NewMilliQuantity(targetResponseTime.Milliseconds(), resource.DecimalSI),
+				},
+			},
+		}
+	} else {
+		targetCPUUtilization := int32(defaultWebhookTargetCPUUtilization)
+		if autoscaling.TargetCPUUtilizationPercentage != nil {
+			targetCPUUtilization = *autoscaling.TargetCPUUtilizationPercentage
+		}
+		metric = autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: utils.NewPtr(targetCPUUtilization),
+				},
+			},
+		}
+	}
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.PodPlacementWebhookName,
+			Namespace: utils.Namespace(),
+			Labels: map[string]string{
+				utils.OperandLabelKey:   operandName,
+				utils.ControllerNameKey: utils.PodPlacementWebhookName,
+			},
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Name:       utils.PodPlacementWebhookName,
+			},
+			MinReplicas: utils.NewPtr(minReplicas),
+			MaxReplicas: autoscaling.MaxReplicas,
+			Metrics:     []autoscalingv2.MetricSpec{metric},
+		},
+	}
+}
+
 // buildControllerDeployment creates the Deployment for the cluster pod placement config controller.
 func buildControllerDeployment(clusterPodPlacementConfig *v1beta1.ClusterPodPlacementConfig, requiredSCCHostmoundAnyUID string, seLinuxOptionsType *corev1.SELinuxOptions) *appsv1.Deployment {
 	d := buildDeployment(clusterPodPlacementConfig.Spec.LogVerbosity.ToZapLevelInt(), utils.PodPlacementControllerName, 2, utils.PodPlacementControllerName,
 		utils.PodPlacementFinalizerName, "--leader-elect", "--enable-ppc-controllers", "--enable-cppc-informer",
 	)
-	if d.Spec.Template.Annotations == nil {
-		d.Spec.Template.Annotations = map[string]string{}
-	}
-	d.Spec.Template.Annotations[requiredSCCAnnotation] = requiredSCCHostmoundAnyUID
 
 	// 2. Define all additional volumes and mounts needed for this specific controller.
 	additionalVolumes := []corev1.Volume{
@@ -112,6 +239,37 @@ func buildControllerDeployment(clusterPodPlacementConfig *v1beta1.ClusterPodPlac
 				},
 			},
 		},
+	}
+	additionalMounts := []corev1.VolumeMount{
+		{
+			Name:      "webhook-server-cert",
+			MountPath: "/var/run/manager/tls",
+			ReadOnly:  true,
+		},
+	}
+
+	// 3. Append the additional volumes and mounts to the base ones from the generic builder.
+	d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, additionalVolumes...)
+	d.Spec.Template.Spec.Containers[0].VolumeMounts = append(d.Spec.Template.Spec.Containers[0].VolumeMounts, additionalMounts...)
+	applyRegistriesConfig(d, requiredSCCHostmoundAnyUID, seLinuxOptionsType)
+
+	applyOperandDeploymentConfig(d, clusterPodPlacementConfig.Spec.Operands.GetPodPlacementController())
+	return d
+}
+
+// applyRegistriesConfig mounts the registries configuration of the nodes in the deployments of the components
+// inspecting the images, so that the mirrors, the insecure and the blocked registries are honored consistently.
+// The hostPath volumes require the hostmount-anyuid SCC on OpenShift: the requiredSCCHostmoundAnyUID is empty on the
+// other clusters.
+func applyRegistriesConfig(d *appsv1.Deployment, requiredSCCHostmoundAnyUID string, seLinuxOptionsType *corev1.SELinuxOptions) {
+	if d.Spec.Template.Annotations == nil {
+		d.Spec.Template.Annotations = map[string]string{}
+	}
+	if requiredSCCHostmoundAnyUID != "" {
+		d.Spec.Template.Annotations[requiredSCCAnnotation] = requiredSCCHostmoundAnyUID
+	}
+
+	additionalVolumes := []corev1.Volume{
 		{
 			Name: "docker-conf",
 			VolumeSource: corev1.VolumeSource{
@@ -139,11 +297,6 @@ func buildControllerDeployment(clusterPodPlacementConfig *v1beta1.ClusterPodPlac
 	}
 
 	additionalMounts := []corev1.VolumeMount{
-		{
-			Name:      "webhook-server-cert",
-			MountPath: "/var/run/manager/tls",
-			ReadOnly:  true,
-		},
 		{
 			Name:      "docker-conf",
 			MountPath: "/etc/docker/",
@@ -166,7 +319,6 @@ func buildControllerDeployment(clusterPodPlacementConfig *v1beta1.ClusterPodPlac
 		},
 	}
 
-	// 3. Append the additional volumes and mounts to the base ones from the generic builder.
 	d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, additionalVolumes...)
 	d.Spec.Template.Spec.Containers[0].Env = append(d.Spec.Template.Spec.Containers[0].Env, additionalEnv...)
 	d.Spec.Template.Spec.Containers[0].VolumeMounts = append(d.Spec.Template.Spec.Containers[0].VolumeMounts, additionalMounts...)
@@ -177,13 +329,21 @@ func buildControllerDeployment(clusterPodPlacementConfig *v1beta1.ClusterPodPlac
 		}
 		d.Spec.Template.Spec.Containers[0].SecurityContext.SELinuxOptions = seLinuxOptionsType
 	}
+}
 
-	return d
+// hasRegistriesConfig returns whether the registries configuration of the nodes is mounted by applyRegistriesConfig,
+// i.e., whether the component inspects the images.
+func hasRegistriesConfig(podSpec *corev1.PodSpec) bool {
+	return slices.ContainsFunc(podSpec.Volumes, func(v corev1.Volume) bool {
+		return v.Name == "containers-conf"
+	})
 }
 
 // buildClusterRoleWebhook defines the cluster-wide permissions required by the cluster pod placement config webhook.
-func buildClusterRoleWebhook() *rbacv1.ClusterRole {
-	return buildClusterRole(utils.PodPlacementWebhookName, []rbacv1.PolicyRule{
+// The access to the secrets and to the SCC mounting the registries configuration is granted only when the webhook
+// inspects the images.
+func buildClusterRoleWebhook(inspectsImages bool) *rbacv1.ClusterRole {
+	rules := []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{""},
 			Resources: []string{"events"},
@@ -199,6 +359,29 @@ func buildClusterRoleWebhook() *rbacv1.ClusterRole {
 			Resources: []string{"pods"},
 			Verbs:     []string{LIST, WATCH, GET},
 		},
+		{
+			// The EmulationFallback plugin reads the labels of the namespaces.
+			APIGroups: []string{""},
+			Resources: []string{"namespaces"},
+			Verbs:     []string{LIST, WATCH, GET},
+		},
+		{
+			// The ArchitectureSpread plugin is configured in the PodPlacementConfigs and reads the annotations of
+			// the owners of the pods.
+			APIGroups: []string{v1beta1.GroupVersion.Group},
+			Resources: []string{v1beta1.PodPlacementConfigResource},
+			Verbs:     []string{LIST, WATCH, GET},
+		},
+		{
+			APIGroups: []string{"apps"},
+			Resources: []string{"replicasets", "statefulsets"},
+			Verbs:     []string{GET},
+		},
+		{
+			APIGroups: []string{"batch"},
+			Resources: []string{"jobs"},
+			Verbs:     []string{GET},
+		},
 		{
 			APIGroups: []string{"authentication.k8s.io"},
 			Resources: []string{"tokenreviews"},
@@ -209,7 +392,25 @@ func buildClusterRoleWebhook() *rbacv1.ClusterRole {
 			Resources: []string{"subjectaccessreviews"},
 			Verbs:     []string{CREATE},
 		},
-	})
+	}
+	if inspectsImages {
+		rules = append(rules,
+			rbacv1.PolicyRule{
+				// The image pull secrets of the pods are read when the images are inspected at admission time. The
+				// global pull secret is granted by a Role in its namespace.
+				APIGroups: []string{""},
+				Resources: []string{"secrets"},
+				Verbs:     []string{GET},
+			},
+			rbacv1.PolicyRule{
+				// The registries configuration of the nodes is mounted from the host.
+				APIGroups: []string{"security.openshift.io"},
+				Resources: []string{"securitycontextconstraints"},
+				Verbs:     []string{USE},
+			},
+		)
+	}
+	return buildClusterRole(utils.PodPlacementWebhookName, rules)
 }
 
 // buildClusterRoleController defines the cluster-wide permissions required by the cluster pod placement controller.
@@ -225,6 +426,13 @@ func buildClusterRoleController() *rbacv1.ClusterRole {
 			Resources: []string{"pods"},
 			Verbs:     []string{LIST, WATCH, GET, UPDATE},
 		},
+		{
+			// The namespaceSelector of the ClusterPodPlacementConfig is evaluated again for the pods gated with a
+			// previous placement configuration.
+			APIGroups: []string{""},
+			Resources: []string{"namespaces"},
+			Verbs:     []string{LIST, WATCH, GET},
+		},
 		{
 			APIGroups: []string{""},
 			Resources: []string{"events"},
@@ -240,11 +448,33 @@ func buildClusterRoleController() *rbacv1.ClusterRole {
 			Resources: []string{v1beta1.ClusterPodPlacementConfigResource},
 			Verbs:     []string{LIST, WATCH, GET},
 		},
+		{
+			APIGroups: []string{v1beta1.GroupVersion.Group},
+			Resources: []string{v1beta1.ClusterPodPlacementConfigResource + "/status"},
+			Verbs:     []string{GET, PATCH},
+		},
+		{
+			// The allowed architectures can be set in the PodPlacementConfigs.
+			APIGroups: []string{v1beta1.GroupVersion.Group},
+			Resources: []string{v1beta1.PodPlacementConfigResource},
+			Verbs:     []string{LIST, WATCH, GET},
+		},
+		{
+			// The images published as per-architecture variants are described by the ImageRewriteRules.
+			APIGroups: []string{v1beta1.GroupVersion.Group},
+			Resources: []string{v1beta1.ImageRewriteRuleResource},
+			Verbs:     []string{LIST, WATCH, GET},
+		},
 		{
 			APIGroups: []string{""},
 			Resources: []string{"configmaps", "secrets"},
 			Verbs:     []string{LIST, WATCH, GET},
 		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"nodes"},
+			Verbs:     []string{LIST, WATCH, GET},
+		},
 		{
 			APIGroups: []string{"authentication.k8s.io"},
 			Resources: []string{"tokenreviews"},
@@ -258,6 +488,24 @@ func buildClusterRoleController() *rbacv1.ClusterRole {
 	})
 }
 
+// buildRoleWebhook defines the namespaced permissions required by the pod placement webhook to watch
+// the leader election Lease of the pod placement controllers.
+func buildRoleWebhook() *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.PodPlacementWebhookName,
+			Namespace: utils.Namespace(),
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"coordination.k8s.io"},
+				Resources: []string{"leases"},
+				Verbs:     []string{LIST, WATCH, GET},
+			},
+		},
+	}
+}
+
 // buildRoleController defines the namespace-scoped permissions for the pod placement controller.
 // These permissions are primarily for managing leader election leases within the operator's namespace.
 func buildRoleController() *rbacv1.Role {
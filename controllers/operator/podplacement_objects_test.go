@@ -0,0 +1,338 @@
+package operator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	metrics2 "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+func TestBuildWebhookHorizontalPodAutoscaler(t *testing.T) {
+	tests := []struct {
+		name            string
+		autoscaling     *v1beta1.WebhookAutoscaling
+		wantMinReplicas int32
+		wantMetric      autoscalingv2.MetricSpec
+	}{
+		{
+			name:            "cpu defaults",
+			autoscaling:     &v1beta1.WebhookAutoscaling{MaxReplicas: 10},
+			wantMinReplicas: defaultWebhookAutoscalingMinReplicas,
+			wantMetric: autoscalingv2.MetricSpec{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: utils.NewPtr(int32(defaultWebhookTargetCPUUtilization)),
+					},
+				},
+			},
+		},
+		{
+			name: "response time",
+			autoscaling: &v1beta1.WebhookAutoscaling{
+				MinReplicas:        utils.NewPtr(int32(2)),
+				MaxReplicas:        10,
+				Metric:             v1beta1.AutoscalingMetricResponseTime,
+				TargetResponseTime: &metav1.Duration{Duration: 250 * time.Millisecond},
+			},
+			wantMinReplicas: 2,
+			wantMetric: autoscalingv2.MetricSpec{
+				Type: autoscalingv2.PodsMetricSourceType,
+				Pods: &autoscalingv2.PodsMetricSource{
+					Metric: autoscalingv2.MetricIdentifier{Name: metrics.AverageResponseTimeMetricName},
+					Target: autoscalingv2.MetricTarget{
+						Type:         autoscalingv2.AverageValueMetricType,
+						AverageValue: utils.NewPtr(resource.MustParse("250m")),
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			hpa := buildWebhookHorizontalPodAutoscaler(tt.autoscaling)
+			g.Expect(hpa.Spec.ScaleTargetRef.Name).To(Equal(utils.PodPlacementWebhookName))
+			g.Expect(hpa.Spec.MinReplicas).To(Equal(utils.NewPtr(tt.wantMinReplicas)))
+			g.Expect(hpa.Spec.MaxReplicas).To(Equal(tt.autoscaling.MaxReplicas))
+			g.Expect(hpa.Spec.Metrics).To(HaveLen(1))
+			g.Expect(hpa.Spec.Metrics[0].Type).To(Equal(tt.wantMetric.Type))
+			g.Expect(hpa.Spec.Metrics[0].Resource).To(Equal(tt.wantMetric.Resource))
+			if tt.wantMetric.Pods != nil {
+				g.Expect(hpa.Spec.Metrics[0].Pods.Metric).To(Equal(tt.wantMetric.Pods.Metric))
+				g.Expect(hpa.Spec.Metrics[0].Pods.Target.AverageValue.Cmp(*tt.wantMetric.Pods.Target.AverageValue)).To(BeZero())
+			}
+		})
+	}
+}
+
+func TestWebhookResponseTimeMetric(t *testing.T) {
+	g := NewGomegaWithT(t)
+	metrics.InitWebhookMetrics()
+	families, err := metrics2.Registry.Gather()
+	g.Expect(err).NotTo(HaveOccurred())
+	histogram := false
+	for _, family := range families {
+		if family.GetName() == metrics.ResponseTimeMetricName {
+			histogram = family.GetType().String() == "HISTOGRAM"
+		}
+	}
+	g.Expect(histogram).To(BeTrue(), "the webhook should export the response time histogram")
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "config", "prometheus", "adapter", "rules.yaml"))
+	g.Expect(err).NotTo(HaveOccurred())
+	var config struct {
+		Rules []struct {
+			SeriesQuery string `json:"seriesQuery"`
+			Name        struct {
+				As string `json:"as"`
+			} `json:"name"`
+			MetricsQuery string `json:"metricsQuery"`
+		} `json:"rules"`
+	}
+	g.Expect(yaml.Unmarshal(data, &config)).To(Succeed())
+	g.Expect(config.Rules).To(HaveLen(1))
+	rule := config.Rules[0]
+	hpa := buildWebhookHorizontalPodAutoscaler(&v1beta1.WebhookAutoscaling{
+		MaxReplicas: 10, Metric: v1beta1.AutoscalingMetricResponseTime})
+	g.Expect(rule.Name.As).To(Equal(hpa.Spec.Metrics[0].Pods.Metric.Name),
+		"the adapter rule should serve the metric the webhook is scaled on")
+	g.Expect(rule.SeriesQuery).To(HavePrefix(metrics.ResponseTimeMetricName + "_count{"))
+	g.Expect(rule.MetricsQuery).To(And(
+		ContainSubstring(metrics.ResponseTimeMetricName+"_sum{"),
+		ContainSubstring(metrics.ResponseTimeMetricName+"_count{")))
+}
+
+func TestBuildDeployment_spread(t *testing.T) {
+	g := NewGomegaWithT(t)
+	d := buildWebhookDeployment(&v1beta1.ClusterPodPlacementConfig{}, "", nil)
+	g.Expect(d.Spec.Template.Spec.TopologySpreadConstraints).To(ContainElement(
+		HaveField("TopologyKey", "topology.kubernetes.io/zone")))
+	g.Expect(d.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(ContainElement(
+		HaveField("PodAffinityTerm.LabelSelector.MatchLabels", d.Spec.Selector.MatchLabels)))
+	g.Expect(buildPodDisruptionBudget(utils.PodPlacementWebhookName).Spec.Selector.MatchLabels).To(
+		Equal(d.Spec.Selector.MatchLabels), "the pod disruption budget should select the pods of the deployment")
+}
+
+func TestBuildWebhookDeployment_registriesConfig(t *testing.T) {
+	tests := []struct {
+		name               string
+		cppc               *v1beta1.ClusterPodPlacementConfig
+		wantInspectsImages bool
+	}{
+		{
+			name: "no image inspection at admission",
+			cppc: &v1beta1.ClusterPodPlacementConfig{},
+		},
+		{
+			name: "emulation fallback disabled",
+			cppc: &v1beta1.ClusterPodPlacementConfig{Spec: v1beta1.ClusterPodPlacementConfigSpec{
+				Plugins: &plugins.Plugins{EmulationFallback: &plugins.EmulationFallback{
+					BasePlugin: plugins.BasePlugin{Enabled: false}, RuntimeClassName: "qemu"}},
+			}},
+		},
+		{
+			name: "emulation fallback enabled",
+			cppc: &v1beta1.ClusterPodPlacementConfig{Spec: v1beta1.ClusterPodPlacementConfigSpec{
+				Plugins: &plugins.Plugins{EmulationFallback: &plugins.EmulationFallback{
+					BasePlugin: plugins.BasePlugin{Enabled: true}, RuntimeClassName: "qemu"}},
+			}},
+			wantInspectsImages: true,
+		},
+		{
+			name: "validating policy",
+			cppc: &v1beta1.ClusterPodPlacementConfig{Spec: v1beta1.ClusterPodPlacementConfigSpec{
+				ValidatingPolicy: &v1beta1.ValidatingPolicy{Mode: v1beta1.RequireCommonArchitecture},
+			}},
+			wantInspectsImages: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			seLinuxOptions := &corev1.SELinuxOptions{Type: "spc_t"}
+			g.Expect(webhookInspectsImages(tt.cppc)).To(Equal(tt.wantInspectsImages))
+			webhook := buildWebhookDeployment(tt.cppc, "hostmount-anyuid", seLinuxOptions)
+			controller := buildControllerDeployment(tt.cppc, "hostmount-anyuid", seLinuxOptions)
+			clusterRole := buildClusterRoleWebhook(webhookInspectsImages(tt.cppc))
+			secretsRule := SatisfyAll(
+				HaveField("Resources", ConsistOf("secrets")),
+				HaveField("Verbs", ConsistOf(GET)))
+			sccRule := HaveField("Resources", ConsistOf("securitycontextconstraints"))
+			g.Expect(hasRegistriesConfig(&webhook.Spec.Template.Spec)).To(Equal(tt.wantInspectsImages))
+			if !tt.wantInspectsImages {
+				for _, name := range []string{"docker-conf", "containers-conf", "shortnames-cache"} {
+					g.Expect(webhook.Spec.Template.Spec.Volumes).NotTo(ContainElement(HaveField("Name", name)))
+				}
+				g.Expect(webhook.Spec.Template.Annotations).NotTo(HaveKeyWithValue(requiredSCCAnnotation, "hostmount-anyuid"))
+				g.Expect(webhook.Spec.Template.Spec.Containers[0].SecurityContext.SELinuxOptions).To(BeNil())
+				g.Expect(clusterRole.Rules).NotTo(ContainElement(secretsRule),
+					"the webhook should not read the secrets when it does not inspect the images")
+				g.Expect(clusterRole.Rules).NotTo(ContainElement(sccRule))
+				return
+			}
+			for _, name := range []string{"docker-conf", "containers-conf", "shortnames-cache"} {
+				g.Expect(webhook.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Name", name)))
+				g.Expect(webhook.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(HaveField("Name", name)))
+			}
+			g.Expect(webhook.Spec.Template.Spec.Containers[0].Env).To(ContainElement(HaveField("Name", "XDG_CACHE_HOME")))
+			g.Expect(webhook.Spec.Template.Annotations).To(HaveKeyWithValue(requiredSCCAnnotation, "hostmount-anyuid"))
+			g.Expect(webhook.Spec.Template.Spec.Containers[0].SecurityContext.SELinuxOptions).To(Equal(seLinuxOptions))
+			g.Expect(webhook.Spec.Template.Spec.Containers[0].VolumeMounts).To(ConsistOf(
+				controller.Spec.Template.Spec.Containers[0].VolumeMounts),
+				"the webhook and the controller should inspect the images with the same registries configuration")
+			g.Expect(clusterRole.Rules).To(ContainElement(secretsRule))
+			g.Expect(clusterRole.Rules).To(ContainElement(sccRule))
+		})
+	}
+}
+
+func TestBuildWebhookDeployment_registriesConfigNotOpenShift(t *testing.T) {
+	g := NewGomegaWithT(t)
+	requiredSCC, seLinuxOptions, err := (&ClusterPodPlacementConfigReconciler{}).getCorrectHostmountAnyUIDSCC(
+		context.Background(), false)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requiredSCC).To(BeEmpty(), "no SCC should be required outside OpenShift")
+	g.Expect(seLinuxOptions).To(BeNil())
+	webhook := buildWebhookDeployment(&v1beta1.ClusterPodPlacementConfig{Spec: v1beta1.ClusterPodPlacementConfigSpec{
+		ValidatingPolicy: &v1beta1.ValidatingPolicy{Mode: v1beta1.RequireCommonArchitecture},
+	}}, requiredSCC, seLinuxOptions)
+	g.Expect(webhook.Spec.Template.Annotations).NotTo(HaveKeyWithValue(requiredSCCAnnotation, BeEmpty()))
+	g.Expect(webhook.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Name", "containers-conf")))
+}
+
+func TestBuildClusterRoleController(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(buildClusterRoleController().Rules).To(ContainElement(SatisfyAll(
+		HaveField("Resources", ConsistOf("namespaces")),
+		HaveField("Verbs", ConsistOf(LIST, WATCH, GET)))),
+		"the controller should read the namespaces it evaluates the namespaceSelector against")
+}
+
+func TestBuildNetworkPolicy(t *testing.T) {
+	monitoring := &metav1.LabelSelector{
+		MatchLabels: map[string]string{networkPolicyGroupLabel: networkPolicyGroupMonitoring},
+	}
+	apiServer := apiServerEndpoints{addresses: []string{"10.0.0.1", "fd00::1"}, ports: []int32{6443}}
+	apiServerPeers := []networkingv1.NetworkPolicyPeer{
+		{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.1/32"}},
+		{IPBlock: &networkingv1.IPBlock{CIDR: "fd00::1/128"}},
+	}
+	hostNetwork := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{networkPolicyHostNetworkLabel: ""}},
+	}
+	tests := []struct {
+		name                         string
+		config                       *v1beta1.OperandNetworkPolicies
+		isOpenShift                  bool
+		servesWebhook                bool
+		inspectsImages               bool
+		wantMetricsNamespaceSelector *metav1.LabelSelector
+		wantWebhookIngress           []networkingv1.NetworkPolicyPeer
+		wantRegistries               *networkingv1.NetworkPolicyEgressRule
+	}{
+		{
+			name:                         "enoexec controller",
+			config:                       &v1beta1.OperandNetworkPolicies{Enabled: true},
+			wantMetricsNamespaceSelector: monitoring,
+		},
+		{
+			name:                         "webhook with any registry",
+			config:                       &v1beta1.OperandNetworkPolicies{Enabled: true},
+			servesWebhook:                true,
+			inspectsImages:               true,
+			wantMetricsNamespaceSelector: monitoring,
+			wantWebhookIngress:           apiServerPeers,
+			wantRegistries:               &networkingv1.NetworkPolicyEgressRule{},
+		},
+		{
+			name:                         "webhook on OpenShift",
+			config:                       &v1beta1.OperandNetworkPolicies{Enabled: true},
+			isOpenShift:                  true,
+			servesWebhook:                true,
+			inspectsImages:               true,
+			wantMetricsNamespaceSelector: monitoring,
+			wantWebhookIngress:           append(slices.Clone(apiServerPeers), hostNetwork),
+			wantRegistries:               &networkingv1.NetworkPolicyEgressRule{},
+		},
+		{
+			name: "controller with registry CIDRs and metrics namespaces",
+			config: &v1beta1.OperandNetworkPolicies{
+				Enabled:       true,
+				RegistryCIDRs: []string{"10.0.0.0/16", "fd00::/64"},
+				MetricsNamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"kubernetes.io/metadata.name": "monitoring"},
+				},
+			},
+			inspectsImages: true,
+			wantMetricsNamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"kubernetes.io/metadata.name": "monitoring"},
+			},
+			wantRegistries: &networkingv1.NetworkPolicyEgressRule{
+				To: []networkingv1.NetworkPolicyPeer{
+					{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/16"}},
+					{IPBlock: &networkingv1.IPBlock{CIDR: "fd00::/64"}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			platform := operandPlatform{isOpenShift: tt.isOpenShift, apiServer: apiServer}
+			np := buildNetworkPolicy(utils.PodPlacementWebhookName, tt.config, platform, tt.servesWebhook, tt.inspectsImages)
+			g.Expect(np.Namespace).To(Equal(utils.Namespace()))
+			g.Expect(np.Spec.PodSelector.MatchLabels).To(Equal(buildService(utils.PodPlacementWebhookName).Spec.Selector))
+			g.Expect(np.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+
+			g.Expect(np.Spec.Ingress[0].From).To(Equal([]networkingv1.NetworkPolicyPeer{
+				{NamespaceSelector: tt.wantMetricsNamespaceSelector},
+			}))
+			g.Expect(np.Spec.Ingress[0].Ports).To(Equal(networkPolicyPorts(corev1.ProtocolTCP, 8443)))
+			if tt.wantWebhookIngress != nil {
+				g.Expect(np.Spec.Ingress).To(HaveLen(2))
+				g.Expect(np.Spec.Ingress[1].From).To(Equal(tt.wantWebhookIngress),
+					"the webhook port should only accept connections from the API server")
+				g.Expect(np.Spec.Ingress[1].Ports).To(ConsistOf(networkingv1.NetworkPolicyPort{
+					Protocol: utils.NewPtr(corev1.ProtocolTCP),
+					Port:     utils.NewPtr(intstr.FromInt32(9443)),
+				}))
+			} else {
+				g.Expect(np.Spec.Ingress).To(HaveLen(1))
+			}
+
+			if tt.wantRegistries == nil {
+				g.Expect(np.Spec.Egress).To(HaveLen(2))
+			} else {
+				g.Expect(np.Spec.Egress).To(HaveLen(3))
+				g.Expect(np.Spec.Egress[2]).To(Equal(*tt.wantRegistries))
+			}
+			g.Expect(np.Spec.Egress[0].To).To(BeEmpty())
+			g.Expect(np.Spec.Egress[0].Ports).NotTo(BeEmpty())
+			g.Expect(np.Spec.Egress[1].To).To(Equal(apiServerPeers),
+				"the egress towards the API server should be restricted to its endpoints")
+			g.Expect(np.Spec.Egress[1].Ports).To(Equal(networkPolicyPorts(corev1.ProtocolTCP, 6443)))
+		})
+	}
+}
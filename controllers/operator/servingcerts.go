@@ -0,0 +1,433 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/servingcerts"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+const (
+	selfManagedCASecretName = "multiarch-tuning-operator-serving-ca"
+	caBundleKey             = "ca-bundle.crt"
+	// operatorServingCertSecretSuffix is appended to the name of the Service of the operator to get the name of the
+	// Secret mounted by its Deployment, see config/manager/manager.yaml.
+	operatorServingCertSecretSuffix = "-cert"
+	// serviceCAOriginatingServiceAnnotation and serviceCAAlphaOriginatingServiceAnnotation are set by the service CA
+	// operator on the Secrets it issues.
+	serviceCAOriginatingServiceAnnotation      = "service.beta.openshift.io/originating-service-name"
+	serviceCAAlphaOriginatingServiceAnnotation = "service.alpha.openshift.io/originating-service-name"
+
+	selfManagedCAValidity                 = 2 * 365 * 24 * time.Hour
+	selfManagedServingCertificateValidity = 90 * 24 * time.Hour
+	// servingCertificatesResyncPeriod is the period of the reconciliations checking whether the self-managed
+	// certificates have to be rotated. It must be far shorter than a third of their validity.
+	servingCertificatesResyncPeriod = time.Hour
+	// operatorServingCertificateMountTimeout bounds the wait for the kubelet to mount the serving certificate of the
+	// operator once issued, which depends on the sync period of the kubelet.
+	operatorServingCertificateMountTimeout = 5 * time.Minute
+	operatorServingCertificatePollInterval = 5 * time.Second
+)
+
+var customResourceDefinitionsGVR = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// ensureSelfManagedCA returns the CA signing the serving certificates in the SelfManaged TLS mode and the CA bundle
+// to inject into the webhook configurations. The CA is stored in a Secret in the namespace of the operator, together
+// with the CA bundle, and it is regenerated when it is missing, invalid or close to its expiry. The CA bundle keeps
+// the previous CAs until they expire, so that the serving certificates they signed are trusted until rotated.
+// The Secret is not owned by the ClusterPodPlacementConfig: the CA also signs the serving certificates of the
+// operator, which outlive it.
+func (r *ClusterPodPlacementConfigReconciler) ensureSelfManagedCA(ctx context.Context) (*servingcerts.KeyPair, []byte, error) {
+	log := ctrllog.FromContext(ctx).WithValues("secret", selfManagedCASecretName)
+	now := time.Now()
+	existing, err := r.getSecret(ctx, selfManagedCASecretName)
+	if err != nil {
+		return nil, nil, err
+	}
+	var previousBundle []byte
+	if existing != nil {
+		ca, err := servingcerts.ParseKeyPair(existing.Data[corev1.TLSCertKey], existing.Data[corev1.TLSPrivateKeyKey])
+		if err == nil && !servingcerts.NeedsRotation(ca.Certificate, now) {
+			return ca, existing.Data[caBundleKey], nil
+		}
+		log.Info("Rotating the CA of the self-managed serving certificates", "reason", err)
+		previousBundle = existing.Data[caBundleKey]
+	}
+	ca, err := servingcerts.NewCA(fmt.Sprintf("%s@%d", selfManagedCASecretName, now.Unix()), now, selfManagedCAValidity)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := ca.KeyPEM()
+	if err != nil {
+		return nil, nil, err
+	}
+	bundle := servingcerts.CABundle(previousBundle, ca.Certificate, now)
+	if err := r.applySecret(ctx, nil, existing, selfManagedCASecretName, map[string][]byte{
+		corev1.TLSCertKey:       ca.CertificatePEM(),
+		corev1.TLSPrivateKeyKey: keyPEM,
+		caBundleKey:             bundle,
+	}); err != nil {
+		return nil, nil, err
+	}
+	return ca, bundle, nil
+}
+
+// ensureSelfManagedServingCertificates issues the serving certificates of the given Services, signed by the CA, into
+// the given Secrets. The certificates are re-issued when they are missing, invalid, signed by a previous CA or close
+// to their expiry. It must run after the CA bundle is injected into the webhook configurations, so that the clients
+// trust the new certificates as soon as the servers load them. The Secrets it creates are owned by the given
+// ClusterPodPlacementConfig, if any.
+func (r *ClusterPodPlacementConfigReconciler) ensureSelfManagedServingCertificates(ctx context.Context,
+	cppc *v1beta1.ClusterPodPlacementConfig, ca *servingcerts.KeyPair, bundle []byte, secretsToServices map[string]string) error {
+	errs := make([]error, 0)
+	for secretName, serviceName := range secretsToServices {
+		if err := r.ensureSelfManagedServingCertificate(ctx, cppc, ca, bundle, secretName, serviceName); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errorutils.NewAggregate(errs)
+}
+
+func (r *ClusterPodPlacementConfigReconciler) ensureSelfManagedServingCertificate(ctx context.Context,
+	cppc *v1beta1.ClusterPodPlacementConfig, ca *servingcerts.KeyPair, bundle []byte, secretName, serviceName string) error {
+	log := ctrllog.FromContext(ctx).WithValues("secret", secretName, "service", serviceName)
+	now := time.Now()
+	dnsNames := []string{
+		fmt.Sprintf("%s.%s.svc", serviceName, utils.Namespace()),
+		fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, utils.Namespace()),
+	}
+	existing, err := r.getSecret(ctx, secretName)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		certificate, err := servingcerts.ParseKeyPair(existing.Data[corev1.TLSCertKey], existing.Data[corev1.TLSPrivateKeyKey])
+		if err == nil && certificate.IsValidServingCertificate(ca.Certificate, dnsNames, now) {
+			if bytes.Equal(existing.Data[corev1.ServiceAccountRootCAKey], bundle) {
+				return nil
+			}
+			// Only the CA bundle changed, e.g., because an expired CA was dropped
+			return r.applySecret(ctx, cppc, existing, secretName, map[string][]byte{
+				corev1.TLSCertKey:              existing.Data[corev1.TLSCertKey],
+				corev1.TLSPrivateKeyKey:        existing.Data[corev1.TLSPrivateKeyKey],
+				corev1.ServiceAccountRootCAKey: bundle,
+			})
+		}
+		log.Info("Rotating the self-managed serving certificate", "reason", err)
+	}
+	certificate, err := ca.NewServingCertificate(dnsNames, now, selfManagedServingCertificateValidity)
+	if err != nil {
+		return err
+	}
+	keyPEM, err := certificate.KeyPEM()
+	if err != nil {
+		return err
+	}
+	return r.applySecret(ctx, cppc, existing, secretName, map[string][]byte{
+		corev1.TLSCertKey:              certificate.CertificatePEM(),
+		corev1.TLSPrivateKeyKey:        keyPEM,
+		corev1.ServiceAccountRootCAKey: bundle,
+	})
+}
+
+// EnsureOperatorServingCertificates issues the serving certificates of the operator and injects their CA bundle into
+// its webhooks when the TLS mode of the operator is explicitly SelfManaged, see isOperatorTLSModeSelfManaged, and
+// returns whether it is. It does not depend on the reconciliation of the ClusterPodPlacementConfig: the validating
+// webhook of the operator must be trusted for the ClusterPodPlacementConfig to be created.
+func (r *ClusterPodPlacementConfigReconciler) EnsureOperatorServingCertificates(ctx context.Context) (bool, error) {
+	cppc := &v1beta1.ClusterPodPlacementConfig{}
+	if err := r.Get(ctx, client.ObjectKey{Name: common.SingletonResourceObjectName}, cppc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		cppc = &v1beta1.ClusterPodPlacementConfig{}
+	}
+	if !r.isOperatorTLSModeSelfManaged(cppc) {
+		return false, nil
+	}
+	ca, bundle, err := r.ensureSelfManagedCA(ctx)
+	if err != nil {
+		return true, err
+	}
+	return true, r.ensureOperatorServingCertificates(ctx, ca, bundle)
+}
+
+// isOperatorTLSModeSelfManaged returns whether the serving certificates of the operator are self-managed. Unlike the
+// ones of the operand, they are self-managed only when the TLS mode is explicitly set to SelfManaged, in the
+// ClusterPodPlacementConfig or, if unset there, in the flags of the operator: the CA bundles of the webhooks of the
+// operator are never replaced because of a defaulted TLS mode.
+func (r *ClusterPodPlacementConfigReconciler) isOperatorTLSModeSelfManaged(cppc *v1beta1.ClusterPodPlacementConfig) bool {
+	if cppc.Spec.TLSMode != "" {
+		return cppc.Spec.TLSMode == v1beta1.TLSModeSelfManaged
+	}
+	return r.OperatorTLSMode == v1beta1.TLSModeSelfManaged
+}
+
+// BootstrapOperatorServingCertificates runs EnsureOperatorServingCertificates before the manager of the operator
+// starts and, when the serving certificates of the operator are self-managed, waits for the serving certificate to be
+// mounted into certDir: the webhook and metrics servers of the manager cannot start without it.
+func (r *ClusterPodPlacementConfigReconciler) BootstrapOperatorServingCertificates(ctx context.Context, certDir string) error {
+	selfManaged := false
+	// Another replica of the operator may be issuing the same certificates.
+	if err := retry.OnError(retry.DefaultBackoff, func(error) bool { return true }, func() (err error) {
+		selfManaged, err = r.EnsureOperatorServingCertificates(ctx)
+		return err
+	}); err != nil {
+		return err
+	}
+	if !selfManaged {
+		return nil
+	}
+	ctrllog.FromContext(ctx).Info("Waiting for the serving certificate to be mounted", "certDir", certDir)
+	return wait.PollUntilContextTimeout(ctx, operatorServingCertificatePollInterval, operatorServingCertificateMountTimeout,
+		true, func(context.Context) (bool, error) {
+			_, err := os.Stat(filepath.Join(certDir, corev1.TLSCertKey))
+			return err == nil, nil
+		})
+}
+
+// OperatorServingCertificatesRotator periodically runs EnsureOperatorServingCertificates, so that the serving
+// certificates of the operator are rotated before they expire even when no ClusterPodPlacementConfig exists.
+// The rotator runs only in the leader replica of the operator.
+type OperatorServingCertificatesRotator struct {
+	reconciler *ClusterPodPlacementConfigReconciler
+	interval   time.Duration
+}
+
+func NewOperatorServingCertificatesRotator(reconciler *ClusterPodPlacementConfigReconciler) *OperatorServingCertificatesRotator {
+	return &OperatorServingCertificatesRotator{
+		reconciler: reconciler,
+		interval:   servingCertificatesResyncPeriod,
+	}
+}
+
+func (o *OperatorServingCertificatesRotator) Start(ctx context.Context) error {
+	log := ctrllog.FromContext(ctx, "handler", "OperatorServingCertificatesRotator")
+	log.Info("Starting the rotator of the serving certificates of the operator", "interval", o.interval)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if _, err := o.reconciler.EnsureOperatorServingCertificates(ctx); err != nil {
+			log.Error(err, "Unable to ensure the serving certificates of the operator")
+		}
+	}, o.interval)
+	log.Info("Stopping the rotator of the serving certificates of the operator")
+	return nil
+}
+
+// ensureOperatorServingCertificates injects the CA bundle into the webhooks of the operator and then issues the
+// serving certificates of the Services serving them.
+func (r *ClusterPodPlacementConfigReconciler) ensureOperatorServingCertificates(ctx context.Context,
+	ca *servingcerts.KeyPair, bundle []byte) error {
+	operatorSecrets, err := r.injectOperatorCABundle(ctx, bundle)
+	if err != nil {
+		return err
+	}
+	return r.ensureSelfManagedServingCertificates(ctx, nil, ca, bundle, operatorSecrets)
+}
+
+// injectOperatorCABundle injects the CA bundle into the conversion webhooks of the CRDs of the operator and into the
+// webhook configurations served by the same Services, and returns the Secrets of the serving certificates of those
+// Services, mapped to the Services names.
+func (r *ClusterPodPlacementConfigReconciler) injectOperatorCABundle(ctx context.Context, bundle []byte) (map[string]string, error) {
+	log := ctrllog.FromContext(ctx)
+	services := map[string]string{}
+	errs := make([]error, 0)
+	crds := r.DynamicClient.Resource(customResourceDefinitionsGVR)
+	for _, resource := range []string{v1beta1.ClusterPodPlacementConfigResource, v1beta1.PodPlacementConfigResource,
+		v1beta1.ENoExecEventResource} {
+		crd, err := crds.Get(ctx, fmt.Sprintf("%s.%s", resource, v1beta1.GroupVersion.Group), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if strategy, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy"); strategy != "Webhook" {
+			continue
+		}
+		clientConfig, _, _ := unstructured.NestedMap(crd.Object, "spec", "conversion", "webhook", "clientConfig")
+		serviceName, _, _ := unstructured.NestedString(clientConfig, "service", "name")
+		serviceNamespace, _, _ := unstructured.NestedString(clientConfig, "service", "namespace")
+		if serviceNamespace != utils.Namespace() || serviceName == "" {
+			continue
+		}
+		services[serviceName+operatorServingCertSecretSuffix] = serviceName
+		if caBundle, _, _ := unstructured.NestedString(clientConfig, "caBundle"); caBundle == base64.StdEncoding.EncodeToString(bundle) {
+			continue
+		}
+		log.Info("Injecting the CA bundle into the conversion webhook", "crd", crd.GetName())
+		patch, err := json.Marshal(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"conversion": map[string]interface{}{
+					"webhook": map[string]interface{}{
+						"clientConfig": map[string]interface{}{
+							"caBundle": bundle,
+						},
+					},
+				},
+			},
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, err := crds.Patch(ctx, crd.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := r.injectWebhookConfigurationsCABundle(ctx, bundle, services); err != nil {
+		errs = append(errs, err)
+	}
+	return services, errorutils.NewAggregate(errs)
+}
+
+// injectWebhookConfigurationsCABundle injects the CA bundle into the webhooks of the validating and mutating webhook
+// configurations served by the given Services of the operator.
+func (r *ClusterPodPlacementConfigReconciler) injectWebhookConfigurationsCABundle(ctx context.Context, bundle []byte,
+	services map[string]string) error {
+	servedByOperator := func(clientConfig *admissionv1.WebhookClientConfig) bool {
+		if clientConfig.Service == nil || clientConfig.Service.Namespace != utils.Namespace() {
+			return false
+		}
+		for _, serviceName := range services {
+			if clientConfig.Service.Name == serviceName {
+				return !bytes.Equal(clientConfig.CABundle, bundle)
+			}
+		}
+		return false
+	}
+	errs := make([]error, 0)
+	vwcs := &admissionv1.ValidatingWebhookConfigurationList{}
+	if err := r.List(ctx, vwcs); err != nil {
+		errs = append(errs, err)
+	}
+	for i := range vwcs.Items {
+		vwc := &vwcs.Items[i]
+		modified := false
+		for j := range vwc.Webhooks {
+			if servedByOperator(&vwc.Webhooks[j].ClientConfig) {
+				vwc.Webhooks[j].ClientConfig.CABundle = bundle
+				modified = true
+			}
+		}
+		if modified {
+			ctrllog.FromContext(ctx).Info("Injecting the CA bundle into the validating webhook configuration", "name", vwc.Name)
+			errs = append(errs, r.Update(ctx, vwc))
+		}
+	}
+	mwcs := &admissionv1.MutatingWebhookConfigurationList{}
+	if err := r.List(ctx, mwcs); err != nil {
+		errs = append(errs, err)
+	}
+	for i := range mwcs.Items {
+		mwc := &mwcs.Items[i]
+		modified := false
+		for j := range mwc.Webhooks {
+			if servedByOperator(&mwc.Webhooks[j].ClientConfig) {
+				mwc.Webhooks[j].ClientConfig.CABundle = bundle
+				modified = true
+			}
+		}
+		if modified {
+			ctrllog.FromContext(ctx).Info("Injecting the CA bundle into the mutating webhook configuration", "name", mwc.Name)
+			errs = append(errs, r.Update(ctx, mwc))
+		}
+	}
+	return errorutils.NewAggregate(errs)
+}
+
+// getSecret returns the Secret with the given name in the namespace of the operator, or nil if it does not exist.
+func (r *ClusterPodPlacementConfigReconciler) getSecret(ctx context.Context, name string) (*corev1.Secret, error) {
+	secret, err := r.ClientSet.CoreV1().Secrets(utils.Namespace()).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return secret, err
+}
+
+// applySecret creates or updates the kubernetes.io/tls Secret with the given name and data. The created Secret is owned
+// by the ClusterPodPlacementConfig, if any.
+func (r *ClusterPodPlacementConfigReconciler) applySecret(ctx context.Context, cppc *v1beta1.ClusterPodPlacementConfig,
+	existing *corev1.Secret, name string, data map[string][]byte) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: utils.Namespace(),
+			Labels: map[string]string{
+				utils.OperandLabelKey: operandName,
+			},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: data,
+	}
+	secrets := r.ClientSet.CoreV1().Secrets(utils.Namespace())
+	if isServiceCASecret(existing) {
+		return fmt.Errorf("the Secret %s/%s is issued by the service CA operator: it has to be deleted for the "+
+			"self-managed serving certificate to be issued", utils.Namespace(), name)
+	}
+	if existing == nil {
+		if cppc != nil {
+			if err := ctrl.SetControllerReference(cppc, secret, r.Scheme); err != nil {
+				return err
+			}
+		}
+		_, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+		return err
+	}
+	// The Secrets created by other providers, e.g., cert-manager before the TLS mode changed, are updated in place.
+	existing = existing.DeepCopy()
+	existing.Data = data
+	_, err := secrets.Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
+// isServiceCASecret returns whether the Secret was issued by the service CA operator, which annotates it with the
+// Service it was issued for.
+func isServiceCASecret(secret *corev1.Secret) bool {
+	if secret == nil {
+		return false
+	}
+	_, beta := secret.Annotations[serviceCAOriginatingServiceAnnotation]
+	_, alpha := secret.Annotations[serviceCAAlphaOriginatingServiceAnnotation]
+	return beta || alpha
+}
+
+// servicesSecrets maps the names of the Secrets with the serving certificates of the operand Services to the
+// Services names: the Secrets are named after the Services.
+func servicesSecrets(objects []client.Object) map[string]string {
+	services := map[string]string{}
+	for _, o := range objects {
+		if s, ok := o.(*corev1.Service); ok {
+			services[s.Name] = s.Name
+		}
+	}
+	return services
+}
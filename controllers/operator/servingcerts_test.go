@@ -0,0 +1,90 @@
+package operator
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+)
+
+func TestIsOperatorTLSModeSelfManaged(t *testing.T) {
+	tests := []struct {
+		name            string
+		tlsMode         v1beta1.TLSMode
+		operatorTLSMode v1beta1.TLSMode
+		want            bool
+	}{
+		{
+			name: "defaulted",
+			want: false,
+		},
+		{
+			name:    "self-managed in the ClusterPodPlacementConfig",
+			tlsMode: v1beta1.TLSModeSelfManaged,
+			want:    true,
+		},
+		{
+			name:            "self-managed in the flags of the operator",
+			operatorTLSMode: v1beta1.TLSModeSelfManaged,
+			want:            true,
+		},
+		{
+			name:            "the ClusterPodPlacementConfig overrides the flags of the operator",
+			tlsMode:         v1beta1.TLSModeServiceCA,
+			operatorTLSMode: v1beta1.TLSModeSelfManaged,
+			want:            false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			r := &ClusterPodPlacementConfigReconciler{OperatorTLSMode: tt.operatorTLSMode}
+			cppc := &v1beta1.ClusterPodPlacementConfig{
+				Spec: v1beta1.ClusterPodPlacementConfigSpec{TLSMode: tt.tlsMode},
+			}
+			g.Expect(r.isOperatorTLSModeSelfManaged(cppc)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestIsServiceCASecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret *corev1.Secret
+		want   bool
+	}{
+		{
+			name: "missing",
+			want: false,
+		},
+		{
+			name:   "self-managed",
+			secret: &corev1.Secret{},
+			want:   false,
+		},
+		{
+			name: "issued by the service CA operator",
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				serviceCAOriginatingServiceAnnotation: "multiarch-tuning-operator-controller-manager-service",
+			}}},
+			want: true,
+		},
+		{
+			name: "issued by a previous version of the service CA operator",
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				serviceCAAlphaOriginatingServiceAnnotation: "multiarch-tuning-operator-controller-manager-service",
+			}}},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(isServiceCASecret(tt.secret)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package podplacement
+
+import (
+	"context"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+)
+
+// matchingPodPlacementConfig returns the PodPlacementConfig with the highest priority, among the ones in the
+// namespace of the pod whose label selector matches the pod. It returns nil if no PodPlacementConfig matches.
+func matchingPodPlacementConfig(ctx context.Context, reader client.Reader, pod *Pod) (*v1beta1.PodPlacementConfig, error) {
+	ppcList := &v1beta1.PodPlacementConfigList{}
+	if err := reader.List(ctx, ppcList, client.InNamespace(pod.Namespace)); err != nil {
+		return nil, err
+	}
+	sort.Slice(ppcList.Items, func(i, j int) bool {
+		return ppcList.Items[i].Spec.Priority > ppcList.Items[j].Spec.Priority
+	})
+	for i := range ppcList.Items {
+		selector := labels.Everything()
+		if ppcList.Items[i].Spec.LabelSelector != nil {
+			var err error
+			if selector, err = metav1.LabelSelectorAsSelector(ppcList.Items[i].Spec.LabelSelector); err != nil {
+				continue
+			}
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return &ppcList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// allowedArchitectures returns the architectures a pod is allowed to run on: the ones of the PodPlacementConfig
+// selecting the pod, if set, or the default of the ClusterPodPlacementConfig. It returns nil if all the
+// architectures are allowed.
+func allowedArchitectures(ppc *v1beta1.PodPlacementConfig, cppc *v1beta1.ClusterPodPlacementConfig) sets.Set[string] {
+	if ppc != nil && len(ppc.Spec.AllowedArchitectures) > 0 {
+		return sets.New(ppc.Spec.AllowedArchitectures...)
+	}
+	if cppc != nil && len(cppc.Spec.AllowedArchitectures) > 0 {
+		return sets.New(cppc.Spec.AllowedArchitectures...)
+	}
+	return nil
+}
+
+// allowedArchitectures returns the architectures the pod is allowed to run on, see allowedArchitectures.
+func (r *PodReconciler) allowedArchitectures(ctx context.Context, pod *Pod,
+	cppc *v1beta1.ClusterPodPlacementConfig) (sets.Set[string], error) {
+	ppc, err := matchingPodPlacementConfig(ctx, r.Client, pod)
+	if err != nil {
+		return nil, err
+	}
+	return allowedArchitectures(ppc, cppc), nil
+}
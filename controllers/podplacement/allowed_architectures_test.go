@@ -0,0 +1,60 @@
+package podplacement
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+func Test_allowedArchitectures(t *testing.T) {
+	cppc := &v1beta1.ClusterPodPlacementConfig{
+		Spec: v1beta1.ClusterPodPlacementConfigSpec{
+			AllowedArchitectures: []string{utils.ArchitectureAmd64, utils.ArchitectureArm64},
+		},
+	}
+	ppc := &v1beta1.PodPlacementConfig{
+		Spec: v1beta1.PodPlacementConfigSpec{
+			AllowedArchitectures: []string{utils.ArchitectureArm64},
+		},
+	}
+	tests := []struct {
+		name string
+		ppc  *v1beta1.PodPlacementConfig
+		cppc *v1beta1.ClusterPodPlacementConfig
+		want sets.Set[string]
+	}{
+		{
+			name: "no policy",
+			cppc: &v1beta1.ClusterPodPlacementConfig{},
+			want: nil,
+		},
+		{
+			name: "cluster-wide default",
+			ppc:  &v1beta1.PodPlacementConfig{},
+			cppc: cppc,
+			want: sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64),
+		},
+		{
+			name: "PodPlacementConfig overriding the cluster-wide default",
+			ppc:  ppc,
+			cppc: cppc,
+			want: sets.New(utils.ArchitectureArm64),
+		},
+		{
+			name: "PodPlacementConfig without a ClusterPodPlacementConfig",
+			ppc:  ppc,
+			want: sets.New(utils.ArchitectureArm64),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(allowedArchitectures(tt.ppc, tt.cppc)).To(Equal(tt.want))
+		})
+	}
+}
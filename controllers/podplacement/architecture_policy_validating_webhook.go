@@ -0,0 +1,180 @@
+package podplacement
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
+	"github.com/openshift/multiarch-tuning-operator/pkg/informers/clusterpodplacementconfig"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+// PodArchitecturePolicyValidatingWebHook rejects the pods whose images violate the validating policy configured in
+// the ClusterPodPlacementConfig or in the namespace of the pods. It fails open: the pods are admitted whenever the
+// architectures of their images are unknown.
+type PodArchitecturePolicyValidatingWebHook struct {
+	client    client.Client
+	clientSet *kubernetes.Clientset
+	decoder   admission.Decoder
+	once      sync.Once
+	scheme    *runtime.Scheme
+}
+
+func NewPodArchitecturePolicyValidatingWebHook(client client.Client, clientSet *kubernetes.Clientset,
+	scheme *runtime.Scheme) *PodArchitecturePolicyValidatingWebHook {
+	return &PodArchitecturePolicyValidatingWebHook{
+		client:    client,
+		clientSet: clientSet,
+		scheme:    scheme,
+	}
+}
+
+func (a *PodArchitecturePolicyValidatingWebHook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	a.once.Do(func() {
+		a.decoder = admission.NewDecoder(a.scheme)
+	})
+	pod := newPod(&corev1.Pod{}, ctx, nil)
+	if err := a.decoder.Decode(req, &pod.Pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	// The name of the pods created through the generateName field is not known yet.
+	log := ctrllog.FromContext(ctx).WithValues("namespace", req.Namespace, "name", pod.Name)
+	cppc := clusterpodplacementconfig.GetClusterPodPlacementConfig()
+	if cppc == nil || cppc.Spec.ValidatingPolicy == nil || pod.shouldIgnorePod() {
+		return admission.Allowed("")
+	}
+	mode := a.policyMode(ctx, req.Namespace, cppc.Spec.ValidatingPolicy)
+	if mode == "" {
+		return admission.Allowed("")
+	}
+	psdl, err := pullSecretDataList(ctx, a.clientSet, pod)
+	if err != nil {
+		log.Error(err, "Unable to retrieve the image pull secret data for the pod, admitting it")
+		return admission.Allowed("")
+	}
+	imagesArchitectures, ok := pod.knownImagesArchitectures(ctx, psdl, cppc.Spec.ValidatingPolicy.GetInspectionTimeout())
+	if !ok {
+		log.V(2).Info("The architectures of the images of the pod are unknown, admitting it")
+		return admission.Allowed("")
+	}
+	if violation := policyViolation(mode, imagesArchitectures); violation != "" {
+		log.Info("Rejecting the pod", "policy", mode, "violation", violation)
+		metrics.PodsRejectedByPolicy.WithLabelValues(string(mode)).Inc()
+		return admission.Denied(fmt.Sprintf("the pod violates the %s policy: %s", mode, violation))
+	}
+	return admission.Allowed("")
+}
+
+// policyMode returns the policy enforced in the namespace: the one set by the utils.ValidatingPolicyNamespaceLabel
+// label, if any, or the one of the ClusterPodPlacementConfig. It returns an empty mode if no policy is enforced.
+func (a *PodArchitecturePolicyValidatingWebHook) policyMode(ctx context.Context, namespace string,
+	policy *v1beta1.ValidatingPolicy) v1beta1.ValidatingPolicyMode {
+	ns := &corev1.Namespace{}
+	if err := a.client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "Unable to get the namespace of the pod, using the cluster-wide policy")
+		return policy.Mode
+	}
+	value, ok := ns.Labels[utils.ValidatingPolicyNamespaceLabel]
+	if !ok {
+		return policy.Mode
+	}
+	switch mode := v1beta1.ValidatingPolicyMode(value); mode {
+	case v1beta1.RequireCommonArchitecture, v1beta1.RequireMultiArchImages:
+		return mode
+	case utils.ValidatingPolicyNone:
+		return ""
+	default:
+		ctrllog.FromContext(ctx).Info("Unknown validating policy in the namespace label, using the cluster-wide policy",
+			"value", value)
+		return policy.Mode
+	}
+}
+
+// knownImagesArchitectures returns the architectures supported by each of the images of the pod, keyed by image.
+// The images not in the inspection cache are inspected within the timeout, if positive. It returns false if the
+// architectures of any of the images are unknown.
+func (pod *Pod) knownImagesArchitectures(ctx context.Context, pullSecretDataList [][]byte,
+	timeout time.Duration) (map[string]sets.Set[string], bool) {
+	reader, _ := imageInspectionCache.(cachedArchitecturesReader)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	imagesArchitectures := map[string]sets.Set[string]{}
+	for imageContainer := range pod.imagesNamesSet() {
+		if reader != nil && !imageContainer.skipCache {
+			if architectures, ok := reader.GetCachedCompatibleArchitecturesSet(imageContainer.imageName,
+				pullSecretDataList); ok {
+				imagesArchitectures[imageContainer.imageName] = architectures
+				continue
+			}
+		}
+		if timeout <= 0 {
+			return nil, false
+		}
+		architectures, err := imageInspectionCache.GetCompatibleArchitecturesSet(ctx, imageContainer.imageName,
+			imageContainer.skipCache, pullSecretDataList)
+		if err != nil {
+			ctrllog.FromContext(ctx).V(2).Info("Unable to inspect the image at admission time",
+				"image", imageContainer.imageName, "error", err.Error())
+			return nil, false
+		}
+		imagesArchitectures[imageContainer.imageName] = architectures
+	}
+	return imagesArchitectures, true
+}
+
+// policyViolation returns a description of the violation of the policy by the images, or an empty string.
+func policyViolation(mode v1beta1.ValidatingPolicyMode, imagesArchitectures map[string]sets.Set[string]) string {
+	images := make([]string, 0, len(imagesArchitectures))
+	for image := range imagesArchitectures {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	if mode == v1beta1.RequireMultiArchImages {
+		singleArch := []string{}
+		for _, image := range images {
+			if imagesArchitectures[image].Len() < 2 {
+				singleArch = append(singleArch, describeImageArchitectures(image, imagesArchitectures[image]))
+			}
+		}
+		if len(singleArch) > 0 {
+			return "the following images are not multi-architecture: " + strings.Join(singleArch, "; ")
+		}
+	}
+	var common sets.Set[string]
+	for _, image := range images {
+		if common == nil {
+			common = imagesArchitectures[image]
+		} else {
+			common = common.Intersection(imagesArchitectures[image])
+		}
+	}
+	if common != nil && common.Len() == 0 {
+		descriptions := make([]string, 0, len(images))
+		for _, image := range images {
+			descriptions = append(descriptions, describeImageArchitectures(image, imagesArchitectures[image]))
+		}
+		return "the images have no architecture in common: " + strings.Join(descriptions, "; ")
+	}
+	return ""
+}
+
+func describeImageArchitectures(image string, architectures sets.Set[string]) string {
+	return fmt.Sprintf("%s supports [%s]", strings.TrimPrefix(image, "//"), strings.Join(sets.List(architectures), ", "))
+}
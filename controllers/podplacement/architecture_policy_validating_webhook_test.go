@@ -0,0 +1,123 @@
+package podplacement
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	mmoimage "github.com/openshift/multiarch-tuning-operator/pkg/image"
+	"github.com/openshift/multiarch-tuning-operator/pkg/testing/image/fake"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+func Test_policyViolation(t *testing.T) {
+	multiArch := sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64)
+	tests := []struct {
+		name                string
+		mode                v1beta1.ValidatingPolicyMode
+		imagesArchitectures map[string]sets.Set[string]
+		want                string
+	}{
+		{
+			name: "common architecture",
+			mode: v1beta1.RequireCommonArchitecture,
+			imagesArchitectures: map[string]sets.Set[string]{
+				"//quay.io/app:latest":     multiArch,
+				"//quay.io/sidecar:latest": sets.New(utils.ArchitectureArm64),
+			},
+			want: "",
+		},
+		{
+			name: "no common architecture",
+			mode: v1beta1.RequireCommonArchitecture,
+			imagesArchitectures: map[string]sets.Set[string]{
+				"//quay.io/app:latest":     sets.New(utils.ArchitectureAmd64),
+				"//quay.io/sidecar:latest": sets.New(utils.ArchitectureArm64),
+			},
+			want: "the images have no architecture in common: quay.io/app:latest supports [amd64]; " +
+				"quay.io/sidecar:latest supports [arm64]",
+		},
+		{
+			name: "single-arch image",
+			mode: v1beta1.RequireMultiArchImages,
+			imagesArchitectures: map[string]sets.Set[string]{
+				"//quay.io/app:latest":     multiArch,
+				"//quay.io/sidecar:latest": sets.New(utils.ArchitectureArm64),
+			},
+			want: "the following images are not multi-architecture: quay.io/sidecar:latest supports [arm64]",
+		},
+		{
+			name: "multi-arch images with no common architecture",
+			mode: v1beta1.RequireMultiArchImages,
+			imagesArchitectures: map[string]sets.Set[string]{
+				"//quay.io/app:latest":     multiArch,
+				"//quay.io/sidecar:latest": sets.New(utils.ArchitecturePpc64le, utils.ArchitectureS390x),
+			},
+			want: "the images have no architecture in common: quay.io/app:latest supports [amd64, arm64]; " +
+				"quay.io/sidecar:latest supports [ppc64le, s390x]",
+		},
+		{
+			name:                "no images",
+			mode:                v1beta1.RequireMultiArchImages,
+			imagesArchitectures: map[string]sets.Set[string]{},
+			want:                "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(policyViolation(tt.mode, tt.imagesArchitectures)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPod_knownImagesArchitectures(t *testing.T) {
+	tests := []struct {
+		name    string
+		pod     *v1.Pod
+		timeout time.Duration
+		want    map[string]sets.Set[string]
+		wantOk  bool
+	}{
+		{
+			name:    "images not cached and inspection at admission disabled",
+			pod:     NewPod().WithContainersImages(fake.SingleArchArm64Image).Build(),
+			timeout: 0,
+			wantOk:  false,
+		},
+		{
+			name:    "images inspected at admission",
+			pod:     NewPod().WithContainersImages(fake.SingleArchArm64Image).Build(),
+			timeout: time.Second,
+			want: map[string]sets.Set[string]{
+				"//" + fake.SingleArchArm64Image: sets.New(utils.ArchitectureArm64),
+			},
+			wantOk: true,
+		},
+		{
+			name:    "image failing the inspection",
+			pod:     NewPod().WithContainersImages(fake.SingleArchArm64Image, "non-existing-image").Build(),
+			timeout: time.Second,
+			wantOk:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imageInspectionCache = fake.FacadeSingleton()
+			defer func() {
+				imageInspectionCache = mmoimage.FacadeSingleton()
+			}()
+			g := NewGomegaWithT(t)
+			got, ok := newPod(tt.pod, ctx, nil).knownImagesArchitectures(ctx, nil, tt.timeout)
+			g.Expect(ok).To(Equal(tt.wantOk))
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package podplacement
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+// filterArchitectures returns the architectures that satisfy the requirement on the kubernetes.io/arch label.
+func filterArchitectures(architectures sets.Set[string], requirement corev1.NodeSelectorRequirement) sets.Set[string] {
+	switch requirement.Operator {
+	case corev1.NodeSelectorOpIn:
+		return architectures.Intersection(sets.New(requirement.Values...))
+	case corev1.NodeSelectorOpNotIn:
+		return architectures.Difference(sets.New(requirement.Values...))
+	case corev1.NodeSelectorOpDoesNotExist:
+		return sets.New[string]()
+	default:
+		return architectures
+	}
+}
+
+// architectureSelectionAdmits returns true if at least one of the given architectures satisfies the nodeSelector and
+// at least one of the required nodeSelectorTerms of the pod, considering the kubernetes.io/arch label only.
+func (pod *Pod) architectureSelectionAdmits(architectures sets.Set[string]) bool {
+	candidates := architectures.Clone()
+	if architecture, ok := pod.Spec.NodeSelector[utils.ArchLabel]; ok {
+		candidates = candidates.Intersection(sets.New(architecture))
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil ||
+		len(pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) == 0 {
+		return candidates.Len() > 0
+	}
+	// The nodeSelectorTerms are ORed, the matchExpressions of each term are ANDed.
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		termCandidates := candidates
+		for _, requirement := range term.MatchExpressions {
+			if requirement.Key == utils.ArchLabel {
+				termCandidates = filterArchitectures(termCandidates, requirement)
+			}
+		}
+		if termCandidates.Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyArchitectureSelection inspects the images of a pod whose architecture is selected by the user in the
+// nodeSelector or in the required nodeAffinity. When the selection does not overlap the architectures supported by
+// the images, the pod would fail with ENOEXEC on any node it is scheduled to: it is labeled and a warning event is
+// published. The selection of the user is never modified.
+func (pod *Pod) verifyArchitectureSelection(pullSecretDataList [][]byte) error {
+	if pod.imagesNamesSet().Len() == 0 {
+		return nil
+	}
+	architectures, err := pod.intersectImagesArchitecture(pullSecretDataList)
+	if err != nil {
+		return err
+	}
+	pod.EnsureNoLabel(utils.ImageInspectionErrorLabel)
+	if pod.architectureSelectionAdmits(sets.New(architectures...)) {
+		pod.EnsureNoLabel(utils.ArchitectureSelectionConflictLabel)
+		return nil
+	}
+	ctrllog.FromContext(pod.Ctx()).Info("The architectures selected for the pod are not supported by its images",
+		"supportedArchitectures", architectures)
+	pod.EnsureLabel(utils.ArchitectureSelectionConflictLabel, "")
+	pod.PublishEvent(corev1.EventTypeWarning, ArchitectureSelectionConflict,
+		ArchitectureSelectionConflictMsg+strings.Join(architectures, ", "))
+	metrics.ArchitectureSelectionConflicts.Inc()
+	return nil
+}
+
+// restrictArchitectureSelection restricts the architectures selected by the user to the ones allowed by the
+// allowedArchitectures policy, if not nil: the requirement for the allowed architectures is ANDed to each required
+// nodeSelectorTerm. When the selection does not overlap the allowed architectures, the pod is labeled, a warning event
+// is published and the pod is made unschedulable.
+func (pod *Pod) restrictArchitectureSelection(allowedArchitectures sets.Set[string]) {
+	if allowedArchitectures == nil {
+		return
+	}
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      utils.ArchLabel,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   sets.List(allowedArchitectures),
+	}
+	if !pod.architectureSelectionAdmits(allowedArchitectures) {
+		requirement = corev1.NodeSelectorRequirement{
+			Key:      utils.NoAllowedArchLabel,
+			Operator: corev1.NodeSelectorOpExists,
+		}
+		pod.EnsureLabel(utils.NoAllowedArchLabel, "")
+		pod.PublishEvent(corev1.EventTypeWarning, NoAllowedArchitecturesFound,
+			NoAllowedArchitecturesSelectedMsg+strings.Join(sets.List(allowedArchitectures), ", "))
+	}
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	nodeSelector := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = make([]corev1.NodeSelectorTerm, 1)
+	}
+	for i := range nodeSelector.NodeSelectorTerms {
+		// The requirement is not added again when the pod is processed again after a configuration change.
+		if slices.ContainsFunc(nodeSelector.NodeSelectorTerms[i].MatchExpressions,
+			func(expression corev1.NodeSelectorRequirement) bool {
+				return equality.Semantic.DeepEqual(expression, requirement)
+			}) {
+			continue
+		}
+		nodeSelector.NodeSelectorTerms[i].MatchExpressions = append(nodeSelector.NodeSelectorTerms[i].MatchExpressions,
+			requirement)
+	}
+}
+
+// architectureSelectionWarnings returns the admission warnings for a pod whose architecture is selected by the user,
+// when the selection does not overlap the architectures supported by its images. Only the image inspection cache is
+// used: no warning is returned if any of the images was not inspected yet.
+func (a *PodSchedulingGateMutatingWebHook) architectureSelectionWarnings(ctx context.Context, pod *Pod) []string {
+	if pod.imagesNamesSet().Len() == 0 {
+		return nil
+	}
+	psdl, err := pullSecretDataList(ctx, a.clientSet, pod)
+	if err != nil {
+		return nil
+	}
+	architectures, ok := pod.cachedImagesArchitectures(psdl)
+	if !ok || pod.architectureSelectionAdmits(architectures) {
+		return nil
+	}
+	return []string{fmt.Sprintf(ArchitectureSelectionConflictWarning, strings.Join(sets.List(architectures), ", "))}
+}
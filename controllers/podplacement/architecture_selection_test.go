@@ -0,0 +1,183 @@
+package podplacement
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
+	mmoimage "github.com/openshift/multiarch-tuning-operator/pkg/image"
+	"github.com/openshift/multiarch-tuning-operator/pkg/testing/image/fake"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+func TestPod_architectureSelectionAdmits(t *testing.T) {
+	supported := sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64)
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want bool
+	}{
+		{
+			name: "nodeSelector with a supported architecture",
+			pod:  NewPod().WithNodeSelectors(utils.ArchLabel, utils.ArchitectureArm64).Build(),
+			want: true,
+		},
+		{
+			name: "nodeSelector with an unsupported architecture",
+			pod:  NewPod().WithNodeSelectors(utils.ArchLabel, utils.ArchitectureS390x).Build(),
+			want: false,
+		},
+		{
+			name: "nodeAffinity with one term selecting a supported architecture",
+			pod: NewPod().WithNodeSelectorTermsMatchExpressions(
+				[]v1.NodeSelectorRequirement{
+					{Key: utils.ArchLabel, Operator: v1.NodeSelectorOpIn, Values: []string{utils.ArchitectureS390x}},
+				},
+				[]v1.NodeSelectorRequirement{
+					{Key: utils.ArchLabel, Operator: v1.NodeSelectorOpIn, Values: []string{utils.ArchitectureAmd64}},
+				},
+			).Build(),
+			want: true,
+		},
+		{
+			name: "nodeAffinity excluding all the supported architectures",
+			pod: NewPod().WithNodeSelectorTermsMatchExpressions(
+				[]v1.NodeSelectorRequirement{
+					{Key: utils.ArchLabel, Operator: v1.NodeSelectorOpNotIn,
+						Values: []string{utils.ArchitectureAmd64, utils.ArchitectureArm64}},
+				},
+			).Build(),
+			want: false,
+		},
+		{
+			name: "nodeSelector and nodeAffinity with no common supported architecture",
+			pod: NewPod().WithNodeSelectors(utils.ArchLabel, utils.ArchitectureArm64).WithNodeSelectorTermsMatchExpressions(
+				[]v1.NodeSelectorRequirement{
+					{Key: utils.ArchLabel, Operator: v1.NodeSelectorOpIn, Values: []string{utils.ArchitectureAmd64}},
+				},
+			).Build(),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(newPod(tt.pod, ctx, nil).architectureSelectionAdmits(supported)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPod_verifyArchitectureSelection(t *testing.T) {
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		wantConflict bool
+	}{
+		{
+			name:         "selection supported by the images",
+			pod:          NewPod().WithContainersImages(fake.MultiArchImage).WithNodeSelectors(utils.ArchLabel, utils.ArchitectureArm64).Build(),
+			wantConflict: false,
+		},
+		{
+			name:         "selection not supported by the images",
+			pod:          NewPod().WithContainersImages(fake.SingleArchArm64Image).WithNodeSelectors(utils.ArchLabel, utils.ArchitectureAmd64).Build(),
+			wantConflict: true,
+		},
+	}
+	metrics.InitPodPlacementControllerMetrics()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imageInspectionCache = fake.FacadeSingleton()
+			defer func() {
+				imageInspectionCache = mmoimage.FacadeSingleton()
+			}()
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			g.Expect(pod.verifyArchitectureSelection(nil)).To(Succeed())
+			g.Expect(pod.Labels).To(WithTransform(func(labels map[string]string) bool {
+				_, ok := labels[utils.ArchitectureSelectionConflictLabel]
+				return ok
+			}, Equal(tt.wantConflict)))
+			g.Expect(pod.Spec.NodeSelector).To(Equal(tt.pod.Spec.NodeSelector), "the selection of the user should not change")
+		})
+	}
+}
+
+func TestPod_restrictArchitectureSelection(t *testing.T) {
+	allowedRequirement := v1.NodeSelectorRequirement{
+		Key: utils.ArchLabel, Operator: v1.NodeSelectorOpIn, Values: []string{utils.ArchitectureAmd64, utils.ArchitectureArm64},
+	}
+	noAllowedRequirement := v1.NodeSelectorRequirement{Key: utils.NoAllowedArchLabel, Operator: v1.NodeSelectorOpExists}
+	tests := []struct {
+		name          string
+		pod           *v1.Pod
+		allowed       sets.Set[string]
+		wantTerms     []v1.NodeSelectorTerm
+		wantNoAllowed bool
+	}{
+		{
+			name:    "no allowedArchitectures policy",
+			pod:     NewPod().WithNodeSelectors(utils.ArchLabel, utils.ArchitectureS390x).Build(),
+			allowed: nil,
+		},
+		{
+			name:      "node selector overlapping the allowed architectures",
+			pod:       NewPod().WithNodeSelectors(utils.ArchLabel, utils.ArchitectureArm64).Build(),
+			allowed:   sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64),
+			wantTerms: []v1.NodeSelectorTerm{{MatchExpressions: []v1.NodeSelectorRequirement{allowedRequirement}}},
+		},
+		{
+			name:          "node selector not overlapping the allowed architectures",
+			pod:           NewPod().WithNodeSelectors(utils.ArchLabel, utils.ArchitectureS390x).Build(),
+			allowed:       sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64),
+			wantTerms:     []v1.NodeSelectorTerm{{MatchExpressions: []v1.NodeSelectorRequirement{noAllowedRequirement}}},
+			wantNoAllowed: true,
+		},
+		{
+			name: "node affinity terms overlapping the allowed architectures",
+			pod: NewPod().WithNodeSelectorTermsMatchExpressions(
+				[]v1.NodeSelectorRequirement{
+					{Key: utils.ArchLabel, Operator: v1.NodeSelectorOpIn, Values: []string{utils.ArchitectureS390x}},
+				},
+				[]v1.NodeSelectorRequirement{
+					{Key: utils.ArchLabel, Operator: v1.NodeSelectorOpIn, Values: []string{utils.ArchitectureAmd64}},
+					allowedRequirement,
+				}).Build(),
+			allowed: sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64),
+			wantTerms: []v1.NodeSelectorTerm{
+				{MatchExpressions: []v1.NodeSelectorRequirement{
+					{Key: utils.ArchLabel, Operator: v1.NodeSelectorOpIn, Values: []string{utils.ArchitectureS390x}},
+					allowedRequirement,
+				}},
+				{MatchExpressions: []v1.NodeSelectorRequirement{
+					{Key: utils.ArchLabel, Operator: v1.NodeSelectorOpIn, Values: []string{utils.ArchitectureAmd64}},
+					allowedRequirement,
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			pod.restrictArchitectureSelection(tt.allowed)
+			g.Expect(pod.Spec.NodeSelector).To(Equal(tt.pod.Spec.NodeSelector), "the node selector should not change")
+			if tt.wantTerms == nil {
+				g.Expect(pod.Spec.Affinity).To(BeNil())
+			} else {
+				g.Expect(pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).
+					To(Equal(tt.wantTerms))
+			}
+			g.Expect(pod.Labels).To(WithTransform(func(labels map[string]string) bool {
+				_, ok := labels[utils.NoAllowedArchLabel]
+				return ok
+			}, Equal(tt.wantNoAllowed)))
+		})
+	}
+}
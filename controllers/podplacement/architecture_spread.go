@@ -0,0 +1,155 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podplacement
+
+import (
+	"context"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+// cachedArchitecturesReader is implemented by the image inspection caches that can report the architectures of an
+// image already inspected, without querying the registry.
+type cachedArchitecturesReader interface {
+	GetCachedCompatibleArchitecturesSet(imageReference string, secrets [][]byte) (sets.Set[string], bool)
+}
+
+// cachedImagesArchitectures returns the architectures supported by all the images of the pod, if all of them are
+// already in the image inspection cache.
+func (pod *Pod) cachedImagesArchitectures(pullSecretDataList [][]byte) (sets.Set[string], bool) {
+	reader, ok := imageInspectionCache.(cachedArchitecturesReader)
+	if !ok {
+		return nil, false
+	}
+	var supportedArchitecturesSet sets.Set[string]
+	for imageContainer := range pod.imagesNamesSet() {
+		architectures, ok := reader.GetCachedCompatibleArchitecturesSet(imageContainer.imageName, pullSecretDataList)
+		if !ok {
+			return nil, false
+		}
+		if supportedArchitecturesSet == nil {
+			supportedArchitecturesSet = architectures
+		} else {
+			supportedArchitecturesSet = supportedArchitecturesSet.Intersection(architectures)
+		}
+	}
+	return supportedArchitecturesSet, supportedArchitecturesSet != nil
+}
+
+// hasTopologySpreadConstraintFor returns true if the pod has a topologySpreadConstraint on the given topology key.
+func (pod *Pod) hasTopologySpreadConstraintFor(topologyKey string) bool {
+	for _, constraint := range pod.Spec.TopologySpreadConstraints {
+		if constraint.TopologyKey == topologyKey {
+			return true
+		}
+	}
+	return false
+}
+
+// revisionLabels are set by the workload controllers to tell apart the revisions or the replicas of the same workload.
+// They are excluded from the architecture spread selector, so that the spread is computed across the revisions,
+// and it is not reset by the rollouts.
+var revisionLabels = sets.New(
+	appsv1.DefaultDeploymentUniqueLabelKey,
+	appsv1.ControllerRevisionHashLabelKey,
+	appsv1.StatefulSetPodNameLabel,
+	appsv1.PodIndexLabel,
+	batchv1.JobCompletionIndexAnnotation,
+)
+
+// architectureSpreadLabelSelector returns the selector of the pods the architecture spread is computed over: the
+// pods with the same labels, excluding the ones managed by the operator, as they change during the processing, and
+// the revisionLabels. It returns nil if the pod has no other labels.
+func (pod *Pod) architectureSpreadLabelSelector() *metav1.LabelSelector {
+	matchLabels := map[string]string{}
+	for key, value := range pod.Labels {
+		if strings.HasPrefix(key, utils.LabelGroup+"/") || revisionLabels.Has(key) {
+			continue
+		}
+		matchLabels[key] = value
+	}
+	if len(matchLabels) == 0 {
+		return nil
+	}
+	return &metav1.LabelSelector{MatchLabels: matchLabels}
+}
+
+// setArchitectureSpread adds the topologySpreadConstraint on the kubernetes.io/arch label configured by the plugin.
+// It returns false if the pod already has a spread constraint on the same key or if its siblings cannot be selected.
+func (pod *Pod) setArchitectureSpread(plugin *plugins.ArchitectureSpread) bool {
+	if pod.hasTopologySpreadConstraintFor(utils.ArchLabel) {
+		return false
+	}
+	labelSelector := pod.architectureSpreadLabelSelector()
+	if labelSelector == nil {
+		return false
+	}
+	pod.Spec.TopologySpreadConstraints = append(pod.Spec.TopologySpreadConstraints,
+		plugin.TopologySpreadConstraint(utils.ArchLabel, labelSelector))
+	return true
+}
+
+// architectureSpread adds the topologySpreadConstraint on the kubernetes.io/arch label to the pods of the workloads
+// known to run on more than one architecture, when the ArchitectureSpread plugin is enabled in the matching
+// PodPlacementConfig. It returns true if the pod was mutated.
+func (a *PodSchedulingGateMutatingWebHook) architectureSpread(ctx context.Context, pod *Pod) bool {
+	localPlugins := pod.localPlugins()
+	if localPlugins == nil || !localPlugins.PluginEnabled(common.ArchitectureSpreadPluginName) ||
+		pod.hasTopologySpreadConstraintFor(utils.ArchLabel) || !a.isOwnerMultiArch(ctx, pod) {
+		return false
+	}
+	return pod.setArchitectureSpread(localPlugins.ArchitectureSpread)
+}
+
+// isOwnerMultiArch returns true if the controller owner of the pod is known to run on more than one architecture.
+// The knowledge comes from the image inspection cache, when all the images of the pod were already inspected, or from
+// the utils.MultiArchOwnerAnnotation of the owner. The result is cached per owner.
+func (a *PodSchedulingGateMutatingWebHook) isOwnerMultiArch(ctx context.Context, pod *Pod) bool {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return false
+	}
+	if multiArch, ok := a.multiArchOwners.Get(owner.UID); ok {
+		return multiArch
+	}
+	log := ctrllog.FromContext(ctx).WithValues("owner", owner.Name, "ownerKind", owner.Kind)
+	psdl, _ := pullSecretDataList(ctx, a.clientSet, pod)
+	if architectures, ok := pod.cachedImagesArchitectures(psdl); ok {
+		a.multiArchOwners.Add(owner.UID, architectures.Len() > 1)
+		return architectures.Len() > 1
+	}
+	ownerMetadata := &metav1.PartialObjectMetadata{}
+	ownerMetadata.SetGroupVersionKind(schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind))
+	if err := a.apiReader.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: owner.Name}, ownerMetadata); err != nil {
+		log.V(2).Info("Unable to get the owner of the pod", "error", err.Error())
+		return false
+	}
+	multiArch := ownerMetadata.Annotations[utils.MultiArchOwnerAnnotation] == utils.True
+	a.multiArchOwners.Add(owner.UID, multiArch)
+	return multiArch
+}
@@ -0,0 +1,102 @@
+package podplacement
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+func TestPod_setArchitectureSpread(t *testing.T) {
+	plugin := &plugins.ArchitectureSpread{
+		BasePlugin: plugins.BasePlugin{Enabled: true},
+		MaxSkew:    2,
+	}
+	zoneSpread := v1.TopologySpreadConstraint{
+		MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: v1.DoNotSchedule,
+	}
+	archSpread := v1.TopologySpreadConstraint{
+		MaxSkew: 1, TopologyKey: utils.ArchLabel, WhenUnsatisfiable: v1.DoNotSchedule,
+	}
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want []v1.TopologySpreadConstraint
+	}{
+		{
+			name: "pod without spread constraints",
+			pod: NewPod().WithLabels("app", "web", utils.SchedulingGateLabel, utils.SchedulingGateLabelValueGated).
+				Build(),
+			want: []v1.TopologySpreadConstraint{
+				{
+					MaxSkew: 2, TopologyKey: utils.ArchLabel, WhenUnsatisfiable: v1.ScheduleAnyway,
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+			},
+		},
+		{
+			name: "pod with a spread constraint on another key",
+			pod:  NewPod().WithLabels("app", "web").WithTopologySpreadConstraints(zoneSpread).Build(),
+			want: []v1.TopologySpreadConstraint{
+				zoneSpread,
+				{
+					MaxSkew: 2, TopologyKey: utils.ArchLabel, WhenUnsatisfiable: v1.ScheduleAnyway,
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+			},
+		},
+		{
+			name: "pod with a user spread constraint on the architecture",
+			pod:  NewPod().WithLabels("app", "web").WithTopologySpreadConstraints(archSpread).Build(),
+			want: []v1.TopologySpreadConstraint{archSpread},
+		},
+		{
+			name: "pod of a deployment revision",
+			pod: NewPod().WithLabels("app", "web", appsv1.DefaultDeploymentUniqueLabelKey, "5d8f7c9b6").
+				Build(),
+			want: []v1.TopologySpreadConstraint{
+				{
+					MaxSkew: 2, TopologyKey: utils.ArchLabel, WhenUnsatisfiable: v1.ScheduleAnyway,
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+			},
+		},
+		{
+			name: "pod of a statefulset",
+			pod: NewPod().WithLabels("app", "db", appsv1.ControllerRevisionHashLabelKey, "db-7c9b6",
+				appsv1.StatefulSetPodNameLabel, "db-0", appsv1.PodIndexLabel, "0").Build(),
+			want: []v1.TopologySpreadConstraint{
+				{
+					MaxSkew: 2, TopologyKey: utils.ArchLabel, WhenUnsatisfiable: v1.ScheduleAnyway,
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+				},
+			},
+		},
+		{
+			name: "pod with only the revision labels",
+			pod:  NewPod().WithLabels(appsv1.DefaultDeploymentUniqueLabelKey, "5d8f7c9b6").Build(),
+			want: nil,
+		},
+		{
+			name: "pod with only the labels managed by the operator",
+			pod:  NewPod().WithLabels(utils.SchedulingGateLabel, utils.SchedulingGateLabelValueGated).Build(),
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			pod.setArchitectureSpread(plugin)
+			g.Expect(pod.Spec.TopologySpreadConstraints).To(Equal(tt.want))
+		})
+	}
+}
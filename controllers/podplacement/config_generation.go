@@ -0,0 +1,115 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podplacement
+
+import (
+	"context"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/informers/clusterpodplacementconfig"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+// setConfigGeneration records the generation and the placement configuration hash of the ClusterPodPlacementConfig the
+// pod is gated or placed with.
+func (pod *Pod) setConfigGeneration(cppc *v1beta1.ClusterPodPlacementConfig) {
+	if cppc == nil {
+		return
+	}
+	pod.EnsureAnnotation(utils.ConfigGenerationAnnotation, strconv.FormatInt(cppc.Generation, 10))
+	pod.EnsureAnnotation(utils.PlacementSpecHashAnnotation, clusterpodplacementconfig.PlacementSpecHash(cppc))
+}
+
+// isConfigOutdated returns true if the pod was gated with a previous placement configuration of the
+// ClusterPodPlacementConfig. The changes of the ClusterPodPlacementConfig ignored by
+// clusterpodplacementconfig.PlacementSpecChanged, e.g., of the log verbosity or of the operands, are ignored.
+func (pod *Pod) isConfigOutdated(cppc *v1beta1.ClusterPodPlacementConfig) bool {
+	if cppc == nil {
+		return false
+	}
+	return pod.Annotations[utils.PlacementSpecHashAnnotation] != clusterpodplacementconfig.PlacementSpecHash(cppc)
+}
+
+// realignWithConfig re-evaluates the decisions taken at admission time for a pod gated with a previous generation of
+// the ClusterPodPlacementConfig, so that the pod is processed consistently with the current one.
+// It returns false if the namespace of the pod is no longer selected by the ClusterPodPlacementConfig.
+func (r *PodReconciler) realignWithConfig(ctx context.Context, pod *Pod, cppc *v1beta1.ClusterPodPlacementConfig) (bool, error) {
+	log := ctrllog.FromContext(ctx)
+	selected, err := r.isNamespaceSelected(ctx, pod.Namespace, cppc.Spec.NamespaceSelector)
+	if err != nil || !selected {
+		return selected, err
+	}
+	log.V(1).Info("Re-evaluating the admission decisions for the configuration change",
+		"from", pod.Annotations[utils.ConfigGenerationAnnotation], "to", cppc.Generation)
+	// The labels set by the admission hooks of the plugins are reset, as the plugins may have been disabled.
+	pod.EnsureNoLabel(utils.PreferredNodeAffinityLabel)
+	pod.publishPluginFailures(placementPlugins.OnAdmit(pod, cppc))
+	pod.setConfigGeneration(cppc)
+	return true, nil
+}
+
+// isNamespaceSelected returns true if the labels of the given namespace match the namespaceSelector of the
+// ClusterPodPlacementConfig.
+func (r *PodReconciler) isNamespaceSelected(ctx context.Context, namespace string,
+	namespaceSelector *metav1.LabelSelector) (bool, error) {
+	if namespaceSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+	if err != nil {
+		return false, err
+	}
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// notifyPlacementSpecChanged is the handler of the placement configuration changes. It does not block the informer:
+// the gated pods are listed when the event is processed by the controller, and at most one event is pending.
+func (r *PodReconciler) notifyPlacementSpecChanged(cppc *v1beta1.ClusterPodPlacementConfig) {
+	select {
+	case r.placementSpecChanges <- event.TypedGenericEvent[*v1beta1.ClusterPodPlacementConfig]{Object: cppc}:
+	default:
+	}
+}
+
+// gatedPods maps a change of the placement configuration to the reconcile requests of all the gated pods.
+func (r *PodReconciler) gatedPods(ctx context.Context, _ *v1beta1.ClusterPodPlacementConfig) []reconcile.Request {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.MatchingLabels{
+		utils.SchedulingGateLabel: utils.SchedulingGateLabelValueGated,
+	}); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "Unable to list the gated pods")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(podList.Items))
+	for i := range podList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&podList.Items[i])})
+	}
+	return requests
+}
@@ -0,0 +1,107 @@
+package podplacement
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/informers/clusterpodplacementconfig"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+func TestPod_isConfigOutdated(t *testing.T) {
+	cppc := NewClusterPodPlacementConfig().Build()
+	cppc.Generation = 2
+	previous := NewClusterPodPlacementConfig().WithNodeAffinityScoring(true).
+		WithNodeAffinityScoringTerm(utils.ArchitectureAmd64, 50).Build()
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		cppc *v1beta1.ClusterPodPlacementConfig
+		want bool
+	}{
+		{
+			name: "no configuration",
+			pod:  NewPod().Build(),
+			want: false,
+		},
+		{
+			name: "pod gated before the placement configuration hash was recorded",
+			pod:  NewPod().WithAnnotations(map[string]string{utils.ConfigGenerationAnnotation: "2"}).Build(),
+			cppc: cppc,
+			want: true,
+		},
+		{
+			name: "pod gated with a previous placement configuration",
+			pod: NewPod().WithAnnotations(map[string]string{
+				utils.ConfigGenerationAnnotation:  "1",
+				utils.PlacementSpecHashAnnotation: clusterpodplacementconfig.PlacementSpecHash(previous),
+			}).Build(),
+			cppc: cppc,
+			want: true,
+		},
+		{
+			name: "pod gated with a previous generation of the same placement configuration",
+			pod: NewPod().WithAnnotations(map[string]string{
+				utils.ConfigGenerationAnnotation:  "1",
+				utils.PlacementSpecHashAnnotation: clusterpodplacementconfig.PlacementSpecHash(cppc),
+			}).Build(),
+			cppc: cppc,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(newPod(tt.pod, context.TODO(), nil).isConfigOutdated(tt.cppc)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPodReconciler_realignWithConfig(t *testing.T) {
+	tests := []struct {
+		name                       string
+		pod                        *v1.Pod
+		cppc                       *v1beta1.ClusterPodPlacementConfig
+		wantPreferredAffinityLabel string
+	}{
+		{
+			name: "node affinity scoring disabled after the admission",
+			pod: NewPod().WithLabels(utils.PreferredNodeAffinityLabel, utils.LabelValueNotSet).
+				WithAnnotations(map[string]string{utils.ConfigGenerationAnnotation: "1"}).Build(),
+			cppc: NewClusterPodPlacementConfig().WithNodeAffinityScoring(false).Build(),
+		},
+		{
+			name: "node affinity scoring enabled after the admission",
+			pod:  NewPod().WithAnnotations(map[string]string{utils.ConfigGenerationAnnotation: "1"}).Build(),
+			cppc: NewClusterPodPlacementConfig().WithNodeAffinityScoring(true).
+				WithNodeAffinityScoringTerm(utils.ArchitectureAmd64, 50).Build(),
+			wantPreferredAffinityLabel: utils.LabelValueNotSet,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			tt.cppc.Generation = 2
+			pod := newPod(tt.pod, context.TODO(), nil)
+			selected, err := (&PodReconciler{}).realignWithConfig(context.TODO(), pod, tt.cppc)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(selected).To(BeTrue())
+			if tt.wantPreferredAffinityLabel == "" {
+				g.Expect(pod.Labels).NotTo(HaveKey(utils.PreferredNodeAffinityLabel))
+			} else {
+				g.Expect(pod.Labels).To(HaveKeyWithValue(utils.PreferredNodeAffinityLabel, tt.wantPreferredAffinityLabel))
+			}
+			g.Expect(pod.Annotations).To(HaveKeyWithValue(utils.ConfigGenerationAnnotation, "2"))
+			g.Expect(pod.Annotations).To(HaveKeyWithValue(utils.PlacementSpecHashAnnotation,
+				clusterpodplacementconfig.PlacementSpecHash(tt.cppc)))
+			g.Expect(pod.isConfigOutdated(tt.cppc)).To(BeFalse())
+		})
+	}
+}
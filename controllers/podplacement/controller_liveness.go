@@ -0,0 +1,101 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podplacement
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	coordinationinformers "k8s.io/client-go/informers/coordination/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// controllerLivenessGracePeriod is the time, in addition to the lease duration, after which a lease that was not
+// renewed is considered expired. It absorbs the clock skew between the nodes and the latency of the informer.
+const controllerLivenessGracePeriod = 30 * time.Second
+
+// ControllerLivenessChecker watches the leader election Lease of the pod placement controllers and reports whether
+// a leader is actively renewing it. The webhook uses it to avoid gating pods that no controller would ungate.
+// The checker runs in every replica of the webhook, regardless of the leader election.
+type ControllerLivenessChecker struct {
+	namespace string
+	name      string
+	informer  cache.SharedIndexInformer
+	log       logr.Logger
+}
+
+func NewControllerLivenessChecker(clientSet *kubernetes.Clientset, namespace, name string) *ControllerLivenessChecker {
+	return &ControllerLivenessChecker{
+		namespace: namespace,
+		name:      name,
+		informer: coordinationinformers.NewFilteredLeaseInformer(clientSet, namespace, time.Hour, cache.Indexers{},
+			func(options *metav1.ListOptions) {
+				options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+			}),
+	}
+}
+
+func (c *ControllerLivenessChecker) Start(ctx context.Context) error {
+	c.log = log.FromContext(ctx, "handler", "ControllerLivenessChecker", "kind", "Lease [coordination.k8s.io/v1]",
+		"namespace", c.namespace, "name", c.name)
+	c.log.Info("Starting the controller liveness checker")
+	c.informer.Run(ctx.Done())
+	c.log.Info("Stopping the controller liveness checker")
+	return nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface: every replica of the webhook needs to know
+// the liveness of the controller.
+func (c *ControllerLivenessChecker) NeedLeaderElection() bool {
+	return false
+}
+
+// IsHealthy returns whether the pod placement controller is considered alive.
+// A nil checker, or a checker whose informer has not synced yet, reports a healthy controller so that the webhook
+// keeps its default behavior.
+func (c *ControllerLivenessChecker) IsHealthy() bool {
+	if c == nil || !c.informer.HasSynced() {
+		return true
+	}
+	obj, exists, err := c.informer.GetStore().GetByKey(c.namespace + "/" + c.name)
+	if err != nil || !exists {
+		return false
+	}
+	lease, ok := obj.(*coordinationv1.Lease)
+	if !ok {
+		return false
+	}
+	return isLeaseHeld(lease, time.Now())
+}
+
+// isLeaseHeld returns true if the lease has a holder and was renewed within its duration, plus a grace period.
+func isLeaseHeld(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" ||
+		lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	expiration := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds)*time.Second +
+		controllerLivenessGracePeriod)
+	return now.Before(expiration)
+}
@@ -0,0 +1,79 @@
+package podplacement
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+func TestControllerLivenessChecker_IsHealthy_nil(t *testing.T) {
+	g := NewGomegaWithT(t)
+	var c *ControllerLivenessChecker
+	g.Expect(c.IsHealthy()).To(BeTrue())
+}
+
+func Test_isLeaseHeld(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		lease coordinationv1.LeaseSpec
+		want  bool
+	}{
+		{
+			name: "lease renewed recently",
+			lease: coordinationv1.LeaseSpec{
+				HolderIdentity:       utils.NewPtr("controller-1"),
+				LeaseDurationSeconds: utils.NewPtr(int32(15)),
+				RenewTime:            &metav1.MicroTime{Time: now.Add(-5 * time.Second)},
+			},
+			want: true,
+		},
+		{
+			name: "lease expired within the grace period",
+			lease: coordinationv1.LeaseSpec{
+				HolderIdentity:       utils.NewPtr("controller-1"),
+				LeaseDurationSeconds: utils.NewPtr(int32(15)),
+				RenewTime:            &metav1.MicroTime{Time: now.Add(-30 * time.Second)},
+			},
+			want: true,
+		},
+		{
+			name: "lease expired beyond the grace period",
+			lease: coordinationv1.LeaseSpec{
+				HolderIdentity:       utils.NewPtr("controller-1"),
+				LeaseDurationSeconds: utils.NewPtr(int32(15)),
+				RenewTime:            &metav1.MicroTime{Time: now.Add(-time.Minute)},
+			},
+			want: false,
+		},
+		{
+			name: "lease released by the holder",
+			lease: coordinationv1.LeaseSpec{
+				HolderIdentity:       utils.NewPtr(""),
+				LeaseDurationSeconds: utils.NewPtr(int32(15)),
+				RenewTime:            &metav1.MicroTime{Time: now},
+			},
+			want: false,
+		},
+		{
+			name: "lease without renew time",
+			lease: coordinationv1.LeaseSpec{
+				HolderIdentity:       utils.NewPtr("controller-1"),
+				LeaseDurationSeconds: utils.NewPtr(int32(15)),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(isLeaseHeld(&coordinationv1.Lease{Spec: tt.lease}, now)).To(Equal(tt.want))
+		})
+	}
+}
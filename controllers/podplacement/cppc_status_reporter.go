@@ -0,0 +1,240 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podplacement
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/image"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+const (
+	cppcStatusReportInterval = time.Minute
+	// topFailingRegistries is the maximum number of registries reported in the status.
+	topFailingRegistries = 5
+	// countedPodsPageSize is the number of pods listed per request when counting the pods, to bound the memory and the
+	// load on the API server in large clusters.
+	countedPodsPageSize = 500
+)
+
+// CPPCStatusReporter accumulates the statistics observed by the pod placement controller and periodically
+// reports them in the status of the ClusterPodPlacementConfig.
+// Updates are batched to bound the rate of writes to the API server: at most one status patch per interval, and none
+// if the statistics did not change.
+// The reporter runs only in the leader replica of the pod placement controller.
+type CPPCStatusReporter struct {
+	client client.Client
+	// apiReader is used to count the pods without going through the cache of the manager, that only holds the
+	// pending pods.
+	apiReader client.Reader
+	// imageStats returns the cumulative statistics of the image inspections. It is optional.
+	imageStats func() image.CacheStats
+	interval   time.Duration
+	log        logr.Logger
+
+	podsWithoutEligibleNodes atomic.Int64
+	processedPods            atomic.Int64
+	inspectionErrors         atomic.Int64
+	// lastImageStats are the image statistics at the last report, to compute the ones of the last window.
+	lastImageStats image.CacheStats
+}
+
+func NewCPPCStatusReporter(client client.Client, apiReader client.Reader, imageStats func() image.CacheStats) *CPPCStatusReporter {
+	return &CPPCStatusReporter{
+		client:     client,
+		apiReader:  apiReader,
+		imageStats: imageStats,
+		interval:   cppcStatusReportInterval,
+	}
+}
+
+func (r *CPPCStatusReporter) Start(ctx context.Context) error {
+	r.log = log.FromContext(ctx, "handler", "CPPCStatusReporter")
+	r.log.Info("Starting the ClusterPodPlacementConfig status reporter", "interval", r.interval)
+	wait.UntilWithContext(ctx, r.report, r.interval)
+	r.log.Info("Stopping the ClusterPodPlacementConfig status reporter")
+	return nil
+}
+
+// IncPodsWithoutEligibleNodes records a pod for which none of the architectures supported by its images is
+// available in the cluster. It is safe to call on a nil reporter.
+func (r *CPPCStatusReporter) IncPodsWithoutEligibleNodes() {
+	if r == nil {
+		return
+	}
+	r.podsWithoutEligibleNodes.Add(1)
+}
+
+// IncProcessedPods records a gated pod processed by the controller. It is safe to call on a nil reporter.
+func (r *CPPCStatusReporter) IncProcessedPods() {
+	if r == nil {
+		return
+	}
+	r.processedPods.Add(1)
+}
+
+// IncInspectionErrors records a gated pod whose images could not be inspected. It is safe to call on a nil reporter.
+func (r *CPPCStatusReporter) IncInspectionErrors() {
+	if r == nil {
+		return
+	}
+	r.inspectionErrors.Add(1)
+}
+
+// report patches the status of the ClusterPodPlacementConfig with the statistics accumulated since the last report.
+// If the patch fails, the statistics are kept and reported at the next interval.
+func (r *CPPCStatusReporter) report(ctx context.Context) {
+	cppc := &v1beta1.ClusterPodPlacementConfig{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: common.SingletonResourceObjectName}, cppc); err != nil {
+		r.log.Error(err, "Unable to get the ClusterPodPlacementConfig")
+		return
+	}
+	podsWithoutEligibleNodes := r.podsWithoutEligibleNodes.Load()
+	processedPods := r.processedPods.Load()
+	inspectionErrors := r.inspectionErrors.Load()
+	var imageStats image.CacheStats
+	if r.imageStats != nil {
+		imageStats = r.imageStats()
+	}
+	stats := &v1beta1.PodPlacementStats{}
+	if cppc.Status.Stats != nil {
+		stats.PodsWithoutEligibleNodes = cppc.Status.Stats.PodsWithoutEligibleNodes
+	}
+	stats.PodsWithoutEligibleNodes += podsWithoutEligibleNodes
+	setWindowStats(stats, processedPods, inspectionErrors, r.lastImageStats, imageStats)
+	if err := r.setClusterStats(ctx, stats); err != nil {
+		r.log.Error(err, "Unable to collect the statistics of the cluster")
+		return
+	}
+	if cppc.Status.Stats != nil {
+		stats.LastUpdateTime = cppc.Status.Stats.LastUpdateTime
+	}
+	if equality.Semantic.DeepEqual(cppc.Status.Stats, stats) {
+		r.log.V(3).Info("The statistics did not change")
+	} else {
+		original := cppc.DeepCopy()
+		stats.LastUpdateTime = metav1.Now()
+		cppc.Status.Stats = stats
+		// The optimistic lock prevents overriding the status concurrently updated by the operator.
+		if err := r.client.Status().Patch(ctx, cppc,
+			client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+			r.log.Error(err, "Unable to patch the ClusterPodPlacementConfig status")
+			return
+		}
+	}
+	r.podsWithoutEligibleNodes.Add(-podsWithoutEligibleNodes)
+	r.processedPods.Add(-processedPods)
+	r.inspectionErrors.Add(-inspectionErrors)
+	r.lastImageStats = imageStats
+}
+
+// setClusterStats sets the statistics about the current state of the cluster: the gated pods, the pods whose images
+// have no architecture in common and the architectures of the nodes.
+func (r *CPPCStatusReporter) setClusterStats(ctx context.Context, stats *v1beta1.PodPlacementStats) error {
+	var err error
+	if stats.GatedPods, err = r.countPods(ctx, client.MatchingLabels{
+		utils.SchedulingGateLabel: utils.SchedulingGateLabelValueGated,
+	}); err != nil {
+		return err
+	}
+	if stats.PodsWithNoSupportedArchitectures, err = r.countPods(ctx,
+		client.HasLabels{utils.NoSupportedArchLabel}); err != nil {
+		return err
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.client.List(ctx, nodes); err != nil {
+		return err
+	}
+	architectures := sets.New[string]()
+	for i := range nodes.Items {
+		if arch, ok := nodes.Items[i].Labels[utils.ArchLabel]; ok {
+			architectures.Insert(arch)
+		}
+	}
+	stats.Architectures = sets.List(architectures)
+	return nil
+}
+
+// countPods counts the pods matching the given options, listing them in pages of countedPodsPageSize pods.
+func (r *CPPCStatusReporter) countPods(ctx context.Context, opts ...client.ListOption) (int64, error) {
+	var count int64
+	continueToken := ""
+	for {
+		// Only the metadata of the pods is needed to count them.
+		pods := &metav1.PartialObjectMetadataList{}
+		pods.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PodList"))
+		if err := r.apiReader.List(ctx, pods, append(opts, client.Limit(countedPodsPageSize),
+			client.Continue(continueToken))...); err != nil {
+			return 0, err
+		}
+		count += int64(len(pods.Items))
+		if continueToken = pods.Continue; continueToken == "" {
+			return count, nil
+		}
+	}
+}
+
+// setWindowStats sets the statistics of the last window, given the counters of the pods processed in the window and
+// the cumulative image statistics at the beginning and at the end of the window.
+func setWindowStats(stats *v1beta1.PodPlacementStats, processedPods, inspectionErrors int64,
+	previous, current image.CacheStats) {
+	stats.ProcessedPods = processedPods
+	stats.InspectionErrors = inspectionErrors
+	stats.InspectionErrorPercentage = percentage(inspectionErrors, processedPods)
+	stats.ImageCacheSize = int32(current.Size)
+	hits, misses := current.Hits-previous.Hits, current.Misses-previous.Misses
+	stats.ImageCacheHitPercentage = percentage(hits, hits+misses)
+	stats.TopFailingRegistries = nil
+	for registry, failures := range current.FailuresByRegistry {
+		if failures -= previous.FailuresByRegistry[registry]; failures > 0 {
+			stats.TopFailingRegistries = append(stats.TopFailingRegistries, v1beta1.RegistryInspectionFailures{
+				Registry: registry,
+				Failures: failures,
+			})
+		}
+	}
+	slices.SortFunc(stats.TopFailingRegistries, func(a, b v1beta1.RegistryInspectionFailures) int {
+		return cmp.Or(cmp.Compare(b.Failures, a.Failures), cmp.Compare(a.Registry, b.Registry))
+	})
+	if len(stats.TopFailingRegistries) > topFailingRegistries {
+		stats.TopFailingRegistries = stats.TopFailingRegistries[:topFailingRegistries]
+	}
+}
+
+func percentage(part, total int64) int32 {
+	if total == 0 {
+		return 0
+	}
+	return int32(part * 100 / total)
+}
@@ -0,0 +1,88 @@
+package podplacement
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/image"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+func Test_setWindowStats(t *testing.T) {
+	tests := []struct {
+		name             string
+		processedPods    int64
+		inspectionErrors int64
+		previous         image.CacheStats
+		current          image.CacheStats
+		want             v1beta1.PodPlacementStats
+	}{
+		{
+			name: "no activity",
+			want: v1beta1.PodPlacementStats{},
+		},
+		{
+			name:             "activity in the window",
+			processedPods:    40,
+			inspectionErrors: 10,
+			previous: image.CacheStats{
+				Size: 10, Hits: 100, Misses: 50,
+				FailuresByRegistry: map[string]int64{"quay.io": 3},
+			},
+			current: image.CacheStats{
+				Size: 12, Hits: 130, Misses: 60,
+				FailuresByRegistry: map[string]int64{"quay.io": 3, "docker.io": 2, "registry.example.com": 8},
+			},
+			want: v1beta1.PodPlacementStats{
+				ProcessedPods:             40,
+				InspectionErrors:          10,
+				InspectionErrorPercentage: 25,
+				ImageCacheSize:            12,
+				ImageCacheHitPercentage:   75,
+				TopFailingRegistries: []v1beta1.RegistryInspectionFailures{
+					{Registry: "registry.example.com", Failures: 8},
+					{Registry: "docker.io", Failures: 2},
+				},
+			},
+		},
+		{
+			name: "more failing registries than reported",
+			current: image.CacheStats{
+				FailuresByRegistry: map[string]int64{"a.io": 1, "b.io": 2, "c.io": 3, "d.io": 4, "e.io": 5, "f.io": 6},
+			},
+			want: v1beta1.PodPlacementStats{
+				TopFailingRegistries: []v1beta1.RegistryInspectionFailures{
+					{Registry: "f.io", Failures: 6},
+					{Registry: "e.io", Failures: 5},
+					{Registry: "d.io", Failures: 4},
+					{Registry: "c.io", Failures: 3},
+					{Registry: "b.io", Failures: 2},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			stats := &v1beta1.PodPlacementStats{}
+			setWindowStats(stats, tt.processedPods, tt.inspectionErrors, tt.previous, tt.current)
+			g.Expect(*stats).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestCPPCStatusReporter_countPods(t *testing.T) {
+	g := NewGomegaWithT(t)
+	reader := &pagedPodsReader{}
+	for range countedPodsPageSize + 1 {
+		reader.pods = append(reader.pods, *NewPod().WithSchedulingGates(utils.SchedulingGateName).Build())
+	}
+	count, err := NewCPPCStatusReporter(nil, reader, nil).countPods(ctx)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(len(reader.pods))), "the pods of all the pages should be counted")
+	g.Expect(reader.requests).To(Equal(2), "the pods should be listed in pages")
+}
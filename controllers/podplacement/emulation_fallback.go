@@ -0,0 +1,243 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podplacement
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+// imagesArchitectures returns the architectures supported by each of the images used by the pod.
+func (pod *Pod) imagesArchitectures(ctx context.Context, pullSecretDataList [][]byte) ([]sets.Set[string], error) {
+	imageNamesSet := pod.imagesNamesSet()
+	imagesArchitectures := make([]sets.Set[string], 0, imageNamesSet.Len())
+	for imageContainer := range imageNamesSet {
+		now := time.Now()
+		architectures, err := imageInspectionCache.GetCompatibleArchitecturesSet(ctx,
+			imageContainer.imageName, imageContainer.skipCache, pullSecretDataList)
+		utils.HistogramObserve(now, metrics.TimeToInspectImage)
+		if err != nil {
+			return nil, err
+		}
+		imagesArchitectures = append(imagesArchitectures, architectures)
+	}
+	return imagesArchitectures, nil
+}
+
+// emulationTarget computes how to run a pod whose images have no architecture in common.
+// The native architecture is the one supported by the largest number of images, ties broken alphabetically, among the
+// allowed architectures, if not nil. For each image not supporting it, the first architecture of the image,
+// alphabetically, is emulated. It returns false if the images have a common architecture, if any of them supports
+// no architecture or if none of the architectures they support is allowed.
+func emulationTarget(imagesArchitectures []sets.Set[string], allowedArchitectures sets.Set[string]) (native string,
+	emulated []string, ok bool) {
+	supportCount := map[string]int{}
+	for _, architectures := range imagesArchitectures {
+		if architectures.Len() == 0 {
+			return "", nil, false
+		}
+		for architecture := range architectures {
+			supportCount[architecture]++
+		}
+	}
+	for _, architecture := range sets.List(sets.KeySet(supportCount)) {
+		if allowedArchitectures != nil && !allowedArchitectures.Has(architecture) {
+			continue
+		}
+		if supportCount[architecture] > supportCount[native] {
+			native = architecture
+		}
+	}
+	if native == "" || supportCount[native] == len(imagesArchitectures) {
+		// No supported architecture is allowed, or the native one is supported by all the images.
+		return "", nil, false
+	}
+	emulatedSet := sets.New[string]()
+	for _, architectures := range imagesArchitectures {
+		if !architectures.Has(native) {
+			emulatedSet.Insert(sets.List(architectures)[0])
+		}
+	}
+	return native, sets.List(emulatedSet), true
+}
+
+// setEmulationFallback sets the runtimeClassName of the pod and requires a node of the native architecture, able to
+// emulate all the given architectures. The pod is labeled and annotated as emulated.
+// As for the architecture-aware node affinity, the nodeSelectorTerms that already have a requirement for the
+// kubernetes.io/arch label are not modified.
+func (pod *Pod) setEmulationFallback(native string, emulated []string, runtimeClassName string) {
+	requirements := []corev1.NodeSelectorRequirement{
+		{
+			Key:      utils.ArchLabel,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{native},
+		},
+	}
+	for _, architecture := range emulated {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      utils.EmulatesArchLabel(architecture),
+			Operator: corev1.NodeSelectorOpExists,
+		})
+	}
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	nodeSelector := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = make([]corev1.NodeSelectorTerm, 1)
+	}
+	for i := range nodeSelector.NodeSelectorTerms {
+		if nodeSelectorTermHasKey(nodeSelector.NodeSelectorTerms[i], utils.ArchLabel) {
+			continue
+		}
+		nodeSelector.NodeSelectorTerms[i].MatchExpressions = append(nodeSelector.NodeSelectorTerms[i].MatchExpressions,
+			requirements...)
+	}
+	pod.Spec.RuntimeClassName = &runtimeClassName
+	pod.EnsureLabel(utils.NodeAffinityLabel, utils.NodeAffinityLabelValueSet)
+	pod.EnsureLabel(utils.EmulatedLabel, utils.True)
+	pod.EnsureLabel(utils.ArchLabelValue(native), "")
+	pod.EnsureAnnotation(utils.EmulatedArchitecturesAnnotation, strings.Join(emulated, ","))
+}
+
+func nodeSelectorTermHasKey(term corev1.NodeSelectorTerm, key string) bool {
+	for _, expression := range term.MatchExpressions {
+		if expression.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmulated returns true if the pod is labeled as sent to emulation. As the label can be set by the user too, the
+// emulation fallback of the pods labeled as emulated is verified when the webhook is reinvoked.
+func (pod *Pod) isEmulated() bool {
+	return pod.Labels[utils.EmulatedLabel] == utils.True
+}
+
+// hasEmulationFallbackRequirements returns true if the pod requires, in at least one of its nodeSelectorTerms, a node
+// of the native architecture able to emulate all the given architectures, as set by setEmulationFallback.
+func (pod *Pod) hasEmulationFallbackRequirements(native string, emulated []string) bool {
+	if _, ok := pod.Spec.NodeSelector[utils.ArchLabel]; ok {
+		return false
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return false
+	}
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		found := 0
+		for _, expression := range term.MatchExpressions {
+			switch {
+			case expression.Key == utils.ArchLabel && expression.Operator == corev1.NodeSelectorOpIn &&
+				len(expression.Values) == 1 && expression.Values[0] == native:
+				found++
+			case expression.Operator == corev1.NodeSelectorOpExists && slices.ContainsFunc(emulated,
+				func(architecture string) bool { return expression.Key == utils.EmulatesArchLabel(architecture) }):
+				found++
+			}
+		}
+		if found == len(emulated)+1 {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmulationFallbackCandidate returns true if the pod can be sent to a node able to emulate the architectures of its
+// images: the runtimeClassName of the pod must not be set by the user. The pods already labeled as emulated with the
+// runtimeClassName of the plugin are candidates too, for their emulation fallback to be verified on reinvocation.
+func (pod *Pod) isEmulationFallbackCandidate(runtimeClassName string) bool {
+	if pod.Spec.RuntimeClassName != nil && *pod.Spec.RuntimeClassName != "" &&
+		(!pod.isEmulated() || *pod.Spec.RuntimeClassName != runtimeClassName) {
+		ctrllog.FromContext(pod.Ctx()).V(2).Info("The pod already has a runtimeClassName, skipping the emulation fallback")
+		return false
+	}
+	return true
+}
+
+// emulationFallback sends the pod to a node able to emulate the architectures of its images when they have no
+// architecture in common. The images are inspected within the timeout of the plugin, using the inspection cache
+// when possible. The native architecture must be allowed by the allowedArchitectures policy of the pod.
+// It returns true if the pod was mutated to run under emulation.
+func (a *PodSchedulingGateMutatingWebHook) emulationFallback(ctx context.Context, pod *Pod,
+	cppc *v1beta1.ClusterPodPlacementConfig) bool {
+	log := ctrllog.FromContext(ctx)
+	plugin := cppc.Spec.Plugins.EmulationFallback
+	if !pod.isEmulationFallbackCandidate(plugin.RuntimeClassName) || !a.isEmulationFallbackEnabledFor(ctx, pod.Namespace) {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, plugin.GetInspectionTimeout())
+	defer cancel()
+	psdl, err := pullSecretDataList(ctx, a.clientSet, pod)
+	if err != nil {
+		log.Error(err, "Unable to retrieve the image pull secret data for the pod, skipping the emulation fallback")
+		return false
+	}
+	imagesArchitectures, err := pod.imagesArchitectures(ctx, psdl)
+	if err != nil {
+		// The pod placement controller will retry the inspection once the pod is gated.
+		log.V(2).Info("Unable to inspect the images at admission time, skipping the emulation fallback",
+			"error", err.Error())
+		return false
+	}
+	ppc, err := matchingPodPlacementConfig(ctx, a.client, pod)
+	if err != nil {
+		log.Error(err, "Unable to retrieve the allowed architectures of the pod, skipping the emulation fallback")
+		return false
+	}
+	native, emulated, ok := emulationTarget(imagesArchitectures, allowedArchitectures(ppc, cppc))
+	if !ok {
+		return false
+	}
+	if pod.isEmulated() && !pod.hasEmulationFallbackRequirements(native, emulated) {
+		log.V(2).Info("The pod labeled as emulated does not require the emulation target of its images",
+			"native", native, "emulated", emulated)
+		return false
+	}
+	log.V(1).Info("Sending the pod to a node able to emulate the architectures of its images",
+		"native", native, "emulated", emulated, "runtimeClassName", plugin.RuntimeClassName)
+	pod.setEmulationFallback(native, emulated, plugin.RuntimeClassName)
+	return true
+}
+
+// isEmulationFallbackEnabledFor returns true if the namespace opted in the EmulationFallback plugin.
+func (a *PodSchedulingGateMutatingWebHook) isEmulationFallbackEnabledFor(ctx context.Context, namespace string) bool {
+	ns := &corev1.Namespace{}
+	if err := a.client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "Unable to get the namespace of the pod, skipping the emulation fallback")
+		return false
+	}
+	return ns.Labels[utils.EmulationFallbackNamespaceLabel] == utils.EmulationFallbackNamespaceLabelEnabled
+}
@@ -0,0 +1,278 @@
+package podplacement
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+func Test_emulationTarget(t *testing.T) {
+	tests := []struct {
+		name                string
+		imagesArchitectures []sets.Set[string]
+		allowed             sets.Set[string]
+		wantNative          string
+		wantEmulated        []string
+		wantOk              bool
+	}{
+		{
+			name: "images with a common architecture",
+			imagesArchitectures: []sets.Set[string]{
+				sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64),
+				sets.New(utils.ArchitectureArm64),
+			},
+			wantOk: false,
+		},
+		{
+			name: "image supporting no architecture",
+			imagesArchitectures: []sets.Set[string]{
+				sets.New(utils.ArchitectureAmd64),
+				sets.New[string](),
+			},
+			wantOk: false,
+		},
+		{
+			name: "the native architecture is the one supported by most images",
+			imagesArchitectures: []sets.Set[string]{
+				sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64),
+				sets.New(utils.ArchitectureArm64),
+				sets.New(utils.ArchitectureS390x),
+			},
+			wantNative:   utils.ArchitectureArm64,
+			wantEmulated: []string{utils.ArchitectureS390x},
+			wantOk:       true,
+		},
+		{
+			name: "ties are broken alphabetically",
+			imagesArchitectures: []sets.Set[string]{
+				sets.New(utils.ArchitectureS390x),
+				sets.New(utils.ArchitectureArm64),
+				sets.New(utils.ArchitecturePpc64le, utils.ArchitectureS390x),
+			},
+			wantNative:   utils.ArchitectureS390x,
+			wantEmulated: []string{utils.ArchitectureArm64},
+			wantOk:       true,
+		},
+		{
+			name: "multiple emulated architectures",
+			imagesArchitectures: []sets.Set[string]{
+				sets.New(utils.ArchitectureAmd64),
+				sets.New(utils.ArchitectureArm64),
+				sets.New(utils.ArchitecturePpc64le, utils.ArchitectureS390x),
+			},
+			wantNative:   utils.ArchitectureAmd64,
+			wantEmulated: []string{utils.ArchitectureArm64, utils.ArchitecturePpc64le},
+			wantOk:       true,
+		},
+		{
+			name: "the native architecture is the most supported among the allowed ones",
+			imagesArchitectures: []sets.Set[string]{
+				sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64),
+				sets.New(utils.ArchitectureArm64),
+				sets.New(utils.ArchitectureAmd64, utils.ArchitectureS390x),
+			},
+			allowed:      sets.New(utils.ArchitectureAmd64, utils.ArchitectureS390x),
+			wantNative:   utils.ArchitectureAmd64,
+			wantEmulated: []string{utils.ArchitectureArm64},
+			wantOk:       true,
+		},
+		{
+			name: "the common architecture of the images is not allowed",
+			imagesArchitectures: []sets.Set[string]{
+				sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64),
+				sets.New(utils.ArchitectureArm64),
+			},
+			allowed:      sets.New(utils.ArchitectureAmd64),
+			wantNative:   utils.ArchitectureAmd64,
+			wantEmulated: []string{utils.ArchitectureArm64},
+			wantOk:       true,
+		},
+		{
+			name: "no architecture supported by the images is allowed",
+			imagesArchitectures: []sets.Set[string]{
+				sets.New(utils.ArchitectureAmd64),
+				sets.New(utils.ArchitectureArm64),
+			},
+			allowed: sets.New(utils.ArchitectureS390x),
+			wantOk:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			native, emulated, ok := emulationTarget(tt.imagesArchitectures, tt.allowed)
+			g.Expect(ok).To(Equal(tt.wantOk))
+			g.Expect(native).To(Equal(tt.wantNative))
+			g.Expect(emulated).To(Equal(tt.wantEmulated))
+		})
+	}
+}
+
+func TestPod_setEmulationFallback(t *testing.T) {
+	emulationRequirements := []v1.NodeSelectorRequirement{
+		{Key: utils.ArchLabel, Operator: v1.NodeSelectorOpIn, Values: []string{utils.ArchitectureAmd64}},
+		{Key: utils.EmulatesArchLabel(utils.ArchitectureArm64), Operator: v1.NodeSelectorOpExists},
+	}
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want []v1.NodeSelectorTerm
+	}{
+		{
+			name: "pod without node affinity",
+			pod:  NewPod().Build(),
+			want: []v1.NodeSelectorTerm{{MatchExpressions: emulationRequirements}},
+		},
+		{
+			name: "pod with node selector terms",
+			pod: NewPod().WithAffinity(&v1.Affinity{
+				NodeAffinity: &v1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+						NodeSelectorTerms: []v1.NodeSelectorTerm{
+							{MatchExpressions: []v1.NodeSelectorRequirement{
+								{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"a"}},
+							}},
+							{MatchExpressions: []v1.NodeSelectorRequirement{
+								{Key: utils.ArchLabel, Operator: v1.NodeSelectorOpIn, Values: []string{utils.ArchitectureS390x}},
+							}},
+						},
+					},
+				},
+			}).Build(),
+			want: []v1.NodeSelectorTerm{
+				{MatchExpressions: append([]v1.NodeSelectorRequirement{
+					{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"a"}},
+				}, emulationRequirements...)},
+				{MatchExpressions: []v1.NodeSelectorRequirement{
+					{Key: utils.ArchLabel, Operator: v1.NodeSelectorOpIn, Values: []string{utils.ArchitectureS390x}},
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			pod.setEmulationFallback(utils.ArchitectureAmd64, []string{utils.ArchitectureArm64}, "qemu")
+			g.Expect(pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).
+				To(Equal(tt.want))
+			g.Expect(pod.Spec.RuntimeClassName).To(Equal(utils.NewPtr("qemu")))
+			g.Expect(pod.Labels).To(HaveKeyWithValue(utils.EmulatedLabel, utils.True))
+			g.Expect(pod.Labels).To(HaveKeyWithValue(utils.NodeAffinityLabel, utils.NodeAffinityLabelValueSet))
+			g.Expect(pod.Annotations).To(HaveKeyWithValue(utils.EmulatedArchitecturesAnnotation, utils.ArchitectureArm64))
+		})
+	}
+}
+
+func TestPod_hasEmulationFallbackRequirements(t *testing.T) {
+	emulatedPod := func() *v1.Pod {
+		pod := newPod(NewPod().WithAffinity(&v1.Affinity{
+			NodeAffinity: &v1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+					NodeSelectorTerms: []v1.NodeSelectorTerm{
+						{MatchExpressions: []v1.NodeSelectorRequirement{
+							{Key: utils.ArchLabel, Operator: v1.NodeSelectorOpIn, Values: []string{utils.ArchitectureS390x}},
+						}},
+						{},
+					},
+				},
+			},
+		}).Build(), ctx, nil)
+		pod.setEmulationFallback(utils.ArchitectureAmd64, []string{utils.ArchitectureArm64}, "qemu")
+		return pod.PodObject()
+	}
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		native   string
+		emulated []string
+		want     bool
+	}{
+		{
+			name:     "pod sent to emulation",
+			pod:      emulatedPod(),
+			native:   utils.ArchitectureAmd64,
+			emulated: []string{utils.ArchitectureArm64},
+			want:     true,
+		},
+		{
+			name:     "pod sent to emulation of another native architecture",
+			pod:      emulatedPod(),
+			native:   utils.ArchitectureArm64,
+			emulated: []string{utils.ArchitectureAmd64},
+			want:     false,
+		},
+		{
+			name:     "pod sent to emulation of fewer architectures",
+			pod:      emulatedPod(),
+			native:   utils.ArchitectureAmd64,
+			emulated: []string{utils.ArchitectureArm64, utils.ArchitecturePpc64le},
+			want:     false,
+		},
+		{
+			name: "pod labeled as emulated with a user-set architecture requirement",
+			pod: NewPod().WithLabels(utils.EmulatedLabel, utils.True).
+				WithNodeSelectors(utils.ArchLabel, utils.ArchitectureAmd64).Build(),
+			native:   utils.ArchitectureAmd64,
+			emulated: []string{utils.ArchitectureArm64},
+			want:     false,
+		},
+		{
+			name:     "pod labeled as emulated without node affinity",
+			pod:      NewPod().WithLabels(utils.EmulatedLabel, utils.True).Build(),
+			native:   utils.ArchitectureAmd64,
+			emulated: []string{utils.ArchitectureArm64},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			g.Expect(pod.hasEmulationFallbackRequirements(tt.native, tt.emulated)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPod_isEmulationFallbackCandidate(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want bool
+	}{
+		{
+			name: "pod without runtimeClassName",
+			pod:  NewPod().Build(),
+			want: true,
+		},
+		{
+			name: "pod with a user-set runtimeClassName",
+			pod:  NewPod().WithRuntimeClassName("qemu").Build(),
+			want: false,
+		},
+		{
+			name: "pod labeled as emulated with the runtimeClassName of the plugin",
+			pod:  NewPod().WithLabels(utils.EmulatedLabel, utils.True).WithRuntimeClassName("qemu").Build(),
+			want: true,
+		},
+		{
+			name: "pod labeled as emulated with another runtimeClassName",
+			pod:  NewPod().WithLabels(utils.EmulatedLabel, utils.True).WithRuntimeClassName("other").Build(),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			g.Expect(pod.isEmulationFallbackCandidate("qemu")).To(Equal(tt.want))
+		})
+	}
+}
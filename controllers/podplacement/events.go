@@ -3,24 +3,51 @@ package podplacement
 import "github.com/openshift/multiarch-tuning-operator/pkg/utils"
 
 const (
-	ArchitecturePredicatesConflict                = "ArchAwarePredicatesConflict"
-	ImageArchitectureInspectionError              = "ArchAwareInspectionError"
-	ArchitectureAwareNodeAffinitySet              = "ArchAwarePredicateSet"
-	ArchitectureAwareGatedPodIgnored              = "ArchAwareGatedPodIgnored"
-	ArchitectureAwareSchedulingGateAdded          = "ArchAwareSchedGateAdded"
-	ArchitectureAwareSchedulingGateRemovalFailure = "ArchAwareSchedGateRemovalFailed"
-	ArchitectureAwareSchedulingGateRemovalSuccess = "ArchAwareSchedGateRemovalSuccess"
-	NoSupportedArchitecturesFound                 = "NoSupportedArchitecturesFound"
+	ImageArchitectureInspectionError                   = "ArchAwareInspectionError"
+	ArchitectureAwareTolerationsSet                    = "ArchAwareTolerationsSet"
+	ArchitectureAwareNodeAffinitySet                   = "ArchAwarePredicateSet"
+	ArchitectureAwareGatedPodIgnored                   = "ArchAwareGatedPodIgnored"
+	ArchitectureAwareSchedulingGateAdded               = "ArchAwareSchedGateAdded"
+	ArchitectureAwareSchedulingGateRemovalFailure      = "ArchAwareSchedGateRemovalFailed"
+	ArchitectureAwareSchedulingGateRemovalSuccess      = "ArchAwareSchedGateRemovalSuccess"
+	NoSupportedArchitecturesFound                      = "NoSupportedArchitecturesFound"
+	NoEligibleNodeArchitectureFound                    = "NoEligibleNodeArchitectureFound"
+	NoAllowedArchitecturesFound                        = "NoAllowedArchitecturesFound"
+	ArchitectureAwareSchedulingGateDeadlineExceeded    = "ArchAwareSchedGateDeadlineExceeded"
+	ArchitectureAwarePodPlacementControllerUnavailable = "ArchAwarePodPlacementControllerUnavailable"
+	ArchitectureAwareTopologySpreadSet                 = "ArchAwareTopologySpreadSet"
+	ArchitectureAwarePluginError                       = "ArchAwarePluginError"
+	ArchitectureAwareEmulationFallbackSet              = "ArchAwareEmulationFallbackSet"
+	ArchitectureAwareImageVolumesSkipped               = "ArchAwareImageVolumesSkipped"
+	ArchitectureSelectionConflict                      = "ArchAwareSelectionConflict"
+	ArchitectureAwareImageDigestsPinned                = "ArchAwareImageDigestsPinned"
+	ArchitectureAwareImagesRewritten                   = "ArchAwareImagesRewritten"
+	ArchitectureAwareGatedPodOutOfScope                = "ArchAwareGatedPodOutOfScope"
 
 	SchedulingGateAddedMsg                   = "Successfully gated with the " + utils.SchedulingGateName + " scheduling gate"
 	SchedulingGateRemovalSuccessMsg          = "Successfully removed the " + utils.SchedulingGateName + " scheduling gate"
 	SchedulingGateRemovalFailureMsg          = "Failed to remove the scheduling gate \"" + utils.SchedulingGateName + "\""
-	ArchitecturePredicatesConflictMsg        = "All the scheduling predicates already include architecture-specific constraints"
 	ArchitecturePredicateSetupMsg            = "Set the supported architectures to "
+	ArchitectureTolerationsSetMsg            = "Added the tolerations for the taints of the nodes dedicated to the supported architectures: "
 	ArchitecturePreferredPredicateSetupMsg   = "Set the architecture preferences in the nodeAffinity"
 	ArchitecturePreferredPredicateSkippedMsg = "The node affinity already includes architecture preferences"
 	ImageArchitectureInspectionErrorMsg      = "Failed to retrieve the supported architectures: "
 	NoSupportedArchitecturesFoundMsg         = "Pod cannot be scheduled due to incompatible image architectures; container images have no supported architectures in common"
+	NoAllowedArchitecturesFoundMsg           = "Pod cannot be scheduled: none of the architectures supported by the container images is allowed by the allowedArchitectures policy, that allows: "
+	NoAllowedArchitecturesSelectedMsg        = "Pod cannot be scheduled: none of the architectures selected for the pod is allowed by the allowedArchitectures policy, that allows: "
+	NoEligibleNodeArchitectureFoundMsg       = "No node in the cluster can run the pod: the architectures supported by the container images are "
 	ArchitectureAwareGatedPodIgnoredMsg      = "The gated pod has been modified and is no longer eligible for architecture-aware scheduling"
 	ImageInspectionErrorMaxRetriesMsg        = "Failed to retrieve the supported architectures after multiple retries"
+	AllContainersAssumedMultiArchMsg         = "All the images are assumed multi-arch or architecture-agnostic, no architecture requirement is set"
+	ArchitectureSelectionConflictMsg         = "The architectures selected in the nodeSelector or nodeAffinity are not supported by the container images, the pod will fail to start. The images support: "
+	ArchitectureSelectionConflictWarning     = "the architectures selected in the nodeSelector or nodeAffinity of the pod are not supported by its container images, that support: %s"
+	ImageVolumesSkippedMsg                   = "The images of the following volumes are architecture-agnostic and were not inspected: "
+	ImageDigestsPinnedMsg                    = "Pinned the images to the inspected digests for the following containers: "
+	ImagesRewrittenMsg                       = "Rewrote the images to their %s variants for the following containers: %s"
+	PodPlacementControllerUnavailableMsg     = "The pod placement controller is not available: the pod was not gated and no architecture-aware node affinity will be set"
+	ArchitectureTopologySpreadSetMsg         = "Added a topologySpreadConstraint to spread the replicas across the architectures"
+	PluginErrorMsg                           = "The %s plugin failed in the %s hook: %s"
+	GatedPodOutOfScopeMsg                    = "The namespace of the gated pod is no longer selected by the ClusterPodPlacementConfig"
+	EmulationFallbackSetMsg                  = "No architecture is supported by all the container images: the pod will run on a node able to emulate "
+	SchedulingGateDeadlineExceededMsg        = "The pod exceeded the scheduling gate deadline: the " + utils.SchedulingGateName + " scheduling gate was removed without setting the architecture-aware node affinity"
 )
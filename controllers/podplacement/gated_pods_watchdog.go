@@ -0,0 +1,143 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podplacement
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
+	"github.com/openshift/multiarch-tuning-operator/pkg/informers/clusterpodplacementconfig"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+const (
+	gatedPodsWatchdogInterval = time.Minute
+	// gatedPodsPageSize is the number of gated pods listed per request, to bound the memory and the load on the API
+	// server when many pods are gated.
+	gatedPodsPageSize = 500
+)
+
+// GatedPodsWatchdog periodically lists the pods gated by the pod placement operand and takes care of the ones that
+// have been gated for longer than the scheduling gate deadline configured in the ClusterPodPlacementConfig.
+// This covers the cases in which the PodReconciler misses an event, crashes repeatedly or cannot see a pod
+// because of the field selector of the cache.
+// It also maintains the mto_ppo_pods_gated gauge.
+// The watchdog runs only in the leader replica of the pod placement controller.
+type GatedPodsWatchdog struct {
+	reconciler *PodReconciler
+	// apiReader is used to list the gated pods without going through the cache of the manager.
+	apiReader client.Reader
+	interval  time.Duration
+	log       logr.Logger
+}
+
+func NewGatedPodsWatchdog(reconciler *PodReconciler, apiReader client.Reader) *GatedPodsWatchdog {
+	return &GatedPodsWatchdog{
+		reconciler: reconciler,
+		apiReader:  apiReader,
+		interval:   gatedPodsWatchdogInterval,
+	}
+}
+
+func (w *GatedPodsWatchdog) Start(ctx context.Context) error {
+	w.log = log.FromContext(ctx, "handler", "GatedPodsWatchdog")
+	w.log.Info("Starting the gated pods watchdog", "interval", w.interval)
+	metrics.InitPodPlacementControllerMetrics()
+	wait.UntilWithContext(ctx, w.sweep, w.interval)
+	w.log.Info("Stopping the gated pods watchdog")
+	return nil
+}
+
+// sweep lists the gated pods, updates the gated pods gauge and handles the pods that exceeded the deadline.
+// The gated pods are listed in pages of gatedPodsPageSize pods.
+func (w *GatedPodsWatchdog) sweep(ctx context.Context) {
+	deadline := clusterpodplacementconfig.GetClusterPodPlacementConfig().GetSchedulingGateDeadline()
+	now := time.Now()
+	gatedPods := 0
+	continueToken := ""
+	for {
+		podList := &corev1.PodList{}
+		if err := w.apiReader.List(ctx, podList, client.MatchingLabels{
+			utils.SchedulingGateLabel: utils.SchedulingGateLabelValueGated,
+		}, client.Limit(gatedPodsPageSize), client.Continue(continueToken)); err != nil {
+			w.log.Error(err, "Unable to list the gated pods")
+			return
+		}
+		gatedPods += len(podList.Items)
+		for i := range podList.Items {
+			plog := w.log.WithValues("namespace", podList.Items[i].Namespace, "name", podList.Items[i].Name)
+			pod := newPod(&podList.Items[i], log.IntoContext(ctx, plog), w.reconciler.Recorder)
+			if !pod.isStuck(deadline, now) {
+				continue
+			}
+			w.handleStuckPod(pod)
+		}
+		if continueToken = podList.Continue; continueToken == "" {
+			break
+		}
+	}
+	metrics.GatedPodsGauge.Set(float64(gatedPods))
+}
+
+// isStuck returns true if the pod has been gated for longer than the deadline. The pods waiting for a node with a
+// supported architecture are intentionally kept gated.
+func (pod *Pod) isStuck(deadline time.Duration, now time.Time) bool {
+	return pod.isGatedBeyondDeadline(deadline, now) && !pod.isWaitingForEligibleNode()
+}
+
+// forceRemoveSchedulingGate removes the scheduling gate from a pod that is still gated after the retry and records the
+// reason in the pod annotations. It returns false if the pod was already ungated.
+func (pod *Pod) forceRemoveSchedulingGate() bool {
+	if !pod.HasSchedulingGate() {
+		return false
+	}
+	log.FromContext(pod.Ctx()).Info("The pod is still gated after the retry. Removing the scheduling gate.")
+	pod.RemoveSchedulingGate()
+	pod.EnsureAnnotation(utils.SchedulingGateRemovalReasonAnnotation, utils.SchedulingGateDeadlineExceeded)
+	pod.PublishEvent(corev1.EventTypeWarning, ArchitectureAwareSchedulingGateDeadlineExceeded,
+		SchedulingGateDeadlineExceededMsg)
+	metrics.ForcedUngatedPodsCounter.Inc()
+	return true
+}
+
+// handleStuckPod tries to process the pod once more and, if the pod is still gated afterward, it removes the
+// scheduling gate and records the reason in the pod annotations.
+func (w *GatedPodsWatchdog) handleStuckPod(pod *Pod) {
+	ctx := pod.Ctx()
+	plog := log.FromContext(ctx)
+	metrics.StuckGatedPodsCounter.Inc()
+	plog.Info("The pod exceeded the scheduling gate deadline. Retrying to process it.",
+		"creationTimestamp", pod.CreationTimestamp)
+	// The retry is not counted in the statistics reported in the status of the ClusterPodPlacementConfig.
+	w.reconciler.processPod(ctx, pod)
+	pod.forceRemoveSchedulingGate()
+	if err := w.reconciler.Update(ctx, pod.PodObject()); err != nil {
+		// The pod will be considered again at the next sweep.
+		plog.Error(err, "Unable to update the pod")
+		pod.PublishEvent(corev1.EventTypeWarning, ArchitectureAwareSchedulingGateRemovalFailure, SchedulingGateRemovalFailureMsg)
+		return
+	}
+	pod.PublishEvent(corev1.EventTypeNormal, ArchitectureAwareSchedulingGateRemovalSuccess, SchedulingGateRemovalSuccessMsg)
+}
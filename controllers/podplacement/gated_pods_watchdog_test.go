@@ -0,0 +1,137 @@
+package podplacement
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	metrics2 "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+func TestGatedPodsWatchdog_stuckPods(t *testing.T) {
+	metrics.InitPodPlacementControllerMetrics()
+	now := time.Now()
+	deadline := 10 * time.Minute
+	tests := []struct {
+		name          string
+		pod           *v1.Pod
+		wantStuck     bool
+		wantUngated   bool
+		wantAnnotated bool
+		wantGates     []v1.PodSchedulingGate
+	}{
+		{
+			name: "gated pod beyond the deadline",
+			pod: NewPod().WithSchedulingGates(utils.SchedulingGateName).
+				WithCreationTimestamp(metav1.NewTime(now.Add(-11 * time.Minute))).Build(),
+			wantStuck:     true,
+			wantUngated:   true,
+			wantAnnotated: true,
+		},
+		{
+			name: "gated pod within the deadline",
+			pod: NewPod().WithSchedulingGates(utils.SchedulingGateName).
+				WithCreationTimestamp(metav1.NewTime(now.Add(-9 * time.Minute))).Build(),
+			wantStuck:     false,
+			wantUngated:   true,
+			wantAnnotated: true,
+		},
+		{
+			name: "gated pod beyond the deadline waiting for an eligible node",
+			pod: NewPod().WithSchedulingGates(utils.SchedulingGateName).
+				WithLabels(utils.NoEligibleNodeArchLabel, utils.NoEligibleNodeArchLabelWaiting).
+				WithCreationTimestamp(metav1.NewTime(now.Add(-time.Hour))).Build(),
+			wantStuck:     false,
+			wantUngated:   true,
+			wantAnnotated: true,
+		},
+		{
+			name: "already ungated pod beyond the deadline",
+			pod: NewPod().WithSchedulingGates("other-gate").
+				WithCreationTimestamp(metav1.NewTime(now.Add(-time.Hour))).Build(),
+			wantStuck:     false,
+			wantUngated:   false,
+			wantAnnotated: false,
+			wantGates:     []v1.PodSchedulingGate{{Name: "other-gate"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			g.Expect(pod.isStuck(deadline, now)).To(Equal(tt.wantStuck))
+			g.Expect(pod.forceRemoveSchedulingGate()).To(Equal(tt.wantUngated))
+			g.Expect(pod.HasSchedulingGate()).To(BeFalse())
+			g.Expect(pod.Spec.SchedulingGates).To(ConsistOf(tt.wantGates))
+			if tt.wantAnnotated {
+				g.Expect(pod.Annotations).To(HaveKeyWithValue(utils.SchedulingGateRemovalReasonAnnotation,
+					utils.SchedulingGateDeadlineExceeded))
+			} else {
+				g.Expect(pod.Annotations).NotTo(HaveKey(utils.SchedulingGateRemovalReasonAnnotation))
+			}
+		})
+	}
+}
+
+// pagedPodsReader serves the pods in pages of the requested size, as the API server does.
+type pagedPodsReader struct {
+	client.Reader
+	pods     []v1.Pod
+	requests int
+}
+
+func (r *pagedPodsReader) List(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOptions := (&client.ListOptions{}).ApplyOptions(opts)
+	r.requests++
+	start, end := 0, len(r.pods)
+	if listOptions.Continue != "" {
+		start, _ = strconv.Atoi(listOptions.Continue)
+	}
+	if listOptions.Limit > 0 {
+		end = min(start+int(listOptions.Limit), len(r.pods))
+	}
+	switch l := list.(type) {
+	case *v1.PodList:
+		l.Items = r.pods[start:end]
+	case *metav1.PartialObjectMetadataList:
+		for i := range r.pods[start:end] {
+			l.Items = append(l.Items, metav1.PartialObjectMetadata{ObjectMeta: r.pods[start+i].ObjectMeta})
+		}
+	}
+	if end < len(r.pods) {
+		list.SetContinue(strconv.Itoa(end))
+	}
+	return nil
+}
+
+func TestGatedPodsWatchdog_sweepPages(t *testing.T) {
+	g := NewGomegaWithT(t)
+	metrics.InitPodPlacementControllerMetrics()
+	reader := &pagedPodsReader{}
+	for range 2*gatedPodsPageSize + 1 {
+		reader.pods = append(reader.pods, *NewPod().WithSchedulingGates(utils.SchedulingGateName).
+			WithCreationTimestamp(metav1.Now()).Build())
+	}
+	NewGatedPodsWatchdog(&PodReconciler{}, reader).sweep(ctx)
+	g.Expect(reader.requests).To(Equal(3), "the gated pods should be listed in pages")
+	families, err := metrics2.Registry.Gather()
+	g.Expect(err).NotTo(HaveOccurred())
+	gatedPods := 0.0
+	for _, family := range families {
+		if family.GetName() == "mto_ppo_pods_gated" {
+			gatedPods = family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	g.Expect(gatedPods).To(Equal(float64(len(reader.pods))), "the gated pods of all the pages should be counted")
+}
@@ -25,6 +25,8 @@ import (
 	"github.com/go-logr/logr"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	clientv1 "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -54,8 +56,12 @@ func (s *GlobalPullSecretSyncer) Start(ctx context.Context) (err error) {
 		"namespace", s.namespace, "name", s.name)
 	s.log.Info("Starting System Config Syncer")
 	clientSet := s.clientSet
-	// Watch the Secret that contains the global pull secret and Sync the inspector
-	globalPullSecretInformer := clientv1.NewSecretInformer(clientSet, s.namespace, time.Hour, cache.Indexers{})
+	// Watch the Secret that contains the global pull secret and Sync the inspector. Only the global pull secret is
+	// listed, so that the access to the other secrets of its namespace is not required.
+	globalPullSecretInformer := clientv1.NewFilteredSecretInformer(clientSet, s.namespace, time.Hour, cache.Indexers{},
+		func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", s.name).String()
+		})
 
 	_, err = globalPullSecretInformer.AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
@@ -0,0 +1,108 @@
+package podplacement
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/image"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+// imageInspector is implemented by the image inspection facades that return the architectures of the images together
+// with the digests of the manifests, or indexes, they were computed from.
+type imageInspector interface {
+	InspectImage(ctx context.Context, imageReference string, skipCache bool, secrets [][]byte) (image.Inspection, error)
+}
+
+// imageRepository returns the image reference without its tag or digest.
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// pinImageDigests rewrites the images of the inspected containers of the pod to the digests of the manifests, or
+// indexes, that were inspected, so that the kubelet pulls the exact images whose architectures are known even if their
+// tags are moved before the pod starts. It must be called while the pod is gated, after the images are inspected: the
+// digests are the ones recorded by the inspection that computed the architectures of the pod. The images already referenced by
+// digest, the ones excluded by the pin-digest configuration and the ones assumed multi-arch are not modified. The
+// original images are recorded in the utils.OriginalImagesAnnotation annotation. The images of the image volumes are
+// never rewritten, as the volumes of a pod are immutable. Failing to resolve a digest is not fatal: the image is left
+// unchanged.
+func (pod *Pod) pinImageDigests(pinning *v1beta1.ImageDigestPinning) {
+	if pinning == nil {
+		return
+	}
+	log := ctrllog.FromContext(pod.Ctx())
+	originalImages := pod.originalImages()
+	assumedMultiArch := pod.assumedMultiArchContainers()
+	pinned := sets.New[string]()
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			container := &containers[i]
+			if assumedMultiArch.Has(container.Name) || strings.Contains(container.Image, "@") ||
+				pinning.Excludes(pod.Namespace, container.Image) {
+				continue
+			}
+			manifestDigest, ok := pod.inspectedDigests[fmt.Sprintf("//%s", container.Image)]
+			if !ok {
+				log.V(2).Info("The digest of the image was not inspected, it will not be pinned",
+					"container", container.Name, "image", container.Image)
+				continue
+			}
+			if _, ok := originalImages[container.Name]; !ok {
+				// The images rewritten by the ImageRewriteRules keep the reference set by the user.
+				originalImages[container.Name] = container.Image
+			}
+			container.Image = fmt.Sprintf("%s@%s", imageRepository(container.Image), manifestDigest)
+			pinned.Insert(container.Name)
+		}
+	}
+	if pinned.Len() == 0 {
+		return
+	}
+	pod.setOriginalImages(originalImages)
+	pod.PublishEvent(corev1.EventTypeNormal, ArchitectureAwareImageDigestsPinned,
+		ImageDigestsPinnedMsg+strings.Join(sets.List(pinned), ", "))
+}
+
+// originalImages returns the original images of the rewritten containers, as recorded in the
+// utils.OriginalImagesAnnotation annotation.
+func (pod *Pod) originalImages() map[string]string {
+	originalImages := map[string]string{}
+	value, ok := pod.Annotations[utils.OriginalImagesAnnotation]
+	if !ok {
+		return originalImages
+	}
+	if err := json.Unmarshal([]byte(value), &originalImages); err != nil {
+		ctrllog.FromContext(pod.Ctx()).Error(err, "Unable to unmarshal the original images, they will be overwritten")
+		return map[string]string{}
+	}
+	return originalImages
+}
+
+// setOriginalImages records the original images of the rewritten containers in the utils.OriginalImagesAnnotation
+// annotation.
+func (pod *Pod) setOriginalImages(originalImages map[string]string) {
+	// json.Marshal sorts the keys of the map: the annotation is stable across invocations.
+	value, err := json.Marshal(originalImages)
+	if err != nil {
+		ctrllog.FromContext(pod.Ctx()).Error(err, "Unable to marshal the original images")
+		return
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[utils.OriginalImagesAnnotation] = string(value)
+}
@@ -0,0 +1,138 @@
+package podplacement
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	mmoimage "github.com/openshift/multiarch-tuning-operator/pkg/image"
+	"github.com/openshift/multiarch-tuning-operator/pkg/testing/image/fake"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+func Test_imageRepository(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "nginx", want: "nginx"},
+		{image: "nginx:latest", want: "nginx"},
+		{image: "my-registry.io:5000/library/nginx", want: "my-registry.io:5000/library/nginx"},
+		{image: "my-registry.io:5000/library/nginx:1.27", want: "my-registry.io:5000/library/nginx"},
+		{image: "my-registry.io/library/nginx:1.27@sha256:0123", want: "my-registry.io/library/nginx"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(imageRepository(tt.image)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPod_pinImageDigests(t *testing.T) {
+	pinned := func(image string) string {
+		return fmt.Sprintf("%s@%s", imageRepository(image), fake.MockImageDigest(image))
+	}
+	digestImage := "my-registry.io/library/multi-arch-image@sha256:0123"
+	tests := []struct {
+		name               string
+		pod                *v1.Pod
+		pinning            *v1beta1.ImageDigestPinning
+		wantInitImages     []string
+		wantImages         []string
+		wantOriginalImages string
+	}{
+		{
+			name: "pin-digest mode disabled",
+			pod: NewPod().WithInitContainersImages(fake.SingleArchAmd64Image).
+				WithContainersImages(fake.MultiArchImage).Build(),
+			wantInitImages: []string{fake.SingleArchAmd64Image},
+			wantImages:     []string{fake.MultiArchImage},
+		},
+		{
+			name: "containers and init containers are pinned",
+			pod: withContainerNames(NewPod().WithInitContainersImages(fake.SingleArchAmd64Image).
+				WithContainersImages(fake.MultiArchImage, digestImage).Build(), "init", "app", "digest"),
+			pinning:        &v1beta1.ImageDigestPinning{},
+			wantInitImages: []string{pinned(fake.SingleArchAmd64Image)},
+			wantImages:     []string{pinned(fake.MultiArchImage), digestImage},
+			wantOriginalImages: fmt.Sprintf(`{"app":%q,"init":%q}`, fake.MultiArchImage,
+				fake.SingleArchAmd64Image),
+		},
+		{
+			name: "images rewritten by the image rewrite rules keep the original image of the user",
+			pod: withContainerNames(NewPod().WithContainersImages(fake.SingleArchArm64Image).
+				WithAnnotations(map[string]string{
+					utils.OriginalImagesAnnotation: `{"app":"my-registry.io/library/vendor-image:latest"}`,
+				}).Build(), "app"),
+			pinning:            &v1beta1.ImageDigestPinning{},
+			wantImages:         []string{pinned(fake.SingleArchArm64Image)},
+			wantOriginalImages: `{"app":"my-registry.io/library/vendor-image:latest"}`,
+		},
+		{
+			name: "excluded namespace",
+			pod:  NewPod().WithNamespace("excluded").WithContainersImages(fake.MultiArchImage).Build(),
+			pinning: &v1beta1.ImageDigestPinning{
+				ExcludedNamespaces: []string{"excluded"},
+			},
+			wantImages: []string{fake.MultiArchImage},
+		},
+		{
+			name: "excluded image prefix and image that cannot be inspected",
+			pod: withContainerNames(NewPod().WithContainersImages(fake.MultiArchImage, fake.SingleArchArm64Image,
+				"my-registry.io/library/unknown:latest").Build(), "app", "excluded", "unknown"),
+			pinning: &v1beta1.ImageDigestPinning{
+				ExcludedImagePrefixes: []string{"my-registry.io/library/single-arch-"},
+			},
+			wantImages: []string{pinned(fake.MultiArchImage), fake.SingleArchArm64Image,
+				"my-registry.io/library/unknown:latest"},
+			wantOriginalImages: fmt.Sprintf(`{"app":%q}`, fake.MultiArchImage),
+		},
+		{
+			name: "containers assumed multi-arch are not pinned",
+			pod: withContainerNames(NewPod().WithContainersImages(fake.MultiArchImage, fake.MultiArchImage2).
+				WithAnnotations(map[string]string{
+					utils.ContainerDecisionsAnnotation: `{"app":"Inspected","sidecar":"AssumedMultiArch"}`,
+				}).Build(), "app", "sidecar"),
+			pinning:            &v1beta1.ImageDigestPinning{},
+			wantImages:         []string{pinned(fake.MultiArchImage), fake.MultiArchImage2},
+			wantOriginalImages: fmt.Sprintf(`{"app":%q}`, fake.MultiArchImage),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imageInspectionCache = fake.FacadeSingleton()
+			defer func() {
+				imageInspectionCache = mmoimage.FacadeSingleton()
+			}()
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			// The images are pinned to the digests recorded by their inspection: the unknown images are not recorded.
+			for imageContainer := range pod.imagesNamesSet() {
+				_, _ = pod.inspectImage(imageContainer, nil)
+			}
+			pod.pinImageDigests(tt.pinning)
+			images := func(containers []v1.Container) []string {
+				var images []string
+				for _, container := range containers {
+					images = append(images, container.Image)
+				}
+				return images
+			}
+			g.Expect(images(pod.Spec.InitContainers)).To(Equal(tt.wantInitImages))
+			g.Expect(images(pod.Spec.Containers)).To(Equal(tt.wantImages))
+			g.Expect(pod.Annotations[utils.OriginalImagesAnnotation]).To(Equal(tt.wantOriginalImages))
+			// Pinning the images again does not change the pod.
+			pinnedPod := pod.DeepCopy()
+			pod.pinImageDigests(tt.pinning)
+			g.Expect(pod.Spec).To(Equal(pinnedPod.Spec))
+			g.Expect(pod.Annotations).To(Equal(pinnedPod.Annotations))
+		})
+	}
+}
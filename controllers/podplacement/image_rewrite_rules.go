@@ -0,0 +1,165 @@
+package podplacement
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+// imageRewriteRules returns the ImageRewriteRules sorted by name, so that the first rule matching an image is
+// always the same.
+func (r *PodReconciler) imageRewriteRules(ctx context.Context) ([]v1beta1.ImageRewriteRule, error) {
+	rules := &v1beta1.ImageRewriteRuleList{}
+	if err := r.List(ctx, rules); err != nil {
+		return nil, err
+	}
+	sort.Slice(rules.Items, func(i, j int) bool {
+		return rules.Items[i].Name < rules.Items[j].Name
+	})
+	return rules.Items, nil
+}
+
+// imageVariants returns the variants, keyed by architecture, of the images of the containers of the pod matching the
+// first of the rules that matches them. Only the variants whose inspection confirms that they support their
+// architecture are kept. The containers assumed multi-arch and the ones already using a digest are not considered.
+func (pod *Pod) imageVariants(rules []v1beta1.ImageRewriteRule, pullSecretDataList [][]byte) map[string]map[string]string {
+	log := ctrllog.FromContext(pod.Ctx())
+	assumedMultiArch := pod.assumedMultiArchContainers()
+	imageVariants := map[string]map[string]string{}
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		if _, ok := imageVariants[container.Image]; ok || assumedMultiArch.Has(container.Name) ||
+			strings.Contains(container.Image, "@") {
+			continue
+		}
+		for i := range rules {
+			variants, err := rules[i].Variants(container.Image)
+			if err != nil {
+				log.Error(err, "Unable to compute the variants of the image", "image", container.Image)
+				continue
+			}
+			if variants == nil {
+				continue
+			}
+			for architecture, variant := range variants {
+				architectures, err := imageInspectionCache.GetCompatibleArchitecturesSet(pod.Ctx(),
+					fmt.Sprintf("//%s", variant), container.ImagePullPolicy == corev1.PullAlways, pullSecretDataList)
+				if err != nil || !architectures.Has(architecture) {
+					log.V(1).Info("Discarding the variant of the image", "image", container.Image,
+						"variant", variant, "architecture", architecture, "error", err)
+					delete(variants, architecture)
+				}
+			}
+			if len(variants) > 0 {
+				imageVariants[container.Image] = variants
+			}
+			break
+		}
+	}
+	return imageVariants
+}
+
+// rewriteArchitecture returns the architecture the images are rewritten to: the candidate with the highest weight
+// in the given NodeAffinityScoring platforms or, with the same weight, the first one in alphabetical order.
+func rewriteArchitecture(candidates sets.Set[string], platforms []plugins.NodeAffinityScoringPlatformTerm) string {
+	weights := map[string]int32{}
+	for _, platform := range platforms {
+		weights[platform.Architecture] = platform.Weight
+	}
+	target := ""
+	for _, architecture := range sets.List(candidates) {
+		if target == "" || weights[architecture] > weights[target] {
+			target = architecture
+		}
+	}
+	return target
+}
+
+// rewriteImages applies the ImageRewriteRules to the pod while it is gated. The images matching a rule are
+// considered as supporting the architectures of their variants: the target architecture is chosen among the ones
+// supported by all the images of the pod, allowed for the pod, available in the nodes it can be scheduled on and
+// admitted by the architecture selected by the user, if any. The matching images are rewritten to their variant for
+// the target architecture and the original images are recorded in the utils.OriginalImagesAnnotation annotation.
+// It returns the architectures the pod must be restricted to: the target one when the images are rewritten or
+// allowedArchitectures otherwise.
+func (r *PodReconciler) rewriteImages(ctx context.Context, pod *Pod, pullSecretDataList [][]byte,
+	allowedArchitectures sets.Set[string], cppc *v1beta1.ClusterPodPlacementConfig) (sets.Set[string], error) {
+	if architecture, ok := pod.Annotations[utils.ImageRewriteArchitectureAnnotation]; ok {
+		// The images were already rewritten in a previous reconciliation.
+		return sets.New(architecture), nil
+	}
+	rules, err := r.imageRewriteRules(ctx)
+	if err != nil || len(rules) == 0 {
+		return allowedArchitectures, err
+	}
+	imageVariants := pod.imageVariants(rules, pullSecretDataList)
+	if len(imageVariants) == 0 {
+		return allowedArchitectures, nil
+	}
+	candidates := utils.AllSupportedArchitecturesSet()
+	if allowedArchitectures != nil {
+		candidates = candidates.Intersection(allowedArchitectures)
+	}
+	for _, variants := range imageVariants {
+		candidates = candidates.Intersection(sets.KeySet(variants))
+	}
+	for imageContainer := range pod.imagesNamesSet() {
+		if _, ok := imageVariants[strings.TrimPrefix(imageContainer.imageName, "//")]; ok {
+			continue
+		}
+		architectures, err := imageInspectionCache.GetCompatibleArchitecturesSet(ctx, imageContainer.imageName,
+			imageContainer.skipCache, pullSecretDataList)
+		if err != nil {
+			return nil, err
+		}
+		candidates = candidates.Intersection(architectures)
+	}
+	nodeArchitectures, err := r.nodeArchitectures(ctx, pod, true, cppc)
+	if err != nil {
+		return nil, err
+	}
+	candidates = candidates.Intersection(nodeArchitectures)
+	for _, architecture := range sets.List(candidates) {
+		if !pod.architectureSelectionAdmits(sets.New(architecture)) {
+			candidates.Delete(architecture)
+		}
+	}
+	if candidates.Len() == 0 {
+		ctrllog.FromContext(ctx).Info("No architecture is supported by the variants of the images and available, " +
+			"the images are not rewritten")
+		return allowedArchitectures, nil
+	}
+	var platforms []plugins.NodeAffinityScoringPlatformTerm
+	if cppc != nil && cppc.PluginsEnabled(common.NodeAffinityScoringPluginName) {
+		platforms = cppc.NodeAffinityScoringPlatforms()
+	}
+	target := rewriteArchitecture(candidates, platforms)
+	assumedMultiArch := pod.assumedMultiArchContainers()
+	originalImages := pod.originalImages()
+	rewritten := sets.New[string]()
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			variants, ok := imageVariants[containers[i].Image]
+			if !ok || assumedMultiArch.Has(containers[i].Name) {
+				continue
+			}
+			originalImages[containers[i].Name] = containers[i].Image
+			containers[i].Image = variants[target]
+			rewritten.Insert(containers[i].Name)
+		}
+	}
+	pod.setOriginalImages(originalImages)
+	pod.Annotations[utils.ImageRewriteArchitectureAnnotation] = target
+	pod.PublishEvent(corev1.EventTypeNormal, ArchitectureAwareImagesRewritten,
+		fmt.Sprintf(ImagesRewrittenMsg, target, strings.Join(sets.List(rewritten), ", ")))
+	return sets.New(target), nil
+}
@@ -0,0 +1,131 @@
+package podplacement
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	mmoimage "github.com/openshift/multiarch-tuning-operator/pkg/image"
+	"github.com/openshift/multiarch-tuning-operator/pkg/testing/image/fake"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+func Test_rewriteArchitecture(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates sets.Set[string]
+		platforms  []plugins.NodeAffinityScoringPlatformTerm
+		want       string
+	}{
+		{
+			name:       "no preferences",
+			candidates: sets.New(utils.ArchitectureS390x, utils.ArchitectureArm64),
+			want:       utils.ArchitectureArm64,
+		},
+		{
+			name:       "highest weight",
+			candidates: sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64, utils.ArchitecturePpc64le),
+			platforms: []plugins.NodeAffinityScoringPlatformTerm{
+				{Architecture: utils.ArchitectureAmd64, Weight: 10},
+				{Architecture: utils.ArchitecturePpc64le, Weight: 50},
+				{Architecture: utils.ArchitectureS390x, Weight: 100},
+			},
+			want: utils.ArchitecturePpc64le,
+		},
+		{
+			name:       "same weight",
+			candidates: sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64),
+			platforms: []plugins.NodeAffinityScoringPlatformTerm{
+				{Architecture: utils.ArchitectureAmd64, Weight: 50},
+				{Architecture: utils.ArchitectureArm64, Weight: 50},
+			},
+			want: utils.ArchitectureAmd64,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(rewriteArchitecture(tt.candidates, tt.platforms)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPod_imageVariants(t *testing.T) {
+	rules := []v1beta1.ImageRewriteRule{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "single-arch"},
+			Spec: v1beta1.ImageRewriteRuleSpec{
+				ImagePattern: "^my-registry.io/library/single-arch-[a-z0-9]+-image:(?P<tag>.+)$",
+				Variants: []v1beta1.ImageVariant{
+					{Architecture: utils.ArchitectureAmd64, Image: "my-registry.io/library/single-arch-amd64-image:{{ .tag }}"},
+					{Architecture: utils.ArchitectureArm64, Image: "my-registry.io/library/single-arch-arm64-image:{{ .tag }}"},
+					// The image of the variant does not support the architecture: the variant is discarded.
+					{Architecture: utils.ArchitectureS390x, Image: "my-registry.io/library/single-arch-amd64-image:{{ .tag }}"},
+					// The image of the variant cannot be inspected: the variant is discarded.
+					{Architecture: utils.ArchitecturePpc64le, Image: "my-registry.io/library/unknown:{{ .tag }}"},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unused"},
+			Spec: v1beta1.ImageRewriteRuleSpec{
+				ImagePattern: "^my-registry.io/library/single-arch-amd64-image:.+$",
+				Variants: []v1beta1.ImageVariant{
+					{Architecture: utils.ArchitectureAmd64, Image: "my-registry.io/library/multi-arch-image:latest"},
+				},
+			},
+		},
+	}
+	wantVariants := map[string]string{
+		utils.ArchitectureAmd64: fake.SingleArchAmd64Image,
+		utils.ArchitectureArm64: fake.SingleArchArm64Image,
+	}
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want map[string]map[string]string
+	}{
+		{
+			name: "no image matching",
+			pod:  NewPod().WithContainersImages(fake.MultiArchImage).Build(),
+			want: map[string]map[string]string{},
+		},
+		{
+			name: "images matching the first rule",
+			pod: NewPod().WithInitContainersImages(fake.SingleArchArm64Image).
+				WithContainersImages(fake.MultiArchImage, fake.SingleArchAmd64Image).Build(),
+			want: map[string]map[string]string{
+				fake.SingleArchAmd64Image: wantVariants,
+				fake.SingleArchArm64Image: wantVariants,
+			},
+		},
+		{
+			name: "containers assumed multi-arch and images with a digest",
+			pod: withContainerNames(NewPod().WithContainersImages(fake.SingleArchAmd64Image,
+				"my-registry.io/library/single-arch-arm64-image:latest@sha256:0123").
+				WithAnnotations(map[string]string{
+					utils.ContainerDecisionsAnnotation: `{"digest":"Inspected","sidecar":"AssumedMultiArch"}`,
+				}).Build(), "sidecar", "digest"),
+			want: map[string]map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imageInspectionCache = fake.FacadeSingleton()
+			defer func() {
+				imageInspectionCache = mmoimage.FacadeSingleton()
+			}()
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			g.Expect(pod.imageVariants(rules, nil)).To(Equal(tt.want))
+		})
+	}
+}
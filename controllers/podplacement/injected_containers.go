@@ -0,0 +1,71 @@
+package podplacement
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+// containerDecision returns the decision of the first rule matching the container.
+func containerDecision(container corev1.Container, rules []v1beta1.InjectedContainerRule) string {
+	for _, rule := range rules {
+		if !rule.Matches(container.Name, container.Image) {
+			continue
+		}
+		if rule.Policy == v1beta1.InjectedContainerPolicyAssumeMultiArch {
+			return utils.ContainerDecisionAssumedMultiArch
+		}
+		return utils.ContainerDecisionIncluded
+	}
+	return utils.ContainerDecisionInspected
+}
+
+// setContainerDecisions records in the utils.ContainerDecisionsAnnotation annotation the decision taken for each
+// container of the pod according to the injected container rules. The annotation is removed when no rule is
+// configured, so that it cannot be used to skip the inspection of the images.
+func (pod *Pod) setContainerDecisions(cppc *v1beta1.ClusterPodPlacementConfig) {
+	if cppc == nil || len(cppc.Spec.InjectedContainerRules) == 0 {
+		delete(pod.Annotations, utils.ContainerDecisionsAnnotation)
+		return
+	}
+	decisions := map[string]string{}
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		decisions[container.Name] = containerDecision(container, cppc.Spec.InjectedContainerRules)
+	}
+	// json.Marshal sorts the keys of the map: the annotation is stable across invocations.
+	value, err := json.Marshal(decisions)
+	if err != nil {
+		ctrllog.FromContext(pod.Ctx()).Error(err, "Unable to marshal the container decisions")
+		return
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[utils.ContainerDecisionsAnnotation] = string(value)
+}
+
+// assumedMultiArchContainers returns the names of the containers whose images are not inspected, as recorded in
+// the utils.ContainerDecisionsAnnotation annotation.
+func (pod *Pod) assumedMultiArchContainers() sets.Set[string] {
+	assumed := sets.New[string]()
+	value, ok := pod.Annotations[utils.ContainerDecisionsAnnotation]
+	if !ok {
+		return assumed
+	}
+	decisions := map[string]string{}
+	if err := json.Unmarshal([]byte(value), &decisions); err != nil {
+		ctrllog.FromContext(pod.Ctx()).Error(err, "Unable to unmarshal the container decisions, all the containers will be inspected")
+		return assumed
+	}
+	for name, decision := range decisions {
+		if decision == utils.ContainerDecisionAssumedMultiArch {
+			assumed.Insert(name)
+		}
+	}
+	return assumed
+}
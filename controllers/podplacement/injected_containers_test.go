@@ -0,0 +1,81 @@
+package podplacement
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+func TestPod_setContainerDecisions(t *testing.T) {
+	rules := []v1beta1.InjectedContainerRule{
+		{ContainerName: "istio-proxy", ImagePrefix: "docker.io/istio/", Policy: v1beta1.InjectedContainerPolicyInclude},
+		{ImagePrefix: "docker.io/istio/", Policy: v1beta1.InjectedContainerPolicyAssumeMultiArch},
+	}
+	tests := []struct {
+		name            string
+		pod             *v1.Pod
+		cppc            *v1beta1.ClusterPodPlacementConfig
+		wantAnnotations map[string]string
+		wantImages      sets.Set[containerImage]
+	}{
+		{
+			name: "no rules",
+			pod: NewPod().WithContainersImages("docker.io/istio/proxyv2:1.20").WithAnnotations(map[string]string{
+				utils.ContainerDecisionsAnnotation: `{"app":"AssumedMultiArch"}`,
+			}).Build(),
+			cppc:            NewClusterPodPlacementConfig().Build(),
+			wantAnnotations: map[string]string{},
+			wantImages:      sets.New(containerImage{imageName: "//docker.io/istio/proxyv2:1.20"}),
+		},
+		{
+			name: "first matching rule applies",
+			pod: withContainerNames(NewPod().WithInitContainersImages("docker.io/istio/proxyv2:1.20").
+				WithContainersImages("quay.io/app:latest", "docker.io/istio/proxyv2:1.20").Build(),
+				"istio-init", "app", "istio-proxy"),
+			cppc: NewClusterPodPlacementConfig().WithInjectedContainerRules(rules...).Build(),
+			wantAnnotations: map[string]string{
+				utils.ContainerDecisionsAnnotation: `{"app":"Inspected","istio-init":"AssumedMultiArch","istio-proxy":"Included"}`,
+			},
+			wantImages: sets.New(containerImage{imageName: "//quay.io/app:latest"},
+				containerImage{imageName: "//docker.io/istio/proxyv2:1.20"}),
+		},
+		{
+			name: "all containers assumed multi-arch",
+			pod: withContainerNames(NewPod().WithContainersImages("docker.io/istio/proxyv2:1.20").Build(),
+				"sidecar"),
+			cppc: NewClusterPodPlacementConfig().WithInjectedContainerRules(rules...).Build(),
+			wantAnnotations: map[string]string{
+				utils.ContainerDecisionsAnnotation: `{"sidecar":"AssumedMultiArch"}`,
+			},
+			wantImages: sets.New[containerImage](),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			pod.setContainerDecisions(tt.cppc)
+			g.Expect(pod.Annotations).To(Equal(tt.wantAnnotations))
+			g.Expect(pod.imagesNamesSet()).To(Equal(tt.wantImages))
+		})
+	}
+}
+
+// withContainerNames sets the names of the init containers and of the containers of the pod, in this order.
+func withContainerNames(pod *v1.Pod, names ...string) *v1.Pod {
+	for i := range pod.Spec.InitContainers {
+		pod.Spec.InitContainers[i].Name, names = names[0], names[1:]
+	}
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].Name, names = names[0], names[1:]
+	}
+	return pod
+}
@@ -11,12 +11,17 @@ import (
 )
 
 var (
-	TimeToProcessPod        prometheus.Histogram
-	TimeToProcessGatedPod   prometheus.Histogram
-	TimeToInspectImage      prometheus.Histogram
-	TimeToInspectPodImages  prometheus.Histogram
-	ProcessedPodsCtrl       prometheus.Counter
-	FailedInspectionCounter prometheus.Counter
+	TimeToProcessPod         prometheus.Histogram
+	TimeToProcessGatedPod    prometheus.Histogram
+	TimeToInspectImage       prometheus.Histogram
+	TimeToInspectPodImages   prometheus.Histogram
+	ProcessedPodsCtrl        prometheus.Counter
+	FailedInspectionCounter  prometheus.Counter
+	GatedPodsGauge           prometheus.Gauge
+	StuckGatedPodsCounter    prometheus.Counter
+	ForcedUngatedPodsCounter prometheus.Counter
+	// ArchitectureSelectionConflicts counts the pods whose architecture selection is not supported by their images.
+	ArchitectureSelectionConflicts prometheus.Counter
 )
 
 var onceController sync.Once
@@ -26,7 +31,6 @@ func InitPodPlacementControllerMetrics() {
 }
 
 func initPodPlacementControllerMetrics() {
-	initCommonMetrics()
 	TimeToProcessPod = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "mto_ppo_ctrl_time_to_process_pod_seconds",
@@ -67,6 +71,31 @@ func initPodPlacementControllerMetrics() {
 			Help: "The total number of image inspections that failed",
 		},
 	)
+	GatedPodsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "mto_ppo_pods_gated",
+			Help: "The current number of pods gated by the pod placement operand, as observed by the gated pods watchdog",
+		},
+	)
+	StuckGatedPodsCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "mto_ppo_ctrl_stuck_gated_pods_total",
+			Help: "The total number of pods found gated beyond the scheduling gate deadline",
+		},
+	)
+	ForcedUngatedPodsCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "mto_ppo_ctrl_forced_ungated_pods_total",
+			Help: "The total number of pods whose scheduling gate was removed because the scheduling gate deadline was exceeded",
+		},
+	)
+	ArchitectureSelectionConflicts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "mto_ppo_ctrl_arch_selection_conflicts_total",
+			Help: "The total number of pods whose nodeSelector or nodeAffinity select architectures not supported by their images",
+		},
+	)
 	metrics2.Registry.MustRegister(TimeToProcessPod, TimeToProcessGatedPod, TimeToInspectImage,
-		TimeToInspectPodImages, ProcessedPodsCtrl, FailedInspectionCounter)
+		TimeToInspectPodImages, ProcessedPodsCtrl, FailedInspectionCounter, GatedPodsGauge, StuckGatedPodsCounter,
+		ForcedUngatedPodsCounter, ArchitectureSelectionConflicts)
 }
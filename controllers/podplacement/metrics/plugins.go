@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	metrics2 "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	PluginResultSuccess = "success"
+	PluginResultError   = "error"
+)
+
+var (
+	PluginHookInvocations *prometheus.CounterVec
+	PluginHookDuration    *prometheus.HistogramVec
+)
+
+var oncePlugins sync.Once
+
+func InitPluginMetrics() {
+	oncePlugins.Do(initPluginMetrics)
+}
+
+func initPluginMetrics() {
+	PluginHookInvocations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mto_ppo_plugin_hook_invocations_total",
+			Help: "The total number of invocations of the hooks of the placement plugins",
+		},
+		[]string{"plugin", "hook", "result"},
+	)
+	PluginHookDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "mto_ppo_plugin_hook_duration_seconds",
+			Help:    "The time taken by the hooks of the placement plugins",
+			Buckets: utils.Buckets(),
+		},
+		[]string{"plugin", "hook"},
+	)
+	metrics2.Registry.MustRegister(PluginHookInvocations, PluginHookDuration)
+}
@@ -10,10 +10,23 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+const (
+	// ResponseTimeMetricName is the name of the histogram of the response time of the webhook.
+	ResponseTimeMetricName = "mto_ppo_wh_response_time_seconds"
+	// AverageResponseTimeMetricName is the name of the average response time of the webhook pods, derived from the
+	// ResponseTimeMetricName histogram by the Prometheus Adapter rule in config/prometheus/adapter/rules.yaml.
+	// The HorizontalPodAutoscaler of the webhook scales on it, as a histogram cannot be a target of the autoscaler.
+	AverageResponseTimeMetricName = ResponseTimeMetricName + "_average"
+)
+
 var (
 	ProcessedPodsWH prometheus.Counter
 	GatedPods       prometheus.Counter
 	ResponseTime    prometheus.Histogram
+
+	PodsNotGatedControllerUnavailable prometheus.Counter
+	PodsEmulated                      prometheus.Counter
+	PodsRejectedByPolicy              *prometheus.CounterVec
 )
 
 var onceWebhook sync.Once
@@ -23,7 +36,6 @@ func InitWebhookMetrics() {
 }
 
 func initWebhookMetrics() {
-	initCommonMetrics()
 	ProcessedPodsWH = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Name: "mto_ppo_wh_pods_processed_total",
@@ -36,13 +48,33 @@ func initWebhookMetrics() {
 			Help: "The total number of pods gated by the webhook",
 		},
 	)
+	PodsNotGatedControllerUnavailable = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "mto_ppo_wh_pods_not_gated_controller_unavailable_total",
+			Help: "The total number of pods the webhook did not gate because the pod placement controller was unavailable",
+		},
+	)
+	PodsEmulated = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "mto_ppo_wh_pods_emulated_total",
+			Help: "The total number of pods the webhook sent to the nodes able to emulate the architectures of their images",
+		},
+	)
+	PodsRejectedByPolicy = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mto_ppo_wh_pods_rejected_total",
+			Help: "The total number of pods rejected by the validating webhook, by policy",
+		},
+		[]string{"policy"},
+	)
 
 	ResponseTime = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
-			Name:    "mto_ppo_wh_response_time_seconds",
+			Name:    ResponseTimeMetricName,
 			Help:    "The response time of the webhook",
 			Buckets: utils.Buckets(),
 		},
 	)
-	metrics2.Registry.MustRegister(ProcessedPodsWH, GatedPods, ResponseTime)
+	metrics2.Registry.MustRegister(ProcessedPodsWH, GatedPods, PodsNotGatedControllerUnavailable, PodsEmulated,
+		PodsRejectedByPolicy, ResponseTime)
 }
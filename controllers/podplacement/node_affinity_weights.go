@@ -0,0 +1,292 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podplacement
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+const (
+	nodeAffinityWeightsInterval = 5 * time.Minute
+	// scheduledPodsPageSize is the number of scheduled pods listed per request, to bound the memory and the load on
+	// the API server in large clusters.
+	scheduledPodsPageSize = 500
+	minNodeAffinityWeight = 1
+	maxNodeAffinityWeight = 100
+)
+
+// NodeAffinityWeightsComputer periodically computes the weights of the architectures for the NodeAffinityScoring
+// plugin in the Dynamic mode, and reports them in the status of the ClusterPodPlacementConfig.
+// It runs only in the leader replica of the pod placement controller.
+type NodeAffinityWeightsComputer struct {
+	client client.Client
+	// apiReader lists the scheduled pods, as the cache of the manager only holds the pending ones.
+	apiReader client.Reader
+	interval  time.Duration
+	log       logr.Logger
+}
+
+func NewNodeAffinityWeightsComputer(client client.Client, apiReader client.Reader) *NodeAffinityWeightsComputer {
+	return &NodeAffinityWeightsComputer{
+		client:    client,
+		apiReader: apiReader,
+		interval:  nodeAffinityWeightsInterval,
+	}
+}
+
+func (c *NodeAffinityWeightsComputer) Start(ctx context.Context) error {
+	c.log = log.FromContext(ctx, "handler", "NodeAffinityWeightsComputer")
+	c.log.Info("Starting the node affinity weights computer", "interval", c.interval)
+	wait.UntilWithContext(ctx, c.update, c.interval)
+	c.log.Info("Stopping the node affinity weights computer")
+	return nil
+}
+
+// update computes the weights of the architectures and patches the status of the ClusterPodPlacementConfig.
+// The computed weights are removed from the status when the Dynamic mode is not enabled.
+func (c *NodeAffinityWeightsComputer) update(ctx context.Context) {
+	cppc := &v1beta1.ClusterPodPlacementConfig{}
+	if err := c.client.Get(ctx, client.ObjectKey{Name: common.SingletonResourceObjectName}, cppc); err != nil {
+		c.log.Error(err, "Unable to get the ClusterPodPlacementConfig")
+		return
+	}
+	original := cppc.DeepCopy()
+	if !cppc.PluginsEnabled(common.NodeAffinityScoringPluginName) || !cppc.Spec.Plugins.NodeAffinityScoring.IsDynamic() {
+		if cppc.Status.NodeAffinityScoring == nil {
+			return
+		}
+		cppc.Status.NodeAffinityScoring = nil
+	} else {
+		nodeAffinityScoring := cppc.Spec.Plugins.NodeAffinityScoring
+		capacities, err := c.architectureCapacities(ctx, nodeAffinityScoring.CostLabel)
+		if err != nil {
+			c.log.Error(err, "Unable to compute the free capacity of the architectures")
+			return
+		}
+		architectures := make([]string, 0, len(nodeAffinityScoring.Platforms))
+		for _, term := range nodeAffinityScoring.Platforms {
+			architectures = append(architectures, term.Architecture)
+		}
+		platforms := dynamicNodeAffinityWeights(architectures, capacities)
+		if cppc.Status.NodeAffinityScoring != nil &&
+			equality.Semantic.DeepEqual(cppc.Status.NodeAffinityScoring.Platforms, platforms) {
+			return
+		}
+		c.log.Info("Updating the node affinity weights", "platforms", platforms)
+		cppc.Status.NodeAffinityScoring = &v1beta1.NodeAffinityScoringStatus{
+			Platforms:      platforms,
+			LastUpdateTime: metav1.Now(),
+		}
+	}
+	// The optimistic lock prevents overriding the status concurrently updated by the operator.
+	if err := c.client.Status().Patch(ctx, cppc,
+		client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		c.log.Error(err, "Unable to patch the ClusterPodPlacementConfig status")
+	}
+}
+
+// architectureCapacity is the free capacity of the schedulable nodes of an architecture and their average cost.
+type architectureCapacity struct {
+	freeCPU    int64 // millicores
+	freeMemory int64 // bytes
+	totalCost  float64
+	costNodes  int
+}
+
+// averageCost returns the average cost of the nodes with cost information, or false if none has it.
+func (a *architectureCapacity) averageCost() (float64, bool) {
+	if a.costNodes == 0 {
+		return 0, false
+	}
+	return a.totalCost / float64(a.costNodes), true
+}
+
+// architectureCapacities returns the free capacity of the schedulable nodes, by architecture.
+func (c *NodeAffinityWeightsComputer) architectureCapacities(ctx context.Context,
+	costLabel string) (map[string]*architectureCapacity, error) {
+	nodes := &corev1.NodeList{}
+	if err := c.client.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	requested := map[string]corev1.ResourceList{}
+	selector := client.MatchingFieldsSelector{Selector: fields.AndSelectors(
+		fields.OneTermNotEqualSelector("spec.nodeName", ""),
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodSucceeded)),
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodFailed)),
+	)}
+	continueToken := ""
+	for {
+		pods := &corev1.PodList{}
+		if err := c.apiReader.List(ctx, pods, selector, client.Limit(scheduledPodsPageSize),
+			client.Continue(continueToken)); err != nil {
+			return nil, err
+		}
+		addPodRequests(requested, pods.Items)
+		if continueToken = pods.Continue; continueToken == "" {
+			break
+		}
+	}
+	return freeCapacityByArchitecture(nodes.Items, requested, costLabel), nil
+}
+
+// addPodRequests adds the CPU and memory requested by the pods to the requests of the nodes they run on.
+func addPodRequests(requested map[string]corev1.ResourceList, pods []corev1.Pod) {
+	for i := range pods {
+		nodeRequests, ok := requested[pods[i].Spec.NodeName]
+		if !ok {
+			nodeRequests = corev1.ResourceList{}
+			requested[pods[i].Spec.NodeName] = nodeRequests
+		}
+		for name, quantity := range podRequests(&pods[i]) {
+			total := nodeRequests[name]
+			total.Add(quantity)
+			nodeRequests[name] = total
+		}
+	}
+}
+
+// freeCapacityByArchitecture sums, by architecture, the CPU and memory allocatable and not requested on the
+// schedulable nodes, and the cost of the nodes read from the costLabel label or annotation.
+func freeCapacityByArchitecture(nodes []corev1.Node, requested map[string]corev1.ResourceList,
+	costLabel string) map[string]*architectureCapacity {
+	capacities := map[string]*architectureCapacity{}
+	for i := range nodes {
+		node := &nodes[i]
+		arch, ok := node.Labels[utils.ArchLabel]
+		if !ok || node.Spec.Unschedulable {
+			continue
+		}
+		capacity, ok := capacities[arch]
+		if !ok {
+			capacity = &architectureCapacity{}
+			capacities[arch] = capacity
+		}
+		allocatableCPU, allocatableMemory := node.Status.Allocatable[corev1.ResourceCPU], node.Status.Allocatable[corev1.ResourceMemory]
+		requestedCPU, requestedMemory := requested[node.Name][corev1.ResourceCPU], requested[node.Name][corev1.ResourceMemory]
+		capacity.freeCPU += max(allocatableCPU.MilliValue()-requestedCPU.MilliValue(), 0)
+		capacity.freeMemory += max(allocatableMemory.Value()-requestedMemory.Value(), 0)
+		if cost, ok := nodeCost(node, costLabel); ok {
+			capacity.totalCost += cost
+			capacity.costNodes++
+		}
+	}
+	return capacities
+}
+
+// nodeCost returns the positive cost of the node, read from the costLabel label or annotation.
+func nodeCost(node *corev1.Node, costLabel string) (float64, bool) {
+	if costLabel == "" {
+		return 0, false
+	}
+	value, ok := node.Labels[costLabel]
+	if !ok {
+		value, ok = node.Annotations[costLabel]
+	}
+	if !ok {
+		return 0, false
+	}
+	cost, err := strconv.ParseFloat(value, 64)
+	if err != nil || cost <= 0 {
+		return 0, false
+	}
+	return cost, true
+}
+
+// podRequests returns the CPU and memory requested by the pod: the maximum between the sum of the requests of the
+// containers and the requests of each init container, plus the pod overhead.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		total := resource.Quantity{}
+		for i := range pod.Spec.Containers {
+			total.Add(pod.Spec.Containers[i].Resources.Requests[name])
+		}
+		for i := range pod.Spec.InitContainers {
+			if request := pod.Spec.InitContainers[i].Resources.Requests[name]; request.Cmp(total) > 0 {
+				total = request.DeepCopy()
+			}
+		}
+		total.Add(pod.Spec.Overhead[name])
+		requests[name] = total
+	}
+	return requests
+}
+
+// dynamicNodeAffinityWeights computes the weights of the given architectures.
+// The score of an architecture is the average of its shares of the free CPU and of the free memory, multiplied by the
+// ratio between the lowest average cost among the architectures and its own average cost. The weights are the
+// scores scaled so that the highest one is 100, and clamped to the 1-100 range.
+func dynamicNodeAffinityWeights(architectures []string,
+	capacities map[string]*architectureCapacity) []plugins.NodeAffinityScoringPlatformTerm {
+	var totalCPU, totalMemory int64
+	minCost := math.Inf(1)
+	for _, architecture := range architectures {
+		capacity, ok := capacities[architecture]
+		if !ok {
+			continue
+		}
+		totalCPU += capacity.freeCPU
+		totalMemory += capacity.freeMemory
+		if cost, ok := capacity.averageCost(); ok {
+			minCost = math.Min(minCost, cost)
+		}
+	}
+	scores := make([]float64, len(architectures))
+	maxScore := 0.0
+	for i, architecture := range architectures {
+		capacity, ok := capacities[architecture]
+		if !ok || totalCPU == 0 || totalMemory == 0 {
+			continue
+		}
+		scores[i] = (float64(capacity.freeCPU)/float64(totalCPU) + float64(capacity.freeMemory)/float64(totalMemory)) / 2
+		if cost, ok := capacity.averageCost(); ok {
+			scores[i] *= minCost / cost
+		}
+		maxScore = math.Max(maxScore, scores[i])
+	}
+	platforms := make([]plugins.NodeAffinityScoringPlatformTerm, 0, len(architectures))
+	for i, architecture := range architectures {
+		weight := int32(minNodeAffinityWeight)
+		if maxScore > 0 {
+			weight = int32(math.Round(maxNodeAffinityWeight * scores[i] / maxScore))
+		}
+		platforms = append(platforms, plugins.NodeAffinityScoringPlatformTerm{
+			Architecture: architecture,
+			Weight:       min(max(weight, minNodeAffinityWeight), maxNodeAffinityWeight),
+		})
+	}
+	return platforms
+}
@@ -0,0 +1,139 @@
+package podplacement
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+func Test_freeCapacityByArchitecture(t *testing.T) {
+	g := NewGomegaWithT(t)
+	allocatable := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("8Gi"),
+	}
+	nodes := []v1.Node{
+		*NewNodeBuilder().WithName("amd64-1").WithLabel(utils.ArchLabel, utils.ArchitectureAmd64).
+			WithLabel("price", "0.2").WithAllocatable(allocatable).Build(),
+		*NewNodeBuilder().WithName("arm64-1").WithLabel(utils.ArchLabel, utils.ArchitectureArm64).
+			WithAnnotation("price", "0.1").WithAllocatable(allocatable).Build(),
+		*NewNodeBuilder().WithName("arm64-2").WithLabel(utils.ArchLabel, utils.ArchitectureArm64).
+			WithUnschedulable().WithAllocatable(allocatable).Build(),
+	}
+	pod := NewPod().Build()
+	pod.Spec.NodeName = "amd64-1"
+	pod.Spec.Containers = []v1.Container{
+		{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1"),
+			v1.ResourceMemory: resource.MustParse("2Gi"),
+		}}},
+		{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU: resource.MustParse("500m"),
+		}}},
+	}
+	pod.Spec.InitContainers = []v1.Container{
+		{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceMemory: resource.MustParse("4Gi"),
+		}}},
+	}
+
+	otherPod := NewPod().Build()
+	otherPod.Spec.NodeName = "arm64-1"
+	otherPod.Spec.Containers = []v1.Container{
+		{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU: resource.MustParse("1"),
+		}}},
+	}
+
+	// The scheduled pods are listed by pages: their requests are accumulated across the pages.
+	requested := map[string]v1.ResourceList{}
+	addPodRequests(requested, []v1.Pod{*pod})
+	addPodRequests(requested, []v1.Pod{*otherPod})
+	capacities := freeCapacityByArchitecture(nodes, requested, "price")
+	g.Expect(capacities).To(HaveLen(2))
+	g.Expect(*capacities[utils.ArchitectureAmd64]).To(Equal(architectureCapacity{
+		freeCPU: 2500, freeMemory: 4 << 30, totalCost: 0.2, costNodes: 1,
+	}))
+	g.Expect(*capacities[utils.ArchitectureArm64]).To(Equal(architectureCapacity{
+		freeCPU: 3000, freeMemory: 8 << 30, totalCost: 0.1, costNodes: 1,
+	}), "the unschedulable nodes should be ignored and the cost should be read from the annotations too")
+}
+
+func Test_dynamicNodeAffinityWeights(t *testing.T) {
+	tests := []struct {
+		name          string
+		architectures []string
+		capacities    map[string]*architectureCapacity
+		want          []plugins.NodeAffinityScoringPlatformTerm
+	}{
+		{
+			name:          "weights proportional to the free capacity",
+			architectures: []string{utils.ArchitectureAmd64, utils.ArchitectureArm64},
+			capacities: map[string]*architectureCapacity{
+				utils.ArchitectureAmd64: {freeCPU: 1000, freeMemory: 1 << 30},
+				utils.ArchitectureArm64: {freeCPU: 4000, freeMemory: 4 << 30},
+			},
+			want: []plugins.NodeAffinityScoringPlatformTerm{
+				{Architecture: utils.ArchitectureAmd64, Weight: 25},
+				{Architecture: utils.ArchitectureArm64, Weight: 100},
+			},
+		},
+		{
+			name:          "cheaper architecture preferred at equal capacity",
+			architectures: []string{utils.ArchitectureAmd64, utils.ArchitectureArm64},
+			capacities: map[string]*architectureCapacity{
+				utils.ArchitectureAmd64: {freeCPU: 1000, freeMemory: 1 << 30, totalCost: 0.4, costNodes: 2},
+				utils.ArchitectureArm64: {freeCPU: 1000, freeMemory: 1 << 30, totalCost: 0.1, costNodes: 1},
+			},
+			want: []plugins.NodeAffinityScoringPlatformTerm{
+				{Architecture: utils.ArchitectureAmd64, Weight: 50},
+				{Architecture: utils.ArchitectureArm64, Weight: 100},
+			},
+		},
+		{
+			name:          "cheaper architecture without free capacity",
+			architectures: []string{utils.ArchitectureAmd64, utils.ArchitectureArm64},
+			capacities: map[string]*architectureCapacity{
+				utils.ArchitectureAmd64: {freeCPU: 1000, freeMemory: 1 << 30, totalCost: 0.4, costNodes: 1},
+				utils.ArchitectureArm64: {totalCost: 0.1, costNodes: 1},
+			},
+			want: []plugins.NodeAffinityScoringPlatformTerm{
+				{Architecture: utils.ArchitectureAmd64, Weight: 100},
+				{Architecture: utils.ArchitectureArm64, Weight: 1},
+			},
+		},
+		{
+			name:          "architecture without nodes",
+			architectures: []string{utils.ArchitectureAmd64, utils.ArchitectureS390x},
+			capacities: map[string]*architectureCapacity{
+				utils.ArchitectureAmd64: {freeCPU: 1000, freeMemory: 1 << 30},
+			},
+			want: []plugins.NodeAffinityScoringPlatformTerm{
+				{Architecture: utils.ArchitectureAmd64, Weight: 100},
+				{Architecture: utils.ArchitectureS390x, Weight: 1},
+			},
+		},
+		{
+			name:          "no free capacity",
+			architectures: []string{utils.ArchitectureAmd64},
+			capacities:    map[string]*architectureCapacity{},
+			want: []plugins.NodeAffinityScoringPlatformTerm{
+				{Architecture: utils.ArchitectureAmd64, Weight: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(dynamicNodeAffinityWeights(tt.architectures, tt.capacities)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podplacement
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+// nodeArchitectures returns the set of the architectures of the nodes in the cluster, as observed by the
+// informer-backed cache of the manager. When eligibleOnly is true, only the nodes the pod can be scheduled on,
+// regardless of their architecture, are considered. The tolerations the ArchitectureTolerations plugin adds to
+// the pods running on an architecture, if the plugin is enabled, are taken into account for the nodes of that
+// architecture.
+func (r *PodReconciler) nodeArchitectures(ctx context.Context, pod *Pod, eligibleOnly bool,
+	cppc *v1beta1.ClusterPodPlacementConfig) (sets.Set[string], error) {
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	var architectureTolerations *plugins.ArchitectureTolerations
+	if cppc != nil && cppc.PluginsEnabled(common.ArchitectureTolerationsPluginName) {
+		architectureTolerations = cppc.Spec.Plugins.ArchitectureTolerations
+	}
+	architectures := sets.New[string]()
+	for i := range nodes.Items {
+		arch, ok := nodes.Items[i].Labels[utils.ArchLabel]
+		if !ok {
+			continue
+		}
+		if eligibleOnly {
+			var tolerations []corev1.Toleration
+			if architectureTolerations != nil {
+				tolerations = architectureTolerations.TolerationsFor([]string{arch})
+			}
+			if !pod.canBeScheduledOn(&nodes.Items[i], tolerations...) {
+				continue
+			}
+		}
+		architectures.Insert(arch)
+	}
+	return architectures, nil
+}
+
+// canBeScheduledOn returns true if the node is schedulable, its NoSchedule and NoExecute taints are tolerated by the
+// pod or by the additional tolerations, and it matches the nodeSelector and the required node affinity of the pod.
+func (pod *Pod) canBeScheduledOn(node *corev1.Node, additionalTolerations ...corev1.Toleration) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for i := range node.Spec.Taints {
+		if node.Spec.Taints[i].Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !pod.toleratesTaint(&node.Spec.Taints[i]) && !toleratesTaint(additionalTolerations, &node.Spec.Taints[i]) {
+			return false
+		}
+	}
+	if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// The nodeSelectorTerms are ORed
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if nodeSelectorTermMatches(term, node) {
+			return true
+		}
+	}
+	return false
+}
+
+func (pod *Pod) toleratesTaint(taint *corev1.Taint) bool {
+	return toleratesTaint(pod.Spec.Tolerations, taint)
+}
+
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeSelectorTermMatches returns true if the node matches all the matchExpressions and matchFields of the term.
+// An empty term matches no node, as in the scheduler.
+func nodeSelectorTermMatches(term corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, expr := range term.MatchExpressions {
+		if !nodeSelectorRequirementMatches(expr, labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	for _, field := range term.MatchFields {
+		// metadata.name is the only field supported by the scheduler
+		if field.Key != "metadata.name" ||
+			!nodeSelectorRequirementMatches(field, labels.Set{field.Key: node.Name}) {
+			return false
+		}
+	}
+	return true
+}
+
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+func nodeSelectorRequirementMatches(requirement corev1.NodeSelectorRequirement, set labels.Set) bool {
+	op, ok := nodeSelectorOperators[requirement.Operator]
+	if !ok {
+		return false
+	}
+	r, err := labels.NewRequirement(requirement.Key, op, requirement.Values)
+	if err != nil {
+		return false
+	}
+	return r.Matches(set)
+}
+
+// nodeChangedPredicate filters the node events that can make a new architecture available to the pods waiting
+// for an eligible node.
+var nodeChangedPredicate = predicate.Funcs{
+	CreateFunc: func(_ event.CreateEvent) bool {
+		return true
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, okOld := e.ObjectOld.(*corev1.Node)
+		newNode, okNew := e.ObjectNew.(*corev1.Node)
+		if !okOld || !okNew {
+			return false
+		}
+		return oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable ||
+			!equality.Semantic.DeepEqual(oldNode.Spec.Taints, newNode.Spec.Taints) ||
+			!labels.Equals(oldNode.Labels, newNode.Labels)
+	},
+	DeleteFunc: func(_ event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(_ event.GenericEvent) bool {
+		return false
+	},
+}
+
+// podsWaitingForEligibleNode maps a node event to the pods kept gated until a node with a supported architecture
+// joins the cluster.
+func (r *PodReconciler) podsWaitingForEligibleNode(ctx context.Context, _ client.Object) []reconcile.Request {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.MatchingLabels{
+		utils.NoEligibleNodeArchLabel: utils.NoEligibleNodeArchLabelWaiting,
+	}); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "Unable to list the pods waiting for an eligible node")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(podList.Items))
+	for i := range podList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&podList.Items[i])})
+	}
+	return requests
+}
@@ -0,0 +1,118 @@
+package podplacement
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+func TestPod_canBeScheduledOn(t *testing.T) {
+	noScheduleTaint := v1.Taint{Key: "dedicated", Value: "infra", Effect: v1.TaintEffectNoSchedule}
+	tests := []struct {
+		name        string
+		pod         *v1.Pod
+		node        *v1.Node
+		tolerations []v1.Toleration
+		want        bool
+	}{
+		{
+			name: "schedulable node without taints",
+			pod:  NewPod().Build(),
+			node: NewNodeBuilder().WithLabel(utils.ArchLabel, utils.ArchitectureArm64).Build(),
+			want: true,
+		},
+		{
+			name: "unschedulable node",
+			pod:  NewPod().Build(),
+			node: NewNodeBuilder().WithLabel(utils.ArchLabel, utils.ArchitectureArm64).WithUnschedulable().Build(),
+			want: false,
+		},
+		{
+			name: "node with a NoSchedule taint not tolerated by the pod",
+			pod:  NewPod().Build(),
+			node: NewNodeBuilder().WithLabel(utils.ArchLabel, utils.ArchitectureArm64).WithTaint(noScheduleTaint).Build(),
+			want: false,
+		},
+		{
+			name: "node with a NoSchedule taint tolerated by the pod",
+			pod: NewPod().WithTolerations(v1.Toleration{
+				Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "infra", Effect: v1.TaintEffectNoSchedule,
+			}).Build(),
+			node: NewNodeBuilder().WithLabel(utils.ArchLabel, utils.ArchitectureArm64).WithTaint(noScheduleTaint).Build(),
+			want: true,
+		},
+		{
+			name: "node with a NoSchedule taint tolerated by the architecture tolerations",
+			pod:  NewPod().Build(),
+			node: NewNodeBuilder().WithLabel(utils.ArchLabel, utils.ArchitectureArm64).WithTaint(noScheduleTaint).Build(),
+			tolerations: []v1.Toleration{{
+				Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "infra", Effect: v1.TaintEffectNoSchedule,
+			}},
+			want: true,
+		},
+		{
+			name: "node with a NoSchedule taint not tolerated by the architecture tolerations",
+			pod:  NewPod().Build(),
+			node: NewNodeBuilder().WithLabel(utils.ArchLabel, utils.ArchitectureArm64).WithTaint(noScheduleTaint).Build(),
+			tolerations: []v1.Toleration{{
+				Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "arm64", Effect: v1.TaintEffectNoSchedule,
+			}},
+			want: false,
+		},
+		{
+			name: "node with a PreferNoSchedule taint",
+			pod:  NewPod().Build(),
+			node: NewNodeBuilder().WithLabel(utils.ArchLabel, utils.ArchitectureArm64).WithTaint(v1.Taint{
+				Key: "dedicated", Value: "infra", Effect: v1.TaintEffectPreferNoSchedule,
+			}).Build(),
+			want: true,
+		},
+		{
+			name: "node not matching the node selector of the pod",
+			pod:  NewPod().WithNodeSelectors("node-role.kubernetes.io/worker", "").Build(),
+			node: NewNodeBuilder().WithLabel(utils.ArchLabel, utils.ArchitectureArm64).Build(),
+			want: false,
+		},
+		{
+			name: "node matching the node selector of the pod",
+			pod:  NewPod().WithNodeSelectors("node-role.kubernetes.io/worker", "").Build(),
+			node: NewNodeBuilder().WithLabel(utils.ArchLabel, utils.ArchitectureArm64).
+				WithLabel("node-role.kubernetes.io/worker", "").Build(),
+			want: true,
+		},
+		{
+			name: "node matching one of the node selector terms of the pod",
+			pod: NewPod().WithNodeSelectorTermsMatchExpressions(
+				[]v1.NodeSelectorRequirement{
+					*NewNodeSelectorRequirement().WithKeyAndValues("zone", v1.NodeSelectorOpIn, "a").Build(),
+				},
+				[]v1.NodeSelectorRequirement{
+					*NewNodeSelectorRequirement().WithKeyAndValues("zone", v1.NodeSelectorOpIn, "b").Build(),
+				}).Build(),
+			node: NewNodeBuilder().WithLabel(utils.ArchLabel, utils.ArchitectureArm64).WithLabel("zone", "b").Build(),
+			want: true,
+		},
+		{
+			name: "node matching none of the node selector terms of the pod",
+			pod: NewPod().WithNodeSelectorTermsMatchExpressions(
+				[]v1.NodeSelectorRequirement{
+					*NewNodeSelectorRequirement().WithKeyAndValues("zone", v1.NodeSelectorOpIn, "a").Build(),
+				}).Build(),
+			node: NewNodeBuilder().WithLabel(utils.ArchLabel, utils.ArchitectureArm64).WithLabel("zone", "b").Build(),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			g.Expect(pod.canBeScheduledOn(tt.node, tt.tolerations...)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podplacement
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+)
+
+// nodeAffinityScoringPlugin sets the architecture preferences of the NodeAffinityScoring plugin in the preferred node
+// affinity of the pods.
+type nodeAffinityScoringPlugin struct{}
+
+func (p *nodeAffinityScoringPlugin) ID() common.Plugin {
+	return common.NodeAffinityScoringPluginName
+}
+
+func (p *nodeAffinityScoringPlugin) Name() string {
+	return plugins.NodeAffinityScoringPluginName
+}
+
+// OnAdmit labels the pod to track whether the preferred node affinity is set by the controller.
+func (p *nodeAffinityScoringPlugin) OnAdmit(pod *Pod, _ *v1beta1.ClusterPodPlacementConfig) error {
+	pod.EnsureLabel(utils.PreferredNodeAffinityLabel, utils.LabelValueNotSet)
+	return nil
+}
+
+// OnUngate sets the architecture preferences in the preferred node affinity of the pod.
+func (p *nodeAffinityScoringPlugin) OnUngate(pod *Pod, cppc *v1beta1.ClusterPodPlacementConfig) error {
+	pod.SetPreferredArchNodeAffinity(cppc)
+	return nil
+}
+
+// nodeInventoryPlugin restricts the required node affinity of the pods to the architectures of the nodes available
+// in the cluster.
+type nodeInventoryPlugin struct{}
+
+func (p *nodeInventoryPlugin) ID() common.Plugin {
+	return common.NodeInventoryPluginName
+}
+
+func (p *nodeInventoryPlugin) Name() string {
+	return plugins.NodeInventoryPluginName
+}
+
+// OnConstrain sets the architectures of the nodes the pod can run on. The node inventory is best effort: when the
+// nodes cannot be listed, the pod is processed based on its images only.
+func (p *nodeInventoryPlugin) OnConstrain(ctx context.Context, reconciler *PodReconciler, pod *Pod,
+	constraints *placementConstraints, cppc *v1beta1.ClusterPodPlacementConfig) error {
+	nodeInventory := cppc.Spec.Plugins.NodeInventory
+	nodeArchitectures, err := reconciler.nodeArchitectures(ctx, pod, nodeInventory.EligibleNodesOnly, cppc)
+	if err != nil {
+		return err
+	}
+	constraints.nodeArchitectures = nodeArchitectures
+	constraints.keepGatedUntilNodeAvailable = nodeInventory.KeepGatedUntilNodeAvailable
+	return nil
+}
+
+// architectureTolerationsPlugin adds to the pods the tolerations for the taints of the nodes dedicated to the
+// architectures supported by their images.
+type architectureTolerationsPlugin struct{}
+
+func (p *architectureTolerationsPlugin) ID() common.Plugin {
+	return common.ArchitectureTolerationsPluginName
+}
+
+func (p *architectureTolerationsPlugin) Name() string {
+	return plugins.ArchitectureTolerationsPluginName
+}
+
+// OnInspected adds the tolerations for the architectures set in the required node affinity of the pod.
+func (p *architectureTolerationsPlugin) OnInspected(pod *Pod, architectures []string,
+	cppc *v1beta1.ClusterPodPlacementConfig) error {
+	pod.SetArchitectureTolerations(architectures, cppc.Spec.Plugins.ArchitectureTolerations)
+	return nil
+}
+
+// emulationFallbackPlugin sends the pods whose images have no architecture in common to the nodes able to emulate
+// the architectures of the images.
+type emulationFallbackPlugin struct{}
+
+func (p *emulationFallbackPlugin) ID() common.Plugin {
+	return common.EmulationFallbackPluginName
+}
+
+func (p *emulationFallbackPlugin) Name() string {
+	return plugins.EmulationFallbackPluginName
+}
+
+// OnPlace sets the runtimeClassName and the node affinity of the pods to run under emulation. As the
+// runtimeClassName cannot be changed after the pod creation, the pod is fully placed at admission time.
+func (p *emulationFallbackPlugin) OnPlace(ctx context.Context, webhook *PodSchedulingGateMutatingWebHook, pod *Pod,
+	cppc *v1beta1.ClusterPodPlacementConfig) (bool, error) {
+	// The pods already emulated are verified when the webhook is reinvoked: the event and the metric are not repeated.
+	reinvoked := pod.isEmulated()
+	if !webhook.emulationFallback(ctx, pod, cppc) {
+		return false, nil
+	}
+	if reinvoked {
+		return true, nil
+	}
+	webhook.delayedEvent(ctx, pod.DeepCopy(), corev1.EventTypeNormal, ArchitectureAwareEmulationFallbackSet,
+		EmulationFallbackSetMsg+pod.Annotations[utils.EmulatedArchitecturesAnnotation])
+	metrics.PodsEmulated.Inc()
+	return true, nil
+}
+
+// architectureSpreadPlugin spreads the replicas of the workloads running on more than one architecture across the
+// architectures. It is configured in the PodPlacementConfigs.
+type architectureSpreadPlugin struct{}
+
+func (p *architectureSpreadPlugin) ID() common.Plugin {
+	return common.ArchitectureSpreadPluginName
+}
+
+func (p *architectureSpreadPlugin) Name() string {
+	return plugins.ArchitectureSpreadPluginName
+}
+
+func (p *architectureSpreadPlugin) configuredLocally() {}
+
+// OnPlace adds the topologySpreadConstraint on the kubernetes.io/arch label to the pods, unless their architecture is
+// selected by the user. The pod is still gated: its node affinity is set by the controller.
+func (p *architectureSpreadPlugin) OnPlace(ctx context.Context, webhook *PodSchedulingGateMutatingWebHook, pod *Pod,
+	_ *v1beta1.ClusterPodPlacementConfig) (bool, error) {
+	if pod.isNodeSelectorConfiguredForArchitecture() || !webhook.architectureSpread(ctx, pod) {
+		return false, nil
+	}
+	ctrllog.FromContext(ctx).V(2).Info("Added the topologySpreadConstraint on the architecture to the pod")
+	webhook.delayedEvent(ctx, pod.DeepCopy(), corev1.EventTypeNormal, ArchitectureAwareTopologySpreadSet,
+		ArchitectureTopologySpreadSetMsg)
+	return false, nil
+}
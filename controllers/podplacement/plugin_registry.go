@@ -0,0 +1,266 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podplacement
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
+)
+
+const (
+	hookOnAdmit     = "OnAdmit"
+	hookOnPlace     = "OnPlace"
+	hookOnConstrain = "OnConstrain"
+	hookOnInspected = "OnInspected"
+	hookOnUngate    = "OnUngate"
+)
+
+var (
+	// placementPlugins is the registry of the plugins taking part in the lifecycle of the pods. It is defined here to
+	// facilitate testing.
+	placementPlugins = NewPluginRegistry(
+		&nodeAffinityScoringPlugin{},
+		&nodeInventoryPlugin{},
+		&architectureTolerationsPlugin{},
+		&emulationFallbackPlugin{},
+		&architectureSpreadPlugin{},
+	)
+)
+
+// PlacementPlugin is a plugin taking part in the lifecycle of the pods processed by the pod placement operand.
+// The configuration of the plugin is read from the ClusterPodPlacementConfig, and its hooks are invoked only when
+// the plugin is enabled there, unless it is a LocalPlacementPlugin. A plugin implements any of the AdmitHook, PlaceHook, ConstrainHook, InspectedHook and
+// UngateHook interfaces.
+type PlacementPlugin interface {
+	// ID identifies the plugin in the ClusterPodPlacementConfig. The hooks of the plugins are invoked in the order
+	// of their ID.
+	ID() common.Plugin
+	// Name is the name of the plugin, as reported in the logs, events and metrics.
+	Name() string
+}
+
+// LocalPlacementPlugin is implemented by the plugins configured in the PodPlacementConfigs instead of the
+// ClusterPodPlacementConfig: their hooks are invoked only when they are enabled in the PodPlacementConfig matching the
+// pod, whose plugins the hooks read with Pod.localPlugins.
+type LocalPlacementPlugin interface {
+	PlacementPlugin
+	// configuredLocally marks the plugins configured in the PodPlacementConfigs.
+	configuredLocally()
+}
+
+// AdmitHook is invoked by the webhook when a pod is admitted, before it is gated.
+type AdmitHook interface {
+	OnAdmit(pod *Pod, cppc *v1beta1.ClusterPodPlacementConfig) error
+}
+
+// PlaceHook is invoked by the webhook for the pods about to be gated, unless their architecture is selected by the
+// user. It returns true if the plugin fully placed the pod at admission time, so that the pod is not gated.
+type PlaceHook interface {
+	OnPlace(ctx context.Context, webhook *PodSchedulingGateMutatingWebHook, pod *Pod,
+		cppc *v1beta1.ClusterPodPlacementConfig) (bool, error)
+}
+
+// placementConstraints are the constraints set by the plugins on the architectures a pod can be placed on, before
+// its required node affinity is set.
+type placementConstraints struct {
+	// nodeArchitectures are the architectures of the nodes the pod can run on. It is nil when not constrained.
+	nodeArchitectures sets.Set[string]
+	// keepGatedUntilNodeAvailable keeps the pod gated when no node of nodeArchitectures supports its images.
+	keepGatedUntilNodeAvailable bool
+}
+
+// ConstrainHook is invoked by the controller before the required node affinity of a pod is set.
+type ConstrainHook interface {
+	OnConstrain(ctx context.Context, reconciler *PodReconciler, pod *Pod, constraints *placementConstraints,
+		cppc *v1beta1.ClusterPodPlacementConfig) error
+}
+
+// InspectedHook is invoked by the controller once the architectures supported by the images of a pod are set in its
+// required node affinity.
+type InspectedHook interface {
+	OnInspected(pod *Pod, architectures []string, cppc *v1beta1.ClusterPodPlacementConfig) error
+}
+
+// UngateHook is invoked by the controller before the scheduling gate is removed from a pod.
+type UngateHook interface {
+	OnUngate(pod *Pod, cppc *v1beta1.ClusterPodPlacementConfig) error
+}
+
+// PluginRegistry invokes the hooks of the registered placement plugins in a deterministic order.
+// The failure of a hook is logged, counted and published as an event on the pod, but it does not interrupt the
+// processing of the pod, nor the invocation of the hooks of the other plugins.
+type PluginRegistry struct {
+	plugins []PlacementPlugin
+}
+
+func NewPluginRegistry(plugins ...PlacementPlugin) *PluginRegistry {
+	sort.SliceStable(plugins, func(i, j int) bool {
+		return plugins[i].ID() < plugins[j].ID()
+	})
+	return &PluginRegistry{plugins: plugins}
+}
+
+// OnAdmit invokes the AdmitHook of the enabled plugins. As the pod is not persisted yet, the messages of the
+// failures are returned, for the webhook to publish them once the pod is created.
+func (r *PluginRegistry) OnAdmit(pod *Pod, cppc *v1beta1.ClusterPodPlacementConfig) []string {
+	return r.invoke(pod, cppc, hookOnAdmit, func(plugin PlacementPlugin) (bool, error) {
+		hook, ok := plugin.(AdmitHook)
+		if !ok {
+			return false, nil
+		}
+		return true, hook.OnAdmit(pod, cppc)
+	})
+}
+
+// OnPlace invokes the PlaceHook of the enabled plugins until one of them places the pod. It returns whether the pod
+// was placed and, as for OnAdmit, the messages of the failures.
+func (r *PluginRegistry) OnPlace(ctx context.Context, webhook *PodSchedulingGateMutatingWebHook, pod *Pod,
+	cppc *v1beta1.ClusterPodPlacementConfig) (placed bool, failures []string) {
+	failures = r.invoke(pod, cppc, hookOnPlace, func(plugin PlacementPlugin) (bool, error) {
+		hook, ok := plugin.(PlaceHook)
+		if !ok || placed {
+			return false, nil
+		}
+		var err error
+		placed, err = hook.OnPlace(ctx, webhook, pod, cppc)
+		return true, err
+	})
+	return placed, failures
+}
+
+// OnConstrain invokes the ConstrainHook of the enabled plugins and returns the resulting constraints.
+func (r *PluginRegistry) OnConstrain(ctx context.Context, reconciler *PodReconciler, pod *Pod,
+	cppc *v1beta1.ClusterPodPlacementConfig) *placementConstraints {
+	constraints := &placementConstraints{}
+	failures := r.invoke(pod, cppc, hookOnConstrain, func(plugin PlacementPlugin) (bool, error) {
+		hook, ok := plugin.(ConstrainHook)
+		if !ok {
+			return false, nil
+		}
+		return true, hook.OnConstrain(ctx, reconciler, pod, constraints, cppc)
+	})
+	pod.publishPluginFailures(failures)
+	return constraints
+}
+
+// OnInspected invokes the InspectedHook of the enabled plugins.
+func (r *PluginRegistry) OnInspected(pod *Pod, architectures []string, cppc *v1beta1.ClusterPodPlacementConfig) {
+	failures := r.invoke(pod, cppc, hookOnInspected, func(plugin PlacementPlugin) (bool, error) {
+		hook, ok := plugin.(InspectedHook)
+		if !ok {
+			return false, nil
+		}
+		return true, hook.OnInspected(pod, architectures, cppc)
+	})
+	pod.publishPluginFailures(failures)
+}
+
+// OnUngate invokes the UngateHook of the enabled plugins.
+func (r *PluginRegistry) OnUngate(pod *Pod, cppc *v1beta1.ClusterPodPlacementConfig) {
+	failures := r.invoke(pod, cppc, hookOnUngate, func(plugin PlacementPlugin) (bool, error) {
+		hook, ok := plugin.(UngateHook)
+		if !ok {
+			return false, nil
+		}
+		return true, hook.OnUngate(pod, cppc)
+	})
+	pod.publishPluginFailures(failures)
+}
+
+// invoke calls the given hook on each of the enabled plugins. The hook returns false if the plugin does not
+// implement it. It returns the messages of the failures.
+func (r *PluginRegistry) invoke(pod *Pod, cppc *v1beta1.ClusterPodPlacementConfig, hookName string,
+	hook func(plugin PlacementPlugin) (bool, error)) (failures []string) {
+	if cppc == nil {
+		return nil
+	}
+	metrics.InitPluginMetrics()
+	log := ctrllog.FromContext(pod.Ctx())
+	for _, plugin := range r.plugins {
+		if !pluginEnabled(plugin, pod, cppc) {
+			continue
+		}
+		now := time.Now()
+		implemented, err := hook(plugin)
+		if !implemented {
+			continue
+		}
+		metrics.PluginHookDuration.WithLabelValues(plugin.Name(), hookName).Observe(time.Since(now).Seconds())
+		if err != nil {
+			log.Error(err, "The plugin hook failed", "plugin", plugin.Name(), "hook", hookName)
+			metrics.PluginHookInvocations.WithLabelValues(plugin.Name(), hookName, metrics.PluginResultError).Inc()
+			failures = append(failures, fmt.Sprintf(PluginErrorMsg, plugin.Name(), hookName, err.Error()))
+			continue
+		}
+		metrics.PluginHookInvocations.WithLabelValues(plugin.Name(), hookName, metrics.PluginResultSuccess).Inc()
+	}
+	return failures
+}
+
+// pluginEnabled returns whether the plugin is enabled for the pod: in the PodPlacementConfig matching the pod for the
+// LocalPlacementPlugins, in the ClusterPodPlacementConfig otherwise.
+func pluginEnabled(plugin PlacementPlugin, pod *Pod, cppc *v1beta1.ClusterPodPlacementConfig) bool {
+	if _, ok := plugin.(LocalPlacementPlugin); ok {
+		localPlugins := pod.localPlugins()
+		return localPlugins != nil && localPlugins.PluginEnabled(plugin.ID())
+	}
+	return cppc.PluginsEnabled(plugin.ID())
+}
+
+// setLocalPluginsReader sets the reader the PodPlacementConfig matching the pod is read with, see localPlugins. The
+// PodPlacementConfig is read at most once, when the plugins are first resolved.
+func (pod *Pod) setLocalPluginsReader(reader client.Reader) {
+	pod.resolveLocalPlugins = sync.OnceValue(func() *plugins.LocalPlugins {
+		ppc, err := matchingPodPlacementConfig(pod.Ctx(), reader, pod)
+		if err != nil {
+			ctrllog.FromContext(pod.Ctx()).Error(err, "Unable to list the PodPlacementConfigs")
+			return nil
+		}
+		if ppc == nil {
+			return nil
+		}
+		return ppc.Spec.Plugins
+	})
+}
+
+// localPlugins returns the plugins of the PodPlacementConfig matching the pod, see matchingPodPlacementConfig.
+// It returns nil if no PodPlacementConfig matches or no reader is set.
+func (pod *Pod) localPlugins() *plugins.LocalPlugins {
+	if pod.resolveLocalPlugins == nil {
+		return nil
+	}
+	return pod.resolveLocalPlugins()
+}
+
+func (pod *Pod) publishPluginFailures(failures []string) {
+	for _, failure := range failures {
+		pod.PublishEvent(corev1.EventTypeWarning, ArchitectureAwarePluginError, failure)
+	}
+}
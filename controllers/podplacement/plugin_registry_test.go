@@ -0,0 +1,206 @@
+package podplacement
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
+
+	. "github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
+)
+
+type fakePlacementPlugin struct {
+	id            common.Plugin
+	err           error
+	placed        bool
+	architectures []string
+	invocations   *[]string
+}
+
+func (p *fakePlacementPlugin) ID() common.Plugin {
+	return p.id
+}
+
+func (p *fakePlacementPlugin) Name() string {
+	return map[common.Plugin]string{
+		common.NodeAffinityScoringPluginName:     "first",
+		common.ArchitectureTolerationsPluginName: "second",
+		common.EmulationFallbackPluginName:       "third",
+		common.ArchitectureSpreadPluginName:      "local",
+	}[p.id]
+}
+
+type fakeLocalPlacementPlugin struct {
+	fakePlacementPlugin
+}
+
+func (p *fakeLocalPlacementPlugin) configuredLocally() {}
+
+func (p *fakePlacementPlugin) OnAdmit(_ *Pod, _ *v1beta1.ClusterPodPlacementConfig) error {
+	*p.invocations = append(*p.invocations, p.Name())
+	return p.err
+}
+
+func (p *fakePlacementPlugin) OnPlace(_ context.Context, _ *PodSchedulingGateMutatingWebHook, _ *Pod,
+	_ *v1beta1.ClusterPodPlacementConfig) (bool, error) {
+	*p.invocations = append(*p.invocations, p.Name())
+	return p.placed, p.err
+}
+
+func (p *fakePlacementPlugin) OnConstrain(_ context.Context, _ *PodReconciler, _ *Pod,
+	constraints *placementConstraints, _ *v1beta1.ClusterPodPlacementConfig) error {
+	*p.invocations = append(*p.invocations, p.Name())
+	if p.err != nil {
+		return p.err
+	}
+	constraints.nodeArchitectures = sets.New(p.architectures...)
+	return nil
+}
+
+func TestPluginRegistry_OnAdmit(t *testing.T) {
+	g := NewGomegaWithT(t)
+	invocations := []string{}
+	registry := NewPluginRegistry(
+		&fakePlacementPlugin{id: common.EmulationFallbackPluginName, invocations: &invocations},
+		&fakePlacementPlugin{id: common.ArchitectureTolerationsPluginName, invocations: &invocations,
+			err: errors.New("failure")},
+		&fakePlacementPlugin{id: common.NodeAffinityScoringPluginName, invocations: &invocations},
+	)
+	cppc := NewClusterPodPlacementConfig().WithNodeAffinityScoring(true).WithArchitectureTolerations(true).Build()
+	pod := newPod(NewPod().Build(), ctx, nil)
+
+	failures := registry.OnAdmit(pod, cppc)
+	g.Expect(invocations).To(Equal([]string{"first", "second"}),
+		"the hooks of the enabled plugins should be invoked in the order of their ID")
+	g.Expect(failures).To(HaveLen(1))
+	g.Expect(failures[0]).To(ContainSubstring("second"))
+
+	invocations = invocations[:0]
+	g.Expect(registry.OnAdmit(pod, nil)).To(BeEmpty())
+	g.Expect(invocations).To(BeEmpty(), "no hook should be invoked without a ClusterPodPlacementConfig")
+}
+
+func TestPluginRegistry_OnPlace(t *testing.T) {
+	cppc := NewClusterPodPlacementConfig().WithNodeAffinityScoring(true).WithArchitectureTolerations(true).Build()
+	tests := []struct {
+		name            string
+		first           *fakePlacementPlugin
+		second          *fakePlacementPlugin
+		wantPlaced      bool
+		wantInvocations []string
+		wantFailures    int
+	}{
+		{
+			name:            "no plugin places the pod",
+			first:           &fakePlacementPlugin{id: common.NodeAffinityScoringPluginName},
+			second:          &fakePlacementPlugin{id: common.ArchitectureTolerationsPluginName},
+			wantPlaced:      false,
+			wantInvocations: []string{"first", "second"},
+		},
+		{
+			name:            "the first plugin places the pod",
+			first:           &fakePlacementPlugin{id: common.NodeAffinityScoringPluginName, placed: true},
+			second:          &fakePlacementPlugin{id: common.ArchitectureTolerationsPluginName, placed: true},
+			wantPlaced:      true,
+			wantInvocations: []string{"first"},
+		},
+		{
+			name: "a failing plugin does not prevent the next one from placing the pod",
+			first: &fakePlacementPlugin{id: common.NodeAffinityScoringPluginName,
+				err: errors.New("failure")},
+			second:          &fakePlacementPlugin{id: common.ArchitectureTolerationsPluginName, placed: true},
+			wantPlaced:      true,
+			wantInvocations: []string{"first", "second"},
+			wantFailures:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			invocations := []string{}
+			tt.first.invocations, tt.second.invocations = &invocations, &invocations
+			registry := NewPluginRegistry(tt.first, tt.second)
+			pod := newPod(NewPod().Build(), ctx, nil)
+			placed, failures := registry.OnPlace(ctx, nil, pod, cppc)
+			g.Expect(placed).To(Equal(tt.wantPlaced))
+			g.Expect(invocations).To(Equal(tt.wantInvocations))
+			g.Expect(failures).To(HaveLen(tt.wantFailures))
+		})
+	}
+}
+
+func TestPluginRegistry_OnConstrain(t *testing.T) {
+	g := NewGomegaWithT(t)
+	invocations := []string{}
+	registry := NewPluginRegistry(
+		&fakePlacementPlugin{id: common.NodeAffinityScoringPluginName, invocations: &invocations,
+			architectures: []string{utils.ArchitectureArm64}},
+		&fakePlacementPlugin{id: common.EmulationFallbackPluginName, invocations: &invocations,
+			architectures: []string{utils.ArchitectureAmd64}},
+	)
+	cppc := NewClusterPodPlacementConfig().WithNodeAffinityScoring(true).Build()
+	pod := newPod(NewPod().Build(), ctx, nil)
+
+	constraints := registry.OnConstrain(ctx, nil, pod, cppc)
+	g.Expect(invocations).To(Equal([]string{"first"}), "only the hooks of the enabled plugins should be invoked")
+	g.Expect(sets.List(constraints.nodeArchitectures)).To(Equal([]string{utils.ArchitectureArm64}))
+
+	constraints = registry.OnConstrain(ctx, nil, pod, nil)
+	g.Expect(constraints.nodeArchitectures).To(BeNil(), "the pod should not be constrained without a ClusterPodPlacementConfig")
+}
+
+func TestPluginRegistry_LocalPlugins(t *testing.T) {
+	cppc := NewClusterPodPlacementConfig().WithNodeAffinityScoring(true).Build()
+	tests := []struct {
+		name            string
+		localPlugins    *plugins.LocalPlugins
+		wantInvocations []string
+	}{
+		{
+			name:            "no PodPlacementConfig matches the pod",
+			wantInvocations: []string{"first"},
+		},
+		{
+			name: "the local plugin is disabled in the PodPlacementConfig",
+			localPlugins: &plugins.LocalPlugins{
+				ArchitectureSpread: &plugins.ArchitectureSpread{BasePlugin: plugins.BasePlugin{Enabled: false}},
+			},
+			wantInvocations: []string{"first"},
+		},
+		{
+			name: "the local plugin is enabled in the PodPlacementConfig",
+			localPlugins: &plugins.LocalPlugins{
+				ArchitectureSpread: &plugins.ArchitectureSpread{BasePlugin: plugins.BasePlugin{Enabled: true}},
+			},
+			wantInvocations: []string{"first", "local"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			invocations := []string{}
+			registry := NewPluginRegistry(
+				&fakeLocalPlacementPlugin{fakePlacementPlugin{id: common.ArchitectureSpreadPluginName,
+					invocations: &invocations}},
+				&fakePlacementPlugin{id: common.NodeAffinityScoringPluginName, invocations: &invocations},
+			)
+			pod := newPod(NewPod().Build(), ctx, nil)
+			pod.resolveLocalPlugins = func() *plugins.LocalPlugins {
+				return tt.localPlugins
+			}
+			placed, failures := registry.OnPlace(ctx, nil, pod, cppc)
+			g.Expect(placed).To(BeFalse())
+			g.Expect(failures).To(BeEmpty())
+			g.Expect(invocations).To(Equal(tt.wantInvocations),
+				"the local plugins should be invoked only when enabled in the PodPlacementConfig matching the pod")
+		})
+	}
+}
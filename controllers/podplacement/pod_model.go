@@ -23,12 +23,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/opencontainers/go-digest"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 
-	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
 	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
 	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
 	"github.com/openshift/multiarch-tuning-operator/pkg/image"
@@ -50,6 +51,11 @@ type containerImage struct {
 
 type Pod struct {
 	models.Pod
+	// inspectedDigests are the digests of the manifests, or indexes, the architectures of the images were computed
+	// from, by image name. They are the digests the images are pinned to.
+	inspectedDigests map[string]digest.Digest
+	// resolveLocalPlugins resolves the plugins of the PodPlacementConfig matching the pod, see setLocalPluginsReader.
+	resolveLocalPlugins func() *plugins.LocalPlugins
 }
 
 func newPod(pod *corev1.Pod, ctx context.Context, recorder record.EventRecorder) *Pod {
@@ -63,6 +69,12 @@ func (pod *Pod) HasSchedulingGate() bool {
 	return pod.HasGate(utils.SchedulingGateName)
 }
 
+// isGatedByWebhook returns true if the pod has both the scheduling gate and the label the webhook sets when gating it.
+// The label alone can be set by the user: it does not tell that the webhook already processed the pod.
+func (pod *Pod) isGatedByWebhook() bool {
+	return pod.HasSchedulingGate() && pod.Labels[utils.SchedulingGateLabel] == utils.SchedulingGateLabelValueGated
+}
+
 // RemoveSchedulingGate removes the scheduling gate utils.SchedulingGateName from the pod.
 func (pod *Pod) RemoveSchedulingGate() {
 	pod.RemoveGate(utils.SchedulingGateName)
@@ -72,6 +84,11 @@ func (pod *Pod) RemoveSchedulingGate() {
 	pod.EnsureLabel(utils.SchedulingGateLabel, utils.SchedulingGateLabelValueRemoved)
 }
 
+// isGatedBeyondDeadline returns true if the pod still has the scheduling gate and was created more than deadline ago.
+func (pod *Pod) isGatedBeyondDeadline(deadline time.Duration, now time.Time) bool {
+	return pod.HasSchedulingGate() && now.Sub(pod.CreationTimestamp.Time) > deadline
+}
+
 // ensureSchedulingGate ensures that the pod has the scheduling gate utils.SchedulingGateName.
 func (pod *Pod) ensureSchedulingGate() {
 	pod.AddGate(utils.SchedulingGateName)
@@ -93,20 +110,66 @@ func (pod *Pod) getPodImagePullSecrets() []string {
 // SetNodeAffinityArchRequirement wraps the logic to set the nodeAffinity for the pod.
 // It verifies first that no nodeSelector field is set for the kubernetes.io/arch label.
 // Then, it computes the intersection of the architectures supported by the images used by the pod via pod.getArchitecturePredicate.
+// When allowedArchitectures is not nil, the architectures are restricted to the allowed ones: if none of them is
+// allowed, the pod is labeled and kept unschedulable.
+// When nodeArchitectures is not nil, the architectures are further intersected with the ones available in the cluster.
+// If none of them is available and keepGatedUntilNodeAvailable is true, the pod is labeled as waiting for an eligible
+// node and the nodeAffinity is not set.
 // Finally, it initializes the nodeAffinity for the pod and set it to the computed requirement via the pod.setRequiredArchNodeAffinity method.
-func (pod *Pod) SetNodeAffinityArchRequirement(pullSecretDataList [][]byte) (bool, error) {
+// It returns the architectures set in the nodeAffinity, if any.
+func (pod *Pod) SetNodeAffinityArchRequirement(pullSecretDataList [][]byte, allowedArchitectures,
+	nodeArchitectures sets.Set[string], keepGatedUntilNodeAvailable bool) ([]string, error) {
 	if pod.isNodeSelectorConfiguredForArchitecture() {
-		pod.publishIgnorePod()
-		return false, nil
+		// The selection of the user is verified, not ignored: no event is published unless it conflicts with the images.
+		ctrllog.FromContext(pod.Ctx()).V(1).Info("The architecture of the pod is selected by the user, verifying the selection")
+		pod.EnsureLabel(utils.NodeAffinityLabel, utils.LabelValueNotSet)
+		if err := pod.verifyArchitectureSelection(pullSecretDataList); err != nil {
+			return nil, err
+		}
+		pod.restrictArchitectureSelection(allowedArchitectures)
+		return nil, nil
 	}
-	requirement, err := pod.getArchitecturePredicate(pullSecretDataList)
-	if err != nil {
-		return false, err
+	if skipped := pod.skippedImageVolumes(); len(skipped) > 0 {
+		pod.PublishEvent(corev1.EventTypeNormal, ArchitectureAwareImageVolumesSkipped,
+			ImageVolumesSkippedMsg+strings.Join(skipped, ", "))
+	}
+	var requirement corev1.NodeSelectorRequirement
+	var nodeAvailable bool
+	if pod.imagesNamesSet().Len() == 0 {
+		// All the containers are assumed multi-arch by the injected container rules: any architecture is supported.
+		pod.PublishEvent(corev1.EventTypeNormal, ArchitectureAwareNodeAffinitySet, AllContainersAssumedMultiArchMsg)
+		if allowedArchitectures == nil {
+			return nil, nil
+		}
+		// Only the architectures allowed by the allowedArchitectures policy are required.
+		requirement, nodeAvailable = allowedArchitecturesPredicate(allowedArchitectures, nodeArchitectures)
+	} else {
+		var err error
+		requirement, nodeAvailable, err = pod.getArchitecturePredicate(pullSecretDataList, allowedArchitectures,
+			nodeArchitectures)
+		if err != nil {
+			return nil, err
+		}
 	}
 	pod.EnsureNoLabel(utils.ImageInspectionErrorLabel)
-	if len(requirement.Values) == 0 {
+	if requirement.Key == utils.NoAllowedArchLabel {
+		pod.EnsureLabel(utils.NoAllowedArchLabel, "")
+		pod.PublishEvent(corev1.EventTypeWarning, NoAllowedArchitecturesFound,
+			NoAllowedArchitecturesFoundMsg+strings.Join(sets.List(allowedArchitectures), ", "))
+	} else if len(requirement.Values) == 0 {
 		pod.PublishEvent(corev1.EventTypeNormal, NoSupportedArchitecturesFound, NoSupportedArchitecturesFoundMsg)
 	}
+	if !nodeAvailable {
+		pod.PublishEvent(corev1.EventTypeWarning, NoEligibleNodeArchitectureFound,
+			NoEligibleNodeArchitectureFoundMsg+strings.Join(requirement.Values, ", "))
+		if keepGatedUntilNodeAvailable {
+			pod.EnsureLabel(utils.NoEligibleNodeArchLabel, utils.NoEligibleNodeArchLabelWaiting)
+			return nil, nil
+		}
+		pod.EnsureLabel(utils.NoEligibleNodeArchLabel, "")
+	} else {
+		pod.EnsureNoLabel(utils.NoEligibleNodeArchLabel)
+	}
 	pod.ensureArchitectureLabels(requirement)
 
 	if pod.Spec.Affinity == nil {
@@ -122,7 +185,49 @@ func (pod *Pod) SetNodeAffinityArchRequirement(pullSecretDataList [][]byte) (boo
 	}
 
 	pod.setRequiredArchNodeAffinity(requirement)
-	return true, nil
+	return requirement.Values, nil
+}
+
+// SetArchitectureTolerations adds to the pod the tolerations for the taints of the nodes dedicated to the given
+// architectures, as configured in the ArchitectureTolerations plugin.
+// The tolerations already set in the pod are never removed or modified: a toleration is added only if no existing
+// toleration already tolerates the taint.
+func (pod *Pod) SetArchitectureTolerations(architectures []string, plugin *plugins.ArchitectureTolerations) {
+	added := []string{}
+	for _, toleration := range plugin.TolerationsFor(architectures) {
+		if pod.hasTolerationFor(toleration) {
+			continue
+		}
+		pod.Spec.Tolerations = append(pod.Spec.Tolerations, toleration)
+		added = append(added, fmt.Sprintf("%s=%s:%s", toleration.Key, toleration.Value, toleration.Effect))
+	}
+	if len(added) == 0 {
+		return
+	}
+	pod.PublishEvent(corev1.EventTypeNormal, ArchitectureAwareTolerationsSet,
+		ArchitectureTolerationsSetMsg+strings.Join(added, ", "))
+}
+
+// hasTolerationFor returns true if the pod already has a toleration that tolerates all the taints the given
+// toleration is meant to tolerate.
+func (pod *Pod) hasTolerationFor(toleration corev1.Toleration) bool {
+	taint := &corev1.Taint{
+		Key:    toleration.Key,
+		Value:  toleration.Value,
+		Effect: toleration.Effect,
+	}
+	for i := range pod.Spec.Tolerations {
+		existing := &pod.Spec.Tolerations[i]
+		// A toleration with the Exists operator matches any value: only another toleration with the Exists operator
+		// can cover it.
+		if toleration.Operator == corev1.TolerationOpExists && existing.Operator != corev1.TolerationOpExists {
+			continue
+		}
+		if existing.ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
 }
 
 // setRequiredArchNodeAffinity sets the node affinity for the pod to the given requirement based on the rules in
@@ -184,7 +289,7 @@ func (pod *Pod) SetPreferredArchNodeAffinity(cppc *v1beta1.ClusterPodPlacementCo
 		pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = []corev1.PreferredSchedulingTerm{}
 	}
 
-	for _, nodeAffinityScoringPlatformTerm := range cppc.Spec.Plugins.NodeAffinityScoring.Platforms {
+	for _, nodeAffinityScoringPlatformTerm := range cppc.NodeAffinityScoringPlatforms() {
 		preferredSchedulingTerm := corev1.PreferredSchedulingTerm{
 			Weight: nodeAffinityScoringPlatformTerm.Weight,
 			Preference: corev1.NodeSelectorTerm{
@@ -207,37 +312,129 @@ func (pod *Pod) SetPreferredArchNodeAffinity(cppc *v1beta1.ClusterPodPlacementCo
 	pod.PublishEvent(corev1.EventTypeNormal, ArchitectureAwareNodeAffinitySet, ArchitecturePreferredPredicateSetupMsg)
 }
 
-func (pod *Pod) getArchitecturePredicate(pullSecretDataList [][]byte) (corev1.NodeSelectorRequirement, error) {
+// getArchitecturePredicate returns the requirement for the architectures supported by all the images of the pod.
+// When allowedArchitectures is not nil, the architectures are intersected with it. If the intersection is empty, the
+// requirement is on the utils.NoAllowedArchLabel label, that no node has, so that the pod cannot be scheduled.
+// When nodeArchitectures is not nil, the architectures are intersected with it. If the intersection is empty, the
+// requirement keeps the architectures supported by the images, so that the pod can be scheduled as soon as a matching
+// node joins the cluster, and nodeAvailable is false.
+func (pod *Pod) getArchitecturePredicate(pullSecretDataList [][]byte, allowedArchitectures,
+	nodeArchitectures sets.Set[string]) (requirement corev1.NodeSelectorRequirement, nodeAvailable bool, err error) {
 	architectures, err := pod.intersectImagesArchitecture(pullSecretDataList)
 	// if an error occurs, we return an empty NodeSelectorRequirement and the error.
 	if err != nil {
-		return corev1.NodeSelectorRequirement{}, err
+		return corev1.NodeSelectorRequirement{}, false, err
 	}
 
 	if len(architectures) == 0 {
 		return corev1.NodeSelectorRequirement{
 			Key:      utils.NoSupportedArchLabel,
 			Operator: corev1.NodeSelectorOpExists,
-		}, nil
+		}, true, nil
+	}
+	if allowedArchitectures != nil {
+		architectures = sets.List(allowedArchitectures.Intersection(sets.New(architectures...)))
+		if len(architectures) == 0 {
+			return corev1.NodeSelectorRequirement{
+				Key:      utils.NoAllowedArchLabel,
+				Operator: corev1.NodeSelectorOpExists,
+			}, true, nil
+		}
+	}
+	nodeAvailable = true
+	if nodeArchitectures != nil {
+		if availableArchitectures := nodeArchitectures.Intersection(sets.New(architectures...)); availableArchitectures.Len() > 0 {
+			architectures = sets.List(availableArchitectures)
+		} else {
+			nodeAvailable = false
+		}
 	}
 	return corev1.NodeSelectorRequirement{
 		Key:      utils.ArchLabel,
 		Operator: corev1.NodeSelectorOpIn,
 		Values:   architectures,
-	}, nil
+	}, nodeAvailable, nil
 }
 
+// allowedArchitecturesPredicate returns the requirement for the allowed architectures of a pod whose images support
+// any architecture, restricted to the architectures of the nodes, if any, and whether a node is available.
+func allowedArchitecturesPredicate(allowedArchitectures,
+	nodeArchitectures sets.Set[string]) (requirement corev1.NodeSelectorRequirement, nodeAvailable bool) {
+	architectures := allowedArchitectures
+	nodeAvailable = true
+	if nodeArchitectures != nil {
+		if availableArchitectures := nodeArchitectures.Intersection(allowedArchitectures); availableArchitectures.Len() > 0 {
+			architectures = availableArchitectures
+		} else {
+			nodeAvailable = false
+		}
+	}
+	return corev1.NodeSelectorRequirement{
+		Key:      utils.ArchLabel,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   sets.List(architectures),
+	}, nodeAvailable
+}
+
+// isWaitingForEligibleNode returns true if the pod is kept gated until a node with one of the architectures
+// supported by its images joins the cluster.
+func (pod *Pod) isWaitingForEligibleNode() bool {
+	return pod.HasSchedulingGate() && pod.Labels[utils.NoEligibleNodeArchLabel] == utils.NoEligibleNodeArchLabelWaiting
+}
+
+// imagesNamesSet returns the images of the pod to inspect: the ones of the containers, excluding the containers
+// assumed multi-arch by the injected container rules, and the ones of the image volumes, excluding the volumes
+// listed in the utils.ArchitectureAgnosticVolumesAnnotation annotation.
 func (pod *Pod) imagesNamesSet() sets.Set[containerImage] {
 	imageNamesSet := sets.New[containerImage]()
+	assumedMultiArch := pod.assumedMultiArchContainers()
 	for _, container := range append(pod.Spec.Containers, pod.Spec.InitContainers...) {
+		if assumedMultiArch.Has(container.Name) {
+			continue
+		}
 		imageNamesSet.Insert(containerImage{
 			imageName: fmt.Sprintf("//%s", container.Image),
 			skipCache: container.ImagePullPolicy == corev1.PullAlways,
 		})
 	}
+	architectureAgnosticVolumes := pod.architectureAgnosticVolumes()
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Image == nil || volume.Image.Reference == "" || architectureAgnosticVolumes.Has(volume.Name) {
+			continue
+		}
+		imageNamesSet.Insert(containerImage{
+			imageName: fmt.Sprintf("//%s", volume.Image.Reference),
+			skipCache: volume.Image.PullPolicy == corev1.PullAlways,
+		})
+	}
 	return imageNamesSet
 }
 
+// architectureAgnosticVolumes returns the names of the volumes listed in the
+// utils.ArchitectureAgnosticVolumesAnnotation annotation.
+func (pod *Pod) architectureAgnosticVolumes() sets.Set[string] {
+	volumes := sets.New[string]()
+	for _, name := range strings.Split(pod.Annotations[utils.ArchitectureAgnosticVolumesAnnotation], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			volumes.Insert(name)
+		}
+	}
+	return volumes
+}
+
+// skippedImageVolumes returns the sorted names of the image volumes that are not inspected because they are listed
+// in the utils.ArchitectureAgnosticVolumesAnnotation annotation.
+func (pod *Pod) skippedImageVolumes() []string {
+	architectureAgnosticVolumes := pod.architectureAgnosticVolumes()
+	skipped := sets.New[string]()
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Image != nil && architectureAgnosticVolumes.Has(volume.Name) {
+			skipped.Insert(volume.Name)
+		}
+	}
+	return sets.List(skipped)
+}
+
 // inspect returns the list of supported architectures for the images used by the pod.
 // if an error occurs, it returns the error and a nil slice of strings.
 func (pod *Pod) intersectImagesArchitecture(pullSecretDataList [][]byte) (supportedArchitectures []string, err error) {
@@ -255,8 +452,7 @@ func (pod *Pod) intersectImagesArchitecture(pullSecretDataList [][]byte) (suppor
 		// We are collecting the time to inspect the image here to avoid implementing a metric in each of the
 		// cache implementations.
 		now := time.Now()
-		currentImageSupportedArchitectures, err := imageInspectionCache.GetCompatibleArchitecturesSet(pod.Ctx(),
-			imageContainer.imageName, imageContainer.skipCache, pullSecretDataList)
+		currentImageSupportedArchitectures, err := pod.inspectImage(imageContainer, pullSecretDataList)
 		utils.HistogramObserve(now, metrics.TimeToInspectImage)
 		if err != nil {
 			log.V(1).Error(err, "Error inspecting the image", "imageName", imageContainer.imageName)
@@ -271,6 +467,26 @@ func (pod *Pod) intersectImagesArchitecture(pullSecretDataList [][]byte) (suppor
 	return sets.List(supportedArchitecturesSet), nil
 }
 
+// inspectImage returns the architectures supported by the image. When the inspection facade returns the digest they
+// were computed from, it is recorded for pinImageDigests to pin the image to the same manifest.
+func (pod *Pod) inspectImage(imageContainer containerImage, pullSecretDataList [][]byte) (sets.Set[string], error) {
+	inspector, ok := imageInspectionCache.(imageInspector)
+	if !ok {
+		return imageInspectionCache.GetCompatibleArchitecturesSet(pod.Ctx(), imageContainer.imageName,
+			imageContainer.skipCache, pullSecretDataList)
+	}
+	inspection, err := inspector.InspectImage(pod.Ctx(), imageContainer.imageName, imageContainer.skipCache,
+		pullSecretDataList)
+	if err != nil {
+		return nil, err
+	}
+	if pod.inspectedDigests == nil {
+		pod.inspectedDigests = map[string]digest.Digest{}
+	}
+	pod.inspectedDigests[imageContainer.imageName] = inspection.Digest
+	return inspection.Architectures, nil
+}
+
 func (pod *Pod) maxRetries() bool {
 	if pod.Labels == nil {
 		return false
@@ -313,13 +529,11 @@ func (pod *Pod) ensureArchitectureLabels(requirement corev1.NodeSelectorRequirem
 // - the pod has a node name set
 // - the pod has a node selector that matches the control plane nodes
 // - the pod is owned by a DaemonSet
-// - both the nodeSelector/nodeAffinity and the preferredAffinity are set for the kubernetes.io/arch label.
-// - only the nodeSelector/nodeAffinity is set for the kubernetes.io/arch label and the NodeAffinityScoring plugin is disabled.
-func (pod *Pod) shouldIgnorePod(cppc *v1beta1.ClusterPodPlacementConfig) bool {
+// The pods with a nodeSelector/nodeAffinity already set for the kubernetes.io/arch label are not ignored: their
+// images are inspected to verify the architectures selected by the user, see pod.verifyArchitectureSelection.
+func (pod *Pod) shouldIgnorePod() bool {
 	return utils.Namespace() == pod.Namespace || strings.HasPrefix(pod.Namespace, "kube-") ||
-		pod.Spec.NodeName != "" || pod.HasControlPlaneNodeSelector() || pod.IsFromDaemonSet() ||
-		pod.isNodeSelectorConfiguredForArchitecture() &&
-			(!cppc.PluginsEnabled(common.NodeAffinityScoringPluginName) || pod.isPreferredAffinityConfiguredForArchitecture())
+		pod.Spec.NodeName != "" || pod.HasControlPlaneNodeSelector() || pod.IsFromDaemonSet()
 }
 
 // isNodeSelectorConfiguredForArchitecture returns true if the pod has already a nodeSelector for the architecture label
@@ -332,7 +546,6 @@ func (pod *Pod) isNodeSelectorConfiguredForArchitecture() bool {
 	// nodeSelectorTerm's MatchExpressions field.
 	for key := range pod.Spec.NodeSelector {
 		if key == utils.ArchLabel {
-			pod.publishIgnorePod()
 			return true
 		}
 	}
@@ -367,13 +580,6 @@ func (pod *Pod) isNodeSelectorConfiguredForArchitecture() bool {
 	return true
 }
 
-func (pod *Pod) publishIgnorePod() {
-	log := ctrllog.FromContext(pod.Ctx())
-	log.V(1).Info("The pod has the nodeSelector or all the nodeAffinityTerms set for the kubernetes.io/arch label. Ignoring the pod...")
-	pod.EnsureLabel(utils.NodeAffinityLabel, utils.LabelValueNotSet)
-	pod.PublishEvent(corev1.EventTypeNormal, ArchitecturePredicatesConflict, ArchitecturePredicatesConflictMsg)
-}
-
 func (pod *Pod) handleError(err error, s string) {
 	if err == nil {
 		return
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -103,6 +104,44 @@ func TestPod_HasSchedulingGate(t *testing.T) {
 	}
 }
 
+func TestPod_isGatedByWebhook(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want bool
+	}{
+		{
+			name: "pod gated by the webhook",
+			pod: NewPod().WithSchedulingGates(utils.SchedulingGateName).
+				WithLabels(utils.SchedulingGateLabel, utils.SchedulingGateLabelValueGated).Build(),
+			want: true,
+		},
+		{
+			name: "pod created with the scheduling gate label only",
+			pod:  NewPod().WithLabels(utils.SchedulingGateLabel, utils.SchedulingGateLabelValueGated).Build(),
+			want: false,
+		},
+		{
+			name: "pod created with the scheduling gate only",
+			pod:  NewPod().WithSchedulingGates(utils.SchedulingGateName).Build(),
+			want: false,
+		},
+		{
+			name: "pod with the scheduling gate and the label of an ignored pod",
+			pod: NewPod().WithSchedulingGates(utils.SchedulingGateName).
+				WithLabels(utils.SchedulingGateLabel, utils.LabelValueNotSet).Build(),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := newPod(tt.pod, ctx, nil)
+			g := NewGomegaWithT(t)
+			g.Expect(pod.isGatedByWebhook()).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestPod_RemoveSchedulingGate(t *testing.T) {
 	tests := []struct {
 		name string
@@ -212,6 +251,28 @@ func TestPod_imagesNamesSet(t *testing.T) {
 				containerImage{imageName: "//foo/pull:always", skipCache: true},
 			),
 		},
+		{
+			name: "pod with image volumes",
+			pod: NewPod().WithContainersImages("bar/foo:latest").
+				WithImageVolume("plugins", "foo/plugins:latest", v1.PullIfNotPresent).
+				WithImageVolume("models", "foo/models:latest", v1.PullAlways).Build(),
+			want: sets.New[containerImage](
+				containerImage{imageName: "//bar/foo:latest"},
+				containerImage{imageName: "//foo/plugins:latest"},
+				containerImage{imageName: "//foo/models:latest", skipCache: true},
+			),
+		},
+		{
+			name: "pod with architecture-agnostic image volumes",
+			pod: NewPod().WithContainersImages("bar/foo:latest").
+				WithImageVolume("plugins", "foo/plugins:latest", v1.PullIfNotPresent).
+				WithImageVolume("models", "foo/models:latest", v1.PullAlways).
+				WithAnnotations(map[string]string{utils.ArchitectureAgnosticVolumesAnnotation: "models, data"}).Build(),
+			want: sets.New[containerImage](
+				containerImage{imageName: "//bar/foo:latest"},
+				containerImage{imageName: "//foo/plugins:latest"},
+			),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -287,12 +348,15 @@ func TestPod_intersectImagesArchitecture(t *testing.T) {
 
 func TestPod_getArchitecturePredicate(t *testing.T) {
 	tests := []struct {
-		name               string
-		pod                *v1.Pod
-		pullSecretDataList [][]byte
+		name                 string
+		pod                  *v1.Pod
+		pullSecretDataList   [][]byte
+		allowedArchitectures sets.Set[string]
+		nodeArchitectures    sets.Set[string]
 		// Be aware that the values in the want.Values slice must be sorted alphabetically
-		want    v1.NodeSelectorRequirement
-		wantErr bool
+		want              v1.NodeSelectorRequirement
+		wantNodeAvailable bool
+		wantErr           bool
 	}{
 		{
 			name: "pod with several containers using multi-arch images",
@@ -315,6 +379,29 @@ func TestPod_getArchitecturePredicate(t *testing.T) {
 				Operator: v1.NodeSelectorOpIn,
 				Values:   []string{utils.ArchitectureAmd64, utils.ArchitectureArm64},
 			},
+			wantNodeAvailable: true,
+		},
+		{
+			name:              "pod using multi-arch images intersected with the node architectures",
+			pod:               NewPod().WithContainersImages(fake.MultiArchImage, fake.MultiArchImage2).Build(),
+			nodeArchitectures: sets.New(utils.ArchitectureArm64, utils.ArchitectureS390x),
+			want: v1.NodeSelectorRequirement{
+				Key:      utils.ArchLabel,
+				Operator: v1.NodeSelectorOpIn,
+				Values:   []string{utils.ArchitectureArm64},
+			},
+			wantNodeAvailable: true,
+		},
+		{
+			name:              "pod using multi-arch images with no node of a supported architecture",
+			pod:               NewPod().WithContainersImages(fake.MultiArchImage, fake.MultiArchImage2).Build(),
+			nodeArchitectures: sets.New(utils.ArchitectureS390x),
+			want: v1.NodeSelectorRequirement{
+				Key:      utils.ArchLabel,
+				Operator: v1.NodeSelectorOpIn,
+				Values:   []string{utils.ArchitectureAmd64, utils.ArchitectureArm64},
+			},
+			wantNodeAvailable: false,
 		},
 		{
 			name: "pod with non-existing image",
@@ -340,24 +427,99 @@ func TestPod_getArchitecturePredicate(t *testing.T) {
 				Key:      utils.NoSupportedArchLabel,
 				Operator: v1.NodeSelectorOpExists,
 			},
+			wantNodeAvailable: true,
+		},
+		{
+			name:                 "pod with a multi-arch image and allowed architectures",
+			pod:                  NewPod().WithContainersImages(fake.MultiArchImage).Build(),
+			allowedArchitectures: sets.New(utils.ArchitectureArm64, utils.ArchitectureS390x),
+			want: v1.NodeSelectorRequirement{
+				Key:      utils.ArchLabel,
+				Operator: v1.NodeSelectorOpIn,
+				Values:   []string{utils.ArchitectureArm64},
+			},
+			wantNodeAvailable: true,
+		},
+		{
+			name:                 "pod with no allowed architecture",
+			pod:                  NewPod().WithContainersImages(fake.SingleArchAmd64Image).Build(),
+			allowedArchitectures: sets.New(utils.ArchitectureArm64),
+			want: v1.NodeSelectorRequirement{
+				Key:      utils.NoAllowedArchLabel,
+				Operator: v1.NodeSelectorOpExists,
+			},
+			wantNodeAvailable: true,
+		},
+		{
+			name:                 "pod with allowed architectures not available in the cluster",
+			pod:                  NewPod().WithContainersImages(fake.MultiArchImage).Build(),
+			allowedArchitectures: sets.New(utils.ArchitectureArm64),
+			nodeArchitectures:    sets.New(utils.ArchitectureAmd64),
+			want: v1.NodeSelectorRequirement{
+				Key:      utils.ArchLabel,
+				Operator: v1.NodeSelectorOpIn,
+				Values:   []string{utils.ArchitectureArm64},
+			},
+			wantNodeAvailable: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			imageInspectionCache = fake.FacadeSingleton()
 			pod := newPod(tt.pod, ctx, nil)
-			got, err := pod.getArchitecturePredicate(tt.pullSecretDataList)
+			got, nodeAvailable, err := pod.getArchitecturePredicate(tt.pullSecretDataList, tt.allowedArchitectures, tt.nodeArchitectures)
 			g := NewGomegaWithT(t)
 			g.Expect(err).Should(WithTransform(func(err error) bool { return err != nil }, Equal(tt.wantErr)),
 				"error expectation failed")
 			// sort the architectures to make the comparison easier
 			sort.Strings(got.Values)
 			g.Expect(got).To(Equal(tt.want))
+			g.Expect(nodeAvailable).To(Equal(tt.wantNodeAvailable))
 			imageInspectionCache = mmoimage.FacadeSingleton()
 		})
 	}
 }
 
+func Test_allowedArchitecturesPredicate(t *testing.T) {
+	tests := []struct {
+		name              string
+		nodeArchitectures sets.Set[string]
+		wantValues        []string
+		wantNodeAvailable bool
+	}{
+		{
+			name:              "no node inventory",
+			wantValues:        []string{utils.ArchitectureAmd64, utils.ArchitectureArm64},
+			wantNodeAvailable: true,
+		},
+		{
+			name:              "nodes of some of the allowed architectures",
+			nodeArchitectures: sets.New(utils.ArchitectureArm64, utils.ArchitectureS390x),
+			wantValues:        []string{utils.ArchitectureArm64},
+			wantNodeAvailable: true,
+		},
+		{
+			name:              "no node of the allowed architectures",
+			nodeArchitectures: sets.New(utils.ArchitectureS390x),
+			wantValues:        []string{utils.ArchitectureAmd64, utils.ArchitectureArm64},
+			wantNodeAvailable: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			requirement, nodeAvailable := allowedArchitecturesPredicate(
+				sets.New(utils.ArchitectureAmd64, utils.ArchitectureArm64), tt.nodeArchitectures)
+			g.Expect(requirement).To(Equal(v1.NodeSelectorRequirement{
+				Key:      utils.ArchLabel,
+				Operator: v1.NodeSelectorOpIn,
+				Values:   tt.wantValues,
+			}))
+			g.Expect(nodeAvailable).To(Equal(tt.wantNodeAvailable))
+		})
+	}
+}
+
 func TestPod_setArchNodeAffinity(t *testing.T) {
 	tests := []struct {
 		name string
@@ -498,7 +660,7 @@ func TestPod_setArchNodeAffinity(t *testing.T) {
 			imageInspectionCache = fake.FacadeSingleton()
 			pod := newPod(tt.pod, ctx, nil)
 			g := NewGomegaWithT(t)
-			pred, err := pod.getArchitecturePredicate(nil)
+			pred, _, err := pod.getArchitecturePredicate(nil, nil, nil)
 			g.Expect(err).ShouldNot(HaveOccurred())
 			pod.setRequiredArchNodeAffinity(pred)
 			g.Expect(pod.Spec.Affinity).Should(Equal(tt.want.Spec.Affinity))
@@ -778,7 +940,7 @@ func TestPod_SetNodeAffinityArchRequirement(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			imageInspectionCache = fake.FacadeSingleton()
 			pod := newPod(tt.pod, ctx, nil)
-			_, err := pod.SetNodeAffinityArchRequirement(tt.pullSecretDataList)
+			_, err := pod.SetNodeAffinityArchRequirement(tt.pullSecretDataList, nil, nil, false)
 			g := NewGomegaWithT(t)
 			if tt.expectErr {
 				g.Expect(err).Should(HaveOccurred())
@@ -914,6 +1076,51 @@ func TestPod_EnsureSchedulingGate(t *testing.T) {
 	}
 }
 
+func TestPod_isGatedBeyondDeadline(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		deadline time.Duration
+		want     bool
+	}{
+		{
+			name: "gated pod created before the deadline",
+			pod: NewPod().WithSchedulingGates(utils.SchedulingGateName).
+				WithCreationTimestamp(metav1.NewTime(now.Add(-11 * time.Minute))).Build(),
+			deadline: 10 * time.Minute,
+			want:     true,
+		},
+		{
+			name: "gated pod created within the deadline",
+			pod: NewPod().WithSchedulingGates(utils.SchedulingGateName).
+				WithCreationTimestamp(metav1.NewTime(now.Add(-9 * time.Minute))).Build(),
+			deadline: 10 * time.Minute,
+			want:     false,
+		},
+		{
+			name: "pod with other scheduling gates created before the deadline",
+			pod: NewPod().WithSchedulingGates("other-gate").
+				WithCreationTimestamp(metav1.NewTime(now.Add(-11 * time.Minute))).Build(),
+			deadline: 10 * time.Minute,
+			want:     false,
+		},
+		{
+			name:     "ungated pod created before the deadline",
+			pod:      NewPod().WithCreationTimestamp(metav1.NewTime(now.Add(-time.Hour))).Build(),
+			deadline: 10 * time.Minute,
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			g.Expect(pod.isGatedBeyondDeadline(tt.deadline, now)).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestPod_shouldIgnorePod(t *testing.T) {
 	type fields struct {
 		Pod      *v1.Pod
@@ -1002,30 +1209,9 @@ func TestPod_shouldIgnorePod(t *testing.T) {
 					},
 				).Build(),
 			},
-			want: true,
+			// the architectures selected by the user are verified against the images
+			want: false,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			pod := newPod(tt.fields.Pod, tt.fields.ctx, tt.fields.recorder)
-			if got := pod.shouldIgnorePod(&v1beta1.ClusterPodPlacementConfig{}); got != tt.want {
-				t.Errorf("shouldIgnorePod() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestPod_shouldIgnorePodWithPluginsEnabledInCPPC(t *testing.T) {
-	type fields struct {
-		Pod      *v1.Pod
-		ctx      context.Context
-		recorder record.EventRecorder
-	}
-	tests := []struct {
-		name   string
-		fields fields
-		want   bool
-	}{
 		{
 			name: "pod with nodeSelector/nodeAffinity and the preferredAffinity is set for the kubernetes.io/arch label",
 			fields: fields{
@@ -1041,73 +1227,13 @@ func TestPod_shouldIgnorePodWithPluginsEnabledInCPPC(t *testing.T) {
 						},
 					).Build(),
 			},
-			want: true,
-		},
-		{
-			name: "pod with set nodeAffinity, the preferredAffinity is not set for the kubernetes.io/arch label",
-			fields: fields{
-				Pod: NewPod().WithContainersImages(fake.SingleArchAmd64Image).
-					WithNodeSelectorTermsMatchExpressions(
-						[]v1.NodeSelectorRequirement{
-							{
-								Key:      utils.ArchLabel,
-								Operator: v1.NodeSelectorOpExists,
-								Values:   []string{utils.ArchitectureAmd64},
-							},
-						},
-					).Build(),
-			},
 			want: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			pod := newPod(tt.fields.Pod, tt.fields.ctx, tt.fields.recorder)
-			if got := pod.shouldIgnorePod(NewClusterPodPlacementConfig().
-				WithName(common.SingletonResourceObjectName).
-				WithNodeAffinityScoring(true).
-				WithNodeAffinityScoringTerm(utils.ArchitectureAmd64, 1).Build(),
-			); got != tt.want {
-				t.Errorf("shouldIgnorePod() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestPod_shouldIgnorePodWithPluginsDisabledInCPPC(t *testing.T) {
-	type fields struct {
-		Pod      *v1.Pod
-		ctx      context.Context
-		recorder record.EventRecorder
-	}
-	tests := []struct {
-		name   string
-		fields fields
-		want   bool
-	}{
-		{
-			name: "pod with nodeSelector/nodeAffinity is set for the kubernetes.io/arch label and the NodeAffinityScoring plugin is disabled ",
-			fields: fields{
-				Pod: NewPod().WithContainersImages(fake.MultiArchImage).WithNodeSelectorTermsMatchExpressions(
-					[]v1.NodeSelectorRequirement{
-						{
-							Key:      utils.ArchLabel,
-							Operator: v1.NodeSelectorOpExists,
-							Values:   []string{utils.ArchitectureAmd64},
-						},
-					},
-				).Build(),
-			},
-			want: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			pod := newPod(tt.fields.Pod, tt.fields.ctx, tt.fields.recorder)
-			if got := pod.shouldIgnorePod(NewClusterPodPlacementConfig().
-				WithName(common.SingletonResourceObjectName).
-				WithNodeAffinityScoring(false).
-				WithNodeAffinityScoringTerm(utils.ArchitectureAmd64, 1).Build()); got != tt.want {
+			if got := pod.shouldIgnorePod(); got != tt.want {
 				t.Errorf("shouldIgnorePod() = %v, want %v", got, tt.want)
 			}
 		})
@@ -1299,3 +1425,99 @@ func TestIsNodeSelectorConfiguredForArchitecture(t *testing.T) {
 		})
 	}
 }
+
+func TestPod_SetArchitectureTolerations(t *testing.T) {
+	plugin := &plugins.ArchitectureTolerations{
+		BasePlugin: plugins.BasePlugin{Enabled: true},
+		Platforms: []plugins.ArchitectureTaintsTerm{
+			{
+				Architecture: utils.ArchitectureArm64,
+				Taints: []plugins.ArchitectureTaint{
+					{Key: "multiarch.openshift.io/arch", Value: utils.ArchitectureArm64, Effect: v1.TaintEffectNoSchedule},
+				},
+			},
+			{
+				Architecture: utils.ArchitectureS390x,
+				Taints: []plugins.ArchitectureTaint{
+					{Key: "dedicated-s390x"},
+				},
+			},
+		},
+	}
+	arm64Toleration := v1.Toleration{
+		Key: "multiarch.openshift.io/arch", Operator: v1.TolerationOpEqual, Value: utils.ArchitectureArm64,
+		Effect: v1.TaintEffectNoSchedule,
+	}
+	tests := []struct {
+		name          string
+		pod           *v1.Pod
+		architectures []string
+		want          []v1.Toleration
+	}{
+		{
+			name:          "pod supporting an architecture with dedicated nodes",
+			pod:           NewPod().Build(),
+			architectures: []string{utils.ArchitectureAmd64, utils.ArchitectureArm64},
+			want:          []v1.Toleration{arm64Toleration},
+		},
+		{
+			name:          "pod supporting an architecture with dedicated nodes tainted with a key only",
+			pod:           NewPod().Build(),
+			architectures: []string{utils.ArchitectureS390x},
+			want:          []v1.Toleration{{Key: "dedicated-s390x", Operator: v1.TolerationOpExists}},
+		},
+		{
+			name:          "pod supporting no architecture with dedicated nodes",
+			pod:           NewPod().Build(),
+			architectures: []string{utils.ArchitectureAmd64},
+			want:          nil,
+		},
+		{
+			name:          "pod already tolerating the taint",
+			pod:           NewPod().WithTolerations(v1.Toleration{Operator: v1.TolerationOpExists}).Build(),
+			architectures: []string{utils.ArchitectureArm64},
+			want:          []v1.Toleration{{Operator: v1.TolerationOpExists}},
+		},
+		{
+			name: "pod with a user toleration for a different value of the taint",
+			pod: NewPod().WithTolerations(v1.Toleration{
+				Key: "multiarch.openshift.io/arch", Operator: v1.TolerationOpEqual, Value: utils.ArchitectureS390x,
+			}).Build(),
+			architectures: []string{utils.ArchitectureArm64},
+			want: []v1.Toleration{
+				{Key: "multiarch.openshift.io/arch", Operator: v1.TolerationOpEqual, Value: utils.ArchitectureS390x},
+				arm64Toleration,
+			},
+		},
+		{
+			name: "pod with a user toleration with the Equal operator for a taint tolerated by key only",
+			pod: NewPod().WithTolerations(v1.Toleration{
+				Key: "dedicated-s390x", Operator: v1.TolerationOpEqual,
+			}).Build(),
+			architectures: []string{utils.ArchitectureS390x},
+			want: []v1.Toleration{
+				{Key: "dedicated-s390x", Operator: v1.TolerationOpEqual},
+				{Key: "dedicated-s390x", Operator: v1.TolerationOpExists},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			pod := newPod(tt.pod, ctx, nil)
+			pod.SetArchitectureTolerations(tt.architectures, plugin)
+			g.Expect(pod.Spec.Tolerations).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPod_skippedImageVolumes(t *testing.T) {
+	g := NewGomegaWithT(t)
+	pod := newPod(NewPod().
+		WithImageVolume("plugins", "foo/plugins:latest", v1.PullIfNotPresent).
+		WithImageVolume("models", "foo/models:latest", v1.PullIfNotPresent).
+		WithImageVolume("config", "foo/config:latest", v1.PullIfNotPresent).
+		WithAnnotations(map[string]string{utils.ArchitectureAgnosticVolumesAnnotation: "models,config,missing"}).
+		Build(), ctx, nil)
+	g.Expect(pod.skippedImageVolumes()).To(Equal([]string{"config", "models"}))
+}
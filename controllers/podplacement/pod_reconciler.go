@@ -25,14 +25,19 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrl2 "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
 	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
 	"github.com/openshift/multiarch-tuning-operator/pkg/informers/clusterpodplacementconfig"
 	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
@@ -44,6 +49,11 @@ type PodReconciler struct {
 	Scheme    *runtime.Scheme
 	ClientSet *kubernetes.Clientset
 	Recorder  record.EventRecorder
+	// StatusReporter reports the statistics of the controller in the ClusterPodPlacementConfig status.
+	// It is optional.
+	StatusReporter *CPPCStatusReporter
+
+	placementSpecChanges chan event.TypedGenericEvent[*v1beta1.ClusterPodPlacementConfig]
 }
 
 // RBACs for the operands' controllers are added manually because kubebuilder can't handle multiple service accounts
@@ -53,6 +63,8 @@ type PodReconciler struct {
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=use
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=multiarch.openshift.io,resources=imagerewriterules,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -77,14 +89,18 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		log.V(2).Info("Unable to fetch pod", "error", err)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	pod.setLocalPluginsReader(r.Client)
 	// Pods without the scheduling gate should be ignored.
 	if !pod.HasSchedulingGate() {
 		log.V(2).Info("Pod does not have the scheduling gate. Ignoring...")
 		return ctrl.Result{}, nil
 	}
 	metrics.ProcessedPodsCtrl.Inc()
+	r.StatusReporter.IncProcessedPods()
 	defer utils.HistogramObserve(now, metrics.TimeToProcessGatedPod)
-	r.processPod(ctx, pod)
+	if r.processPod(ctx, pod) {
+		r.StatusReporter.IncInspectionErrors()
+	}
 	err := r.Update(ctx, pod.PodObject())
 	if err != nil {
 		log.Error(err, "Unable to update the pod")
@@ -94,40 +110,81 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	if !pod.HasSchedulingGate() {
 		// Only publish the event if the scheduling gate has been removed and the pod has been updated successfully.
 		pod.PublishEvent(corev1.EventTypeNormal, ArchitectureAwareSchedulingGateRemovalSuccess, SchedulingGateRemovalSuccessMsg)
-		metrics.GatedPodsGauge.Dec()
 	}
 	return ctrl.Result{}, nil
 }
 
-func (r *PodReconciler) processPod(ctx context.Context, pod *Pod) {
+// processPod sets the node affinity of the gated pod and removes the scheduling gate once it is processed. It returns
+// true if the inspection of the images of the pod failed.
+func (r *PodReconciler) processPod(ctx context.Context, pod *Pod) (inspectionFailed bool) {
 	log := ctrllog.FromContext(ctx)
 	log.V(1).Info("Processing pod")
 
 	cppc := clusterpodplacementconfig.GetClusterPodPlacementConfig()
-	if pod.shouldIgnorePod(cppc) {
+	if pod.shouldIgnorePod() {
 		log.V(3).Info("A pod with the scheduling gate should be ignored. Ignoring...")
 		// We can reach this branch when:
 		// - The pod has been gated but not processed before the operator changed configuration such that the pod should be ignored.
 		// - The pod has got some other changes in the admission chain from another webhook that makes it not suitable for processing anymore
-		//	(for example another actor set the nodeAffinity already for the kubernetes.io/arch label).
+		//	(for example another actor set the nodeName or a control plane nodeSelector).
 		// In both cases, we should just remove the scheduling gate.
 		log.V(1).Info("Removing the scheduling gate from pod.")
 		pod.RemoveSchedulingGate()
 		pod.PublishEvent(corev1.EventTypeWarning, ArchitectureAwareGatedPodIgnored, ArchitectureAwareGatedPodIgnoredMsg)
 		return
 	}
-
-	if cppc != nil && cppc.PluginsEnabled(common.NodeAffinityScoringPluginName) {
-		pod.SetPreferredArchNodeAffinity(cppc)
+	if pod.isConfigOutdated(cppc) {
+		// The pod was gated with a previous generation of the ClusterPodPlacementConfig: the decisions taken by the
+		// webhook are re-evaluated before processing the pod with the current one.
+		selected, err := r.realignWithConfig(ctx, pod, cppc)
+		if err != nil {
+			log.Error(err, "Unable to re-evaluate the pod with the current configuration")
+		} else if !selected {
+			log.V(1).Info("The namespace is no longer selected, removing the scheduling gate from pod.")
+			pod.RemoveSchedulingGate()
+			pod.PublishEvent(corev1.EventTypeNormal, ArchitectureAwareGatedPodOutOfScope, GatedPodOutOfScopeMsg)
+			return
+		}
 	}
 
+	// The containers may have been injected after the webhook recorded the decisions: refresh them before the inspection.
+	pod.setContainerDecisions(cppc)
 	// Prepare the requirement for the node affinity.
 	psdl, err := r.pullSecretDataList(ctx, pod)
 	pod.handleError(err, "Unable to retrieve the image pull secret data for the pod.")
+	var allowedArchitectures sets.Set[string]
+	if err == nil {
+		allowedArchitectures, err = r.allowedArchitectures(ctx, pod, cppc)
+		pod.handleError(err, "Unable to retrieve the architectures allowed for the pod.")
+	}
+	if err == nil {
+		// The images published as per-architecture variants are rewritten to the variants of a single architecture.
+		allowedArchitectures, err = r.rewriteImages(ctx, pod, psdl, allowedArchitectures, cppc)
+		pod.handleError(err, "Unable to apply the image rewrite rules to the pod.")
+		inspectionFailed = err != nil
+	}
 	// If no error occurred when retrieving the image pull secret data, set the node affinity.
 	if err == nil {
-		_, err = pod.SetNodeAffinityArchRequirement(psdl)
+		constraints := placementPlugins.OnConstrain(ctx, r, pod, cppc)
+		_, hadNoEligibleNodeLabel := pod.Labels[utils.NoEligibleNodeArchLabel]
+		var architectures []string
+		architectures, err = pod.SetNodeAffinityArchRequirement(psdl, allowedArchitectures,
+			constraints.nodeArchitectures, constraints.keepGatedUntilNodeAvailable)
 		pod.handleError(err, "Unable to set the node affinity for the pod.")
+		inspectionFailed = err != nil
+		if err == nil {
+			placementPlugins.OnInspected(pod, architectures, cppc)
+			if cppc != nil {
+				pod.pinImageDigests(cppc.Spec.ImageDigestPinning)
+			}
+		}
+		if _, ok := pod.Labels[utils.NoEligibleNodeArchLabel]; ok && !hadNoEligibleNodeLabel {
+			r.StatusReporter.IncPodsWithoutEligibleNodes()
+		}
+	}
+	if err == nil && pod.isWaitingForEligibleNode() {
+		log.V(1).Info("No node with a supported architecture is available. Keeping the scheduling gate.")
+		return
 	}
 	if pod.maxRetries() && err != nil {
 		// the number of retries is incremented in the handleError function when the error is not nil.
@@ -140,23 +197,32 @@ func (r *PodReconciler) processPod(ctx context.Context, pod *Pod) {
 	}
 	// If the pod has been processed successfully or the max retries have been reached, remove the scheduling gate.
 	if err == nil || pod.maxRetries() {
+		placementPlugins.OnUngate(pod, cppc)
 		if pod.Labels[utils.PreferredNodeAffinityLabel] == utils.LabelValueNotSet {
 			pod.PublishEvent(corev1.EventTypeNormal, ArchitectureAwareNodeAffinitySet,
 				ArchitecturePreferredPredicateSkippedMsg)
 		}
 
 		log.V(1).Info("Removing the scheduling gate from pod.")
+		pod.setConfigGeneration(cppc)
 		pod.RemoveSchedulingGate()
 	}
+	return inspectionFailed
 }
 
 // pullSecretDataList returns the list of secrets data for the given pod given its imagePullSecrets field
 func (r *PodReconciler) pullSecretDataList(ctx context.Context, pod *Pod) ([][]byte, error) {
+	return pullSecretDataList(ctx, r.ClientSet, pod)
+}
+
+// pullSecretDataList returns the list of secrets data for the given pod given its imagePullSecrets field.
+// It is shared by the pod placement controller and the webhook.
+func pullSecretDataList(ctx context.Context, clientSet *kubernetes.Clientset, pod *Pod) ([][]byte, error) {
 	log := ctrllog.FromContext(ctx)
 	secretAuths := make([][]byte, 0)
 	secretList := pod.getPodImagePullSecrets()
 	for _, pullsecret := range secretList {
-		secret, err := r.ClientSet.CoreV1().Secrets(pod.Namespace).Get(ctx, pullsecret, metav1.GetOptions{})
+		secret, err := clientSet.CoreV1().Secrets(pod.Namespace).Get(ctx, pullsecret, metav1.GetOptions{})
 		if err != nil {
 			log.Error(err, "Error getting secret", "secret", pullsecret)
 			continue
@@ -180,9 +246,19 @@ func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	ctrllog.FromContext(context.Background()).Info("Setting up the PodReconciler with the manager with max"+
 		" concurrent reconciles", "maxConcurrentReconciles", maxConcurrentReconciles)
 
+	// The gated pods are reconciled again when the placement configuration changes, to be processed consistently
+	// with the new one.
+	r.placementSpecChanges = make(chan event.TypedGenericEvent[*v1beta1.ClusterPodPlacementConfig], 1)
+	clusterpodplacementconfig.OnPlacementSpecChanged(r.notifyPlacementSpecChanged)
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Pod{}).WithOptions(ctrl2.Options{
 		MaxConcurrentReconciles: maxConcurrentReconciles,
 	}).
+		// The pods kept gated until a node with a supported architecture is available are reconciled again
+		// when the nodes change.
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.podsWaitingForEligibleNode),
+			builder.WithPredicates(nodeChangedPredicate)).
+		WatchesRawSource(source.Channel(r.placementSpecChanges, handler.TypedEnqueueRequestsFromMapFunc(r.gatedPods))).
 		Complete(r)
 }
@@ -27,6 +27,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
@@ -36,33 +37,48 @@ import (
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/hashicorp/golang-lru/v2/expirable"
 	"github.com/panjf2000/ants/v2"
 
-	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
 	"github.com/openshift/multiarch-tuning-operator/controllers/podplacement/metrics"
 	"github.com/openshift/multiarch-tuning-operator/pkg/informers/clusterpodplacementconfig"
 	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
 )
 
+// multiArchOwnersCacheTTL is the time the webhook remembers whether the owner of a pod runs on more than one
+// architecture.
+const multiArchOwnersCacheTTL = 10 * time.Minute
+
 // [disabled:operator]kubebuilder:webhook:path=/add-pod-scheduling-gate,mutating=true,sideEffects=None,admissionReviewVersions=v1,failurePolicy=ignore,groups="",resources=pods,verbs=create,versions=v1,name=pod-placement-scheduling-gate.multiarch.openshift.io
 
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=replicasets;statefulsets,verbs=get
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get
+
 // PodSchedulingGateMutatingWebHook annotates Pods
 type PodSchedulingGateMutatingWebHook struct {
 	client     client.Client
+	apiReader  client.Reader
 	clientSet  *kubernetes.Clientset
 	decoder    admission.Decoder
 	once       sync.Once
 	scheme     *runtime.Scheme
 	recorder   record.EventRecorder
 	workerPool *ants.MultiPool
+	// controllerLiveness reports whether the pod placement controller is alive and able to ungate the pods.
+	// When nil, the controller is always considered alive.
+	controllerLiveness *ControllerLivenessChecker
+	// multiArchOwners caches whether the controller owners of the pods run on more than one architecture.
+	multiArchOwners *expirable.LRU[types.UID, bool]
 }
 
-func (a *PodSchedulingGateMutatingWebHook) patchedPodResponse(pod *corev1.Pod, req admission.Request) admission.Response {
+func (a *PodSchedulingGateMutatingWebHook) patchedPodResponse(pod *corev1.Pod, req admission.Request,
+	warnings ...string) admission.Response {
 	marshaledPod, err := json.Marshal(pod)
 	if err != nil {
 		return admission.Errored(http.StatusInternalServerError, err)
 	}
-	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod).WithWarnings(warnings...)
 }
 
 func (a *PodSchedulingGateMutatingWebHook) Handle(ctx context.Context, req admission.Request) admission.Response {
@@ -79,42 +95,89 @@ func (a *PodSchedulingGateMutatingWebHook) Handle(ctx context.Context, req admis
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 	log := ctrllog.FromContext(ctx).WithValues("namespace", pod.Namespace, "name", pod.Name)
+	pod.setLocalPluginsReader(a.client)
 
+	// The pod placement controller trusts the annotation to skip the rewrite of the images: it is only set by the
+	// controller on the persisted pods, never by the clients creating them.
+	delete(pod.Annotations, utils.ImageRewriteArchitectureAnnotation)
 	cppc := clusterpodplacementconfig.GetClusterPodPlacementConfig()
-	if cppc != nil && cppc.PluginsEnabled(common.NodeAffinityScoringPluginName) {
-		pod.EnsureLabel(utils.PreferredNodeAffinityLabel, utils.LabelValueNotSet)
+	if pod.isGatedByWebhook() {
+		// The webhook is reinvoked because another mutating webhook changed the pod after us: the pod was already
+		// gated and only the decisions about the containers the other webhook may have injected are refreshed.
+		// The pod placement controller processes the gated pods anyway, even if the user set the gate and the label.
+		log.V(3).Info("Reinvoked for an already processed pod, refreshing the container decisions")
+		pod.setContainerDecisions(cppc)
+		return a.patchedPodResponse(pod.PodObject(), req)
+	}
+	pod.setContainerDecisions(cppc)
+	for _, failure := range placementPlugins.OnAdmit(pod, cppc) {
+		a.delayedEvent(ctx, pod.DeepCopy(), corev1.EventTypeWarning, ArchitectureAwarePluginError, failure)
 	}
 	pod.EnsureLabel(utils.NodeAffinityLabel, utils.LabelValueNotSet)
 	pod.EnsureLabel(utils.SchedulingGateLabel, utils.LabelValueNotSet)
 
-	if pod.shouldIgnorePod(cppc) {
+	if pod.shouldIgnorePod() {
 		log.V(3).Info("Ignoring the pod")
 		return a.patchedPodResponse(pod.PodObject(), req)
 	}
 
+	// The pods whose architecture is selected by the user are gated to verify the selection against their images.
+	// The emulation fallback and the architecture spread do not apply to them.
+	architectureSelectedByUser := pod.isNodeSelectorConfiguredForArchitecture()
+	var warnings []string
+	if architectureSelectedByUser {
+		warnings = a.architectureSelectionWarnings(ctx, pod)
+	}
+
+	// The pods already labeled as emulated are processed by the plugins again, to verify their emulation fallback when
+	// the webhook is reinvoked.
+	if !architectureSelectedByUser || pod.isEmulated() {
+		// The pods fully placed at admission time by a plugin do not need to be gated.
+		placed, failures := placementPlugins.OnPlace(ctx, a, pod, cppc)
+		for _, failure := range failures {
+			a.delayedEvent(ctx, pod.DeepCopy(), corev1.EventTypeWarning, ArchitectureAwarePluginError, failure)
+		}
+		if placed {
+			return a.patchedPodResponse(pod.PodObject(), req)
+		}
+	}
+
+	if !a.controllerLiveness.IsHealthy() {
+		// No pod placement controller would remove the scheduling gate: gating the pod would leave it pending
+		// until the controller recovers. We let the pod through, without the scheduling gate.
+		log.Info("The pod placement controller is not available, the pod will not be gated")
+		pod.EnsureLabel(utils.PodPlacementControllerUnavailableLabel, utils.True)
+		a.delayedEvent(ctx, pod.DeepCopy(), corev1.EventTypeWarning, ArchitectureAwarePodPlacementControllerUnavailable,
+			PodPlacementControllerUnavailableMsg)
+		metrics.PodsNotGatedControllerUnavailable.Inc()
+		return a.patchedPodResponse(pod.PodObject(), req, warnings...)
+	}
+
 	pod.ensureSchedulingGate()
 	// We also add a label to the pod to indicate that the scheduling gate was added
 	// and this pod expects processing by the operator. That's useful for testing and debugging, but also gives the user
 	// an indication that the pod is waiting for processing and can support kubectl queries to find out which pods are
 	// waiting for processing, for example when the operator is being uninstalled.
 	pod.Labels[utils.SchedulingGateLabel] = utils.SchedulingGateLabelValueGated
+	// The generation of the configuration is recorded for the controller to detect the changes since the admission.
+	pod.setConfigGeneration(cppc)
 	// we don't care about this goroutine, it's informational,
 	// we know it will finish eventually by design, and we don't need to block the response as we
 	// are right in the admission pipeline, before the pod is persisted.
 	log.V(3).Info("Scheduling gate added to the pod, launching the event creation goroutine")
-	a.delayedSchedulingGatedEvent(ctx, pod.DeepCopy())
+	a.delayedEvent(ctx, pod.DeepCopy(), corev1.EventTypeNormal, ArchitectureAwareSchedulingGateAdded, SchedulingGateAddedMsg)
 	metrics.GatedPods.Inc()
-	metrics.GatedPodsGauge.Inc()
 	log.V(2).Info("Accepting pod")
-	return a.patchedPodResponse(pod.PodObject(), req)
+	return a.patchedPodResponse(pod.PodObject(), req, warnings...)
 }
 
-func (a *PodSchedulingGateMutatingWebHook) delayedSchedulingGatedEvent(ctx context.Context, pod *corev1.Pod) {
+// delayedEvent publishes an event for a pod that is being admitted, once the pod is persisted by the API server.
+func (a *PodSchedulingGateMutatingWebHook) delayedEvent(ctx context.Context, pod *corev1.Pod, eventType, reason, message string) {
 	err := a.workerPool.Submit(func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 		defer cancel()
 		log := ctrllog.FromContext(ctx).WithValues("namespace", pod.Namespace, "name", pod.Name,
-			"function", "delayedSchedulingGatedEvent")
+			"function", "delayedEvent")
 		// We try to get the pod from the API with exponential backoff until we find it or a timeout is reached
 		err := wait.ExponentialBackoff(wait.Backoff{
 			// The maximum time, excluding the time for the execution of the request,
@@ -128,7 +191,7 @@ func (a *PodSchedulingGateMutatingWebHook) delayedSchedulingGatedEvent(ctx conte
 			createdPod, err := a.clientSet.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
 			if err == nil {
 				log.V(2).Info("Pod was found", "namespace", pod.Namespace, "name", pod.Name)
-				a.recorder.Event(createdPod, corev1.EventTypeNormal, ArchitectureAwareSchedulingGateAdded, SchedulingGateAddedMsg)
+				a.recorder.Event(createdPod, eventType, reason, message)
 				// Pod was found, return true to stop retrying
 				return true, nil
 			}
@@ -142,24 +205,28 @@ func (a *PodSchedulingGateMutatingWebHook) delayedSchedulingGatedEvent(ctx conte
 			return false, err
 		})
 		if err != nil {
-			log.V(2).Info("Failed to get a scheduling gated Pod after retries",
+			log.V(2).Info("Failed to get the Pod after retries",
 				"error", err)
 		}
 	})
 	if err != nil {
 		ctrllog.FromContext(ctx).WithValues("namespace", pod.Namespace, "name", pod.Name,
-			"function", "delayedSchedulingGatedEvent").Error(err, "Failed to submit the delayedSchedulingGatedEvent job")
+			"function", "delayedEvent").Error(err, "Failed to submit the delayedEvent job")
 	}
 }
 
-func NewPodSchedulingGateMutatingWebHook(client client.Client, clientSet *kubernetes.Clientset,
-	scheme *runtime.Scheme, recorder record.EventRecorder, workerPool *ants.MultiPool) *PodSchedulingGateMutatingWebHook {
+func NewPodSchedulingGateMutatingWebHook(client client.Client, apiReader client.Reader, clientSet *kubernetes.Clientset,
+	scheme *runtime.Scheme, recorder record.EventRecorder, workerPool *ants.MultiPool,
+	controllerLiveness *ControllerLivenessChecker) *PodSchedulingGateMutatingWebHook {
 	a := &PodSchedulingGateMutatingWebHook{
-		client:     client,
-		clientSet:  clientSet,
-		scheme:     scheme,
-		recorder:   recorder,
-		workerPool: workerPool,
+		client:             client,
+		apiReader:          apiReader,
+		clientSet:          clientSet,
+		scheme:             scheme,
+		recorder:           recorder,
+		workerPool:         workerPool,
+		controllerLiveness: controllerLiveness,
+		multiArchOwners:    expirable.NewLRU[types.UID, bool](1024, nil, multiArchOwnersCacheTTL),
 	}
 	metrics.InitWebhookMetrics()
 	return a
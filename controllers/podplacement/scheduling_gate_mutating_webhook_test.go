@@ -10,6 +10,7 @@ import (
 
 	"github.com/openshift/multiarch-tuning-operator/pkg/testing/builder"
 	"github.com/openshift/multiarch-tuning-operator/pkg/testing/image/fake/registry"
+	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
 )
 
 var _ = Describe("Controllers/PodPlacement/scheduling_gate_mutating_webhook", func() {
@@ -26,6 +27,18 @@ var _ = Describe("Controllers/PodPlacement/scheduling_gate_mutating_webhook", fu
 				err := k8sClient.Create(ctx, pod)
 				Expect(err).NotTo(HaveOccurred(), "failed to create the pod", err)
 			})
+			It("should strip the image rewrite architecture annotation set by the client", func() {
+				pod := builder.NewPod().
+					WithContainersImages(fmt.Sprintf("%s/%s/%s:latest", registryAddress,
+						registry.PublicRepo, registry.ComputeNameByMediaType(imgspecv1.MediaTypeImageIndex))).
+					WithGenerateName("test-pod-").
+					WithNamespace("test-namespace").
+					WithAnnotations(map[string]string{utils.ImageRewriteArchitectureAnnotation: utils.ArchitectureArm64}).
+					Build()
+				err := k8sClient.Create(ctx, pod)
+				Expect(err).NotTo(HaveOccurred(), "failed to create the pod", err)
+				Expect(pod.Annotations).NotTo(HaveKey(utils.ImageRewriteArchitectureAnnotation))
+			})
 		})
 	})
 })
@@ -342,7 +342,7 @@ func runManager() {
 	Expect(err).NotTo(HaveOccurred())
 	mgr.GetWebhookServer().Register("/add-pod-scheduling-gate", &webhook.Admission{
 		Handler: NewPodSchedulingGateMutatingWebHook(
-			mgr.GetClient(), clientset, mgr.GetScheme(), mgr.GetEventRecorderFor(utils.OperatorName), pool),
+			mgr.GetClient(), mgr.GetAPIReader(), clientset, mgr.GetScheme(), mgr.GetEventRecorderFor(utils.OperatorName), pool, nil),
 	})
 
 	policyConfig := []byte(`{"default":[{"type":"insecureAcceptAnything"}],"transports":{"atomic":{},"docker":{},"docker-daemon":{"":[{"type":"insecureAcceptAnything"}]}}}`)
@@ -44,6 +44,10 @@ func (w *PodPlacementConfigWebhook) Handle(ctx context.Context, req admission.Re
 			if ok, err := newPPC.Spec.Plugins.NodeAffinityScoring.ValidateArchitecturesSet(); !ok {
 				return admission.Denied(err.Error())
 			}
+			if newPPC.Spec.Plugins.NodeAffinityScoring.IsDynamic() {
+				return admission.Denied("the Dynamic mode of the nodeAffinityScoring plugin is only supported " +
+					"in the ClusterPodPlacementConfig")
+			}
 		}
 
 		// List existing PodPlacementConfigs in the same namespace
@@ -20,56 +20,134 @@ import (
 	"context"
 	"encoding/hex"
 	"hash/fnv"
+	"maps"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/openshift/multiarch-tuning-operator/pkg/image/metrics"
 	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
 
+	"github.com/containers/image/v5/docker/reference"
 	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/opencontainers/go-digest"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// Inspection is the result of the inspection of an image reference: the architectures it supports and the digest of
+// the manifest, or of the index, they were computed from.
+type Inspection struct {
+	Architectures sets.Set[string]
+	Digest        digest.Digest
+}
+
 type cacheProxy struct {
 	registryInspector IRegistryInspector
-	imageRefsCache    *expirable.LRU[string, sets.Set[string]] // LRU cache with expirable keys
+	// imageRefsCache stores the architectures and the digest of an inspection as a single entry, so that the digest
+	// is always the one of the manifest the architectures were computed from.
+	imageRefsCache *expirable.LRU[string, Inspection] // LRU cache with expirable keys
+
+	hits   atomic.Int64
+	misses atomic.Int64
+	// failuresByRegistry counts the failed inspections by registry host.
+	failuresByRegistry     map[string]int64
+	failuresByRegistryLock sync.Mutex
+}
+
+// CacheStats are the cumulative statistics of the image inspections since the controller started.
+type CacheStats struct {
+	// Size is the number of images currently cached.
+	Size int
+	// Hits and Misses are the numbers of inspections served by the cache and by the registries.
+	Hits   int64
+	Misses int64
+	// FailuresByRegistry are the numbers of failed inspections by registry host.
+	FailuresByRegistry map[string]int64
 }
 
 func (c *cacheProxy) GetCompatibleArchitecturesSet(ctx context.Context, imageReference string,
 	skipCache bool, secrets [][]byte) (sets.Set[string], error) {
+	inspection, err := c.inspectImage(ctx, imageReference, skipCache, secrets)
+	if err != nil {
+		return nil, err
+	}
+	return inspection.Architectures, nil
+}
+
+// inspectImage returns the architectures and the digest of the image reference, from the cache unless skipCache is
+// true. The result of each inspection of the registry replaces the cached one.
+func (c *cacheProxy) inspectImage(ctx context.Context, imageReference string, skipCache bool,
+	secrets [][]byte) (Inspection, error) {
 	metrics.InitCommonMetrics()
 	metrics.InspectionGauge.Set(float64(c.imageRefsCache.Len()))
 	now := time.Now()
 	authJSON, err := marshaledImagePullSecrets(imageReference, secrets)
 	if err != nil {
-		return nil, err
+		return Inspection{}, err
 	}
 
 	log := ctrllog.FromContext(ctx).WithValues("imageReference", imageReference)
 	hash := computeFNV128Hash(imageReference, authJSON)
-	if architectures, ok := c.imageRefsCache.Get(hash); ok && !skipCache {
-		log.V(3).Info("Cache hit", "architectures", architectures, "hash", hash)
+	if inspection, ok := c.imageRefsCache.Get(hash); ok && !skipCache {
+		log.V(3).Info("Cache hit", "architectures", inspection.Architectures, "hash", hash)
+		c.hits.Add(1)
 		defer utils.HistogramObserve(now, metrics.TimeToInspectImageGivenHit)
-		return architectures, nil
+		return inspection, nil
 	}
-	architectures, err := c.registryInspector.GetCompatibleArchitecturesSet(ctx, imageReference, true, secrets)
+	c.misses.Add(1)
+	architectures, manifestDigest, err := c.registryInspector.inspect(ctx, imageReference, secrets)
 	if err != nil {
-		return nil, err
+		c.recordFailure(imageReference)
+		return Inspection{}, err
 	}
-
+	inspection := Inspection{Architectures: architectures, Digest: manifestDigest}
+	// The inspections skipping the cache refresh it too: the cached entry always reflects the latest inspection.
 	log.V(3).Info("Cache miss...adding to cache", "architectures", architectures, "hash", hash)
-	if !skipCache {
-		c.imageRefsCache.Add(hash, architectures)
-	}
+	c.imageRefsCache.Add(hash, inspection)
 	defer utils.HistogramObserve(now, metrics.TimeToInspectImageGivenMiss)
-	return architectures, nil
+	return inspection, nil
+}
+
+// getCachedCompatibleArchitecturesSet returns the architectures of the image reference, only if they are already in
+// the cache. It never queries the registry.
+func (c *cacheProxy) getCachedCompatibleArchitecturesSet(imageReference string, secrets [][]byte) (sets.Set[string], bool) {
+	authJSON, err := marshaledImagePullSecrets(imageReference, secrets)
+	if err != nil {
+		return nil, false
+	}
+	inspection, ok := c.imageRefsCache.Get(computeFNV128Hash(imageReference, authJSON))
+	return inspection.Architectures, ok
 }
 
 func (c *cacheProxy) GetRegistryInspector() IRegistryInspector {
 	return c.registryInspector
 }
 
+// recordFailure counts a failed inspection for the registry of the image reference.
+func (c *cacheProxy) recordFailure(imageReference string) {
+	registry := "unknown"
+	if named, err := reference.ParseNormalizedNamed(imageReference); err == nil {
+		registry = reference.Domain(named)
+	}
+	c.failuresByRegistryLock.Lock()
+	defer c.failuresByRegistryLock.Unlock()
+	c.failuresByRegistry[registry]++
+}
+
+// stats returns the cumulative statistics of the inspections.
+func (c *cacheProxy) stats() CacheStats {
+	c.failuresByRegistryLock.Lock()
+	defer c.failuresByRegistryLock.Unlock()
+	return CacheStats{
+		Size:               c.imageRefsCache.Len(),
+		Hits:               c.hits.Load(),
+		Misses:             c.misses.Load(),
+		FailuresByRegistry: maps.Clone(c.failuresByRegistry),
+	}
+}
+
 // clearCache purges the image metadata cache
 func (c *cacheProxy) clearCache() {
 	c.imageRefsCache.Purge()
@@ -77,8 +155,9 @@ func (c *cacheProxy) clearCache() {
 
 func newCacheProxy() *cacheProxy {
 	return &cacheProxy{
-		registryInspector: newRegistryInspector(),
-		imageRefsCache:    expirable.NewLRU[string, sets.Set[string]](256, nil, time.Hour*6),
+		registryInspector:  newRegistryInspector(),
+		imageRefsCache:     expirable.NewLRU[string, Inspection](256, nil, time.Hour*6),
+		failuresByRegistry: map[string]int64{},
 	}
 }
 
@@ -0,0 +1,70 @@
+package image
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/opencontainers/go-digest"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// sequenceInspector returns a new digest at each inspection, emulating a tag moved between the inspections.
+type sequenceInspector struct {
+	IRegistryInspector
+	architectures []sets.Set[string]
+	inspections   int
+}
+
+func (i *sequenceInspector) inspect(_ context.Context, _ string, _ [][]byte) (sets.Set[string], digest.Digest, error) {
+	architectures := i.architectures[i.inspections%len(i.architectures)]
+	i.inspections++
+	return architectures, digest.FromString(sets.List(architectures)[0]), nil
+}
+
+func Test_cacheProxy_inspectImage(t *testing.T) {
+	inspector := &sequenceInspector{
+		architectures: []sets.Set[string]{sets.New("amd64"), sets.New("arm64")},
+	}
+	c := &cacheProxy{
+		registryInspector:  inspector,
+		imageRefsCache:     expirable.NewLRU[string, Inspection](256, nil, time.Hour),
+		failuresByRegistry: map[string]int64{},
+	}
+	imageReference := "//quay.io/library/image:latest"
+
+	first, err := c.inspectImage(context.Background(), imageReference, false, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cached, err := c.inspectImage(context.Background(), imageReference, false, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if inspector.inspections != 1 || !cached.Architectures.Equal(first.Architectures) || cached.Digest != first.Digest {
+		t.Errorf("Expected the second inspection to be served by the cache, got %+v", cached)
+	}
+
+	refreshed, err := c.inspectImage(context.Background(), imageReference, true, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if inspector.inspections != 2 || refreshed.Digest == first.Digest {
+		t.Errorf("Expected the inspection skipping the cache to query the registry, got %+v", refreshed)
+	}
+	if refreshed.Digest != digest.FromString(sets.List(refreshed.Architectures)[0]) {
+		t.Errorf("Expected the digest of the inspection that computed the architectures, got %+v", refreshed)
+	}
+	architectures, ok := c.getCachedCompatibleArchitecturesSet(imageReference, nil)
+	if !ok || !architectures.Equal(refreshed.Architectures) {
+		t.Errorf("Expected the cache to hold the latest inspection, got %v", architectures)
+	}
+	cached, err = c.inspectImage(context.Background(), imageReference, false, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !cached.Architectures.Equal(refreshed.Architectures) || cached.Digest != refreshed.Digest {
+		t.Errorf("Expected the architectures and the digest to be cached together, got %+v", cached)
+	}
+}
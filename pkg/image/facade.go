@@ -33,12 +33,33 @@ type Facade struct {
 	inspectionCache       ICache
 	storeGlobalPullSecret func(pullSecret []byte)
 	clearCache            func()
+	getCached             func(imageReference string, secrets [][]byte) (sets.Set[string], bool)
+	inspectImage          func(ctx context.Context, imageReference string, skipCache bool, secrets [][]byte) (Inspection, error)
+	stats                 func() CacheStats
 }
 
 func (i *Facade) GetCompatibleArchitecturesSet(ctx context.Context, imageReference string, skipCache bool, secrets [][]byte) (architectures sets.Set[string], err error) {
 	return i.inspectionCache.GetCompatibleArchitecturesSet(ctx, imageReference, skipCache, secrets)
 }
 
+// GetCachedCompatibleArchitecturesSet returns the architectures of the image reference if they are already known,
+// without inspecting the image.
+func (i *Facade) GetCachedCompatibleArchitecturesSet(imageReference string, secrets [][]byte) (sets.Set[string], bool) {
+	return i.getCached(imageReference, secrets)
+}
+
+// InspectImage returns the compatible architectures of the image reference together with the digest of the
+// manifest, or of the index for manifest-list images, they were computed from.
+func (i *Facade) InspectImage(ctx context.Context, imageReference string, skipCache bool,
+	secrets [][]byte) (Inspection, error) {
+	return i.inspectImage(ctx, imageReference, skipCache, secrets)
+}
+
+// Stats returns the cumulative statistics of the image inspections.
+func (i *Facade) Stats() CacheStats {
+	return i.stats()
+}
+
 func (i *Facade) StoreGlobalPullSecret(pullSecret []byte) {
 	i.storeGlobalPullSecret(pullSecret)
 	i.clearCache()
@@ -50,6 +71,9 @@ func newImageFacade() *Facade {
 		inspectionCache:       inspectionCache,
 		storeGlobalPullSecret: inspectionCache.registryInspector.storeGlobalPullSecret,
 		clearCache:            inspectionCache.clearCache,
+		getCached:             inspectionCache.getCachedCompatibleArchitecturesSet,
+		inspectImage:          inspectionCache.inspectImage,
+		stats:                 inspectionCache.stats,
 	}
 }
 
@@ -72,6 +72,13 @@ type registryInspector struct {
 // If the image is an operator bundle image, it will return an empty set. This is because operator bundle images
 // are not tied to a specific architecture, and we should not set any constraints based on the architecture they report.
 func (i *registryInspector) GetCompatibleArchitecturesSet(ctx context.Context, imageReference string, _ bool, secrets [][]byte) (supportedArchitectures sets.Set[string], err error) {
+	supportedArchitectures, _, err = i.inspect(ctx, imageReference, secrets)
+	return supportedArchitectures, err
+}
+
+// inspect returns the set of compatible architectures of the imageReference, as GetCompatibleArchitecturesSet does,
+// and the digest of the manifest (or of the index, for manifest-list images) it inspected.
+func (i *registryInspector) inspect(ctx context.Context, imageReference string, secrets [][]byte) (supportedArchitectures sets.Set[string], manifestDigest digest.Digest, err error) {
 	// Create the auth file
 	log := ctrllog.FromContext(ctx, "imageReference", imageReference)
 	i.mutex.RLock()
@@ -80,7 +87,7 @@ func (i *registryInspector) GetCompatibleArchitecturesSet(ctx context.Context, i
 	authFile, err := i.createAuthFile(imageReference, append([][]byte{globalPullSecret}, secrets...)...)
 	if err != nil {
 		log.Error(err, "Couldn't write auth file")
-		return nil, err
+		return nil, "", err
 	} else {
 		defer func(f *os.File) {
 			if err := f.Close(); err != nil {
@@ -97,7 +104,7 @@ func (i *registryInspector) GetCompatibleArchitecturesSet(ctx context.Context, i
 	imageReference, err = parseImageReference(imageReference)
 	if err != nil {
 		log.Error(err, "Couldn't parse image reference")
-		return nil, err
+		return nil, "", err
 	}
 
 	sys := &types.SystemContext{
@@ -113,7 +120,7 @@ func (i *registryInspector) GetCompatibleArchitecturesSet(ctx context.Context, i
 	src, err := resolveAndOpenImageSource(ctx, sys, imageReference)
 	if err != nil {
 		log.Error(err, "Error creating the image source")
-		return nil, err
+		return nil, "", err
 	}
 	defer func(src types.ImageSource) {
 		err := src.Close()
@@ -124,17 +131,22 @@ func (i *registryInspector) GetCompatibleArchitecturesSet(ctx context.Context, i
 	rawManifest, _, err := src.GetManifest(ctx, nil)
 	if err != nil {
 		log.Error(err, "Error getting the image manifest: %v")
-		return nil, err
+		return nil, "", err
+	}
+	manifestDigest, err = manifest.Digest(rawManifest)
+	if err != nil {
+		log.Error(err, "Error computing the digest of the image manifest")
+		return nil, "", err
 	}
 	policy, err := signature.DefaultPolicy(sys)
 	if err != nil {
 		log.Error(err, "Error loading the systemContext's policy")
-		return nil, err
+		return nil, "", err
 	}
 	policyCtx, err := signature.NewPolicyContext(policy)
 	if err != nil {
 		log.Error(err, "Error creating the PolicyContext")
-		return nil, err
+		return nil, "", err
 	}
 
 	supportedArchitectures = sets.New[string]()
@@ -143,7 +155,7 @@ func (i *registryInspector) GetCompatibleArchitecturesSet(ctx context.Context, i
 		index, err := manifest.OCI1IndexFromManifest(rawManifest)
 		if err != nil {
 			log.Error(err, "Error parsing the OCI index from the raw manifest of the image")
-			return nil, err
+			return nil, "", err
 		}
 		for _, m := range index.Manifests {
 			supportedArchitectures = sets.Insert(supportedArchitectures, m.Platform.Architecture)
@@ -167,23 +179,23 @@ func (i *registryInspector) GetCompatibleArchitecturesSet(ctx context.Context, i
 			// false and valid error
 			log.V(3).Info("The signature policy JSON file configuration does not allow inspecting this image",
 				"validationError", e)
-			return nil, e
+			return nil, "", e
 		}
 		log.Error(err, "Unable to perform the signature validation")
-		return nil, err
+		return nil, "", err
 	}
 
 	parsedImage, err := image.FromUnparsedImage(ctx, sys, unparsedImage)
 	if err != nil {
 		log.Error(err, "Error parsing the manifest of the image")
-		return nil, err
+		return nil, "", err
 	}
 
 	config, err := parsedImage.OCIConfig(ctx)
 
 	if err != nil {
 		log.Error(err, "Error parsing the OCI config of the image")
-		return nil, err
+		return nil, "", err
 	}
 	if isBundleImage(config.Config) {
 		log.V(3).Info("The image is an operator bundle image")
@@ -192,14 +204,14 @@ func (i *registryInspector) GetCompatibleArchitecturesSet(ctx context.Context, i
 		// We return the full set of supported architectures so that the intersection with the node architecture set
 		// does not change later.
 		// See https://issues.redhat.com/browse/OCPBUGS-38823 for more information.
-		return utils.AllSupportedArchitecturesSet(), nil
+		return utils.AllSupportedArchitecturesSet(), manifestDigest, nil
 	}
 
 	if !manifest.MIMETypeIsMultiImage(manifest.GuessMIMEType(rawManifest)) {
 		log.V(3).Info("The image is not a manifest list... getting the supported architecture")
-		return sets.New[string](config.Architecture), nil
+		return sets.New[string](config.Architecture), manifestDigest, nil
 	}
-	return supportedArchitectures, nil
+	return supportedArchitectures, manifestDigest, nil
 }
 
 // parseImageReference normalizes an imageName into a reference suitable for use
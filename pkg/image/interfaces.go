@@ -19,6 +19,7 @@ package image
 import (
 	"context"
 
+	"github.com/opencontainers/go-digest"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -34,4 +35,7 @@ type IRegistryInspector interface {
 	// in charge of watching the global pull secret and to store it in the ImageFacade's relevant private field.
 	// Then, the ImageFacade will be responsible for consuming it during the inspection.
 	storeGlobalPullSecret(pullSecret []byte)
+	// inspect returns the set of architectures that are compatible with the image reference and the digest of the
+	// manifest, or of the index, that was inspected.
+	inspect(ctx context.Context, imageReference string, secrets [][]byte) (sets.Set[string], digest.Digest, error)
 }
@@ -2,12 +2,16 @@ package clusterpodplacementconfig
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 
 	"github.com/go-logr/logr"
 	multiarchv1beta1 "github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
 	"github.com/openshift/multiarch-tuning-operator/pkg/informers/clusterpodplacementconfig/internal"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -107,9 +111,53 @@ func (s *CPPCSyncer) onUpdate() func(oldObj, newObj interface{}) {
 		}
 
 		s.onAdd()(newobj)
+		if PlacementSpecChanged(oldConfig, newConfig) {
+			s.log.Info("The placement configuration changed, notifying the handlers", "generation", newConfig.Generation)
+			internal.NotifyPlacementSpecChanged(newConfig)
+		}
 	}
 }
 
+// PlacementSpecChanged returns true if the spec of the ClusterPodPlacementConfig changed in the fields that drive the
+// placement of the pods. The changes of the fields that only configure the operand workloads, the scheduling gate
+// deadline, the validating policy and the pausing of the operand are ignored.
+func PlacementSpecChanged(oldConfig, newConfig *multiarchv1beta1.ClusterPodPlacementConfig) bool {
+	if oldConfig.Generation == newConfig.Generation {
+		return false
+	}
+	return !equality.Semantic.DeepEqual(placementSpec(oldConfig), placementSpec(newConfig))
+}
+
+// PlacementSpecHash returns the hash of the fields of the spec of the ClusterPodPlacementConfig compared by
+// PlacementSpecChanged. It is recorded in the pods to detect the placement configuration changes since their admission.
+func PlacementSpecHash(config *multiarchv1beta1.ClusterPodPlacementConfig) string {
+	// The spec only holds structs, slices and maps with string keys: the marshaling does not fail and json sorts the
+	// keys of the maps.
+	data, _ := json.Marshal(placementSpec(config))
+	hash := fnv.New64a()
+	hash.Write(data)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func placementSpec(config *multiarchv1beta1.ClusterPodPlacementConfig) *multiarchv1beta1.ClusterPodPlacementConfigSpec {
+	spec := config.Spec.DeepCopy()
+	spec.LogVerbosity = ""
+	spec.GlobalPullSecretRef = nil
+	spec.CABundleConfigMapRef = nil
+	spec.TLSMode = ""
+	spec.Operands = nil
+	spec.Paused = false
+	spec.SchedulingGateDeadline = nil
+	spec.ValidatingPolicy = nil
+	return spec
+}
+
+// OnPlacementSpecChanged registers a handler invoked with a copy of the new ClusterPodPlacementConfig every time the
+// placement configuration changes. The handlers run in the informer goroutine and must not block.
+func OnPlacementSpecChanged(handler func(config *multiarchv1beta1.ClusterPodPlacementConfig)) {
+	internal.AddPlacementSpecChangedHandler(handler)
+}
+
 // GetClusterPodPlacementConfig provides access to the stored config.
 func GetClusterPodPlacementConfig() *multiarchv1beta1.ClusterPodPlacementConfig {
 	return internal.GetClusterPodPlacementConfig()
@@ -0,0 +1,93 @@
+package clusterpodplacementconfig
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
+	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
+	multiarchv1beta1 "github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+)
+
+func TestPlacementSpecChanged(t *testing.T) {
+	tests := []struct {
+		name           string
+		mutate         func(spec *multiarchv1beta1.ClusterPodPlacementConfigSpec)
+		sameGeneration bool
+		want           bool
+	}{
+		{
+			name: "namespace selector changed",
+			mutate: func(spec *multiarchv1beta1.ClusterPodPlacementConfigSpec) {
+				spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"placement": "enabled"}}
+			},
+			want: true,
+		},
+		{
+			name: "plugin enabled",
+			mutate: func(spec *multiarchv1beta1.ClusterPodPlacementConfigSpec) {
+				spec.Plugins = &plugins.Plugins{
+					NodeAffinityScoring: &plugins.NodeAffinityScoring{BasePlugin: plugins.BasePlugin{Enabled: true}},
+				}
+			},
+			want: true,
+		},
+		{
+			name: "operand settings changed",
+			mutate: func(spec *multiarchv1beta1.ClusterPodPlacementConfigSpec) {
+				spec.LogVerbosity = common.LogVerbosityLevelDebug
+				spec.TLSMode = multiarchv1beta1.TLSModeSelfManaged
+				spec.GlobalPullSecretRef = &corev1.SecretReference{Name: "pull-secret"}
+				spec.CABundleConfigMapRef = &corev1.LocalObjectReference{Name: "ca-bundle"}
+				spec.Operands = &multiarchv1beta1.Operands{
+					PodPlacementWebhook: &multiarchv1beta1.OperandDeploymentConfig{Replicas: ptr.To[int32](3)},
+				}
+			},
+			want: false,
+		},
+		{
+			name: "scheduling gate deadline changed",
+			mutate: func(spec *multiarchv1beta1.ClusterPodPlacementConfigSpec) {
+				spec.SchedulingGateDeadline = &metav1.Duration{Duration: 5 * time.Minute}
+			},
+			want: false,
+		},
+		{
+			name: "validating policy changed",
+			mutate: func(spec *multiarchv1beta1.ClusterPodPlacementConfigSpec) {
+				spec.ValidatingPolicy = &multiarchv1beta1.ValidatingPolicy{Mode: multiarchv1beta1.RequireMultiArchImages}
+			},
+			want: false,
+		},
+		{
+			name: "same generation",
+			mutate: func(spec *multiarchv1beta1.ClusterPodPlacementConfigSpec) {
+				spec.NamespaceSelector = &metav1.LabelSelector{}
+			},
+			sameGeneration: true,
+			want:           false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			oldConfig := &multiarchv1beta1.ClusterPodPlacementConfig{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+			newConfig := oldConfig.DeepCopy()
+			if !tt.sameGeneration {
+				newConfig.Generation++
+			}
+			tt.mutate(&newConfig.Spec)
+			g.Expect(PlacementSpecChanged(oldConfig, newConfig)).To(Equal(tt.want))
+			if !tt.sameGeneration {
+				g.Expect(PlacementSpecHash(oldConfig) != PlacementSpecHash(newConfig)).To(Equal(tt.want),
+					"the placement spec hash should change with the placement spec")
+			}
+		})
+	}
+}
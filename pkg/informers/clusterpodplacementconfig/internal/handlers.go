@@ -0,0 +1,26 @@
+package internal
+
+import (
+	"sync"
+
+	multiarchv1beta1 "github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
+)
+
+var (
+	handlersMu                   sync.RWMutex
+	placementSpecChangedHandlers []func(config *multiarchv1beta1.ClusterPodPlacementConfig)
+)
+
+func AddPlacementSpecChangedHandler(handler func(config *multiarchv1beta1.ClusterPodPlacementConfig)) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	placementSpecChangedHandlers = append(placementSpecChangedHandlers, handler)
+}
+
+func NotifyPlacementSpecChanged(config *multiarchv1beta1.ClusterPodPlacementConfig) {
+	handlersMu.RLock()
+	defer handlersMu.RUnlock()
+	for _, handler := range placementSpecChangedHandlers {
+		handler(config.DeepCopy())
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2025 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package servingcerts issues the CA and the serving certificates of the operand Services when neither the OpenShift
+// service CA operator nor cert-manager provisions them.
+package servingcerts
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	certificatePEMType = "CERTIFICATE"
+	privateKeyPEMType  = "EC PRIVATE KEY"
+	// clockSkew backdates the certificates to tolerate the clock skew between the nodes.
+	clockSkew = 5 * time.Minute
+)
+
+// KeyPair is a certificate with its private key.
+type KeyPair struct {
+	Certificate *x509.Certificate
+	Key         *ecdsa.PrivateKey
+}
+
+// NewCA generates a self-signed CA valid for the given duration.
+func NewCA(commonName string, now time.Time, validity time.Duration) (*KeyPair, error) {
+	return newKeyPair(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil)
+}
+
+// NewServingCertificate generates a serving certificate for the given DNS names, signed by the CA. The certificate
+// does not outlive the CA.
+func (ca *KeyPair) NewServingCertificate(dnsNames []string, now time.Time, validity time.Duration) (*KeyPair, error) {
+	if len(dnsNames) == 0 {
+		return nil, errors.New("at least one DNS name is required")
+	}
+	notAfter := now.Add(validity)
+	if notAfter.After(ca.Certificate.NotAfter) {
+		notAfter = ca.Certificate.NotAfter
+	}
+	return newKeyPair(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:    dnsNames,
+		NotBefore:   now.Add(-clockSkew),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+}
+
+func newKeyPair(template *x509.Certificate, parent *KeyPair) (*KeyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the private key: %w", err)
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the serial number: %w", err)
+	}
+	template.SerialNumber = serialNumber
+	parentCertificate, parentKey := template, key
+	if parent != nil {
+		parentCertificate, parentKey = parent.Certificate, parent.Key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCertificate, key.Public(), parentKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the certificate: %w", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the certificate: %w", err)
+	}
+	return &KeyPair{Certificate: certificate, Key: key}, nil
+}
+
+// CertificatePEM returns the PEM encoding of the certificate.
+func (kp *KeyPair) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: certificatePEMType, Bytes: kp.Certificate.Raw})
+}
+
+// KeyPEM returns the PEM encoding of the private key.
+func (kp *KeyPair) KeyPEM() ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(kp.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: privateKeyPEMType, Bytes: der}), nil
+}
+
+// ParseKeyPair parses the PEM encoded certificate and private key, as stored in the kubernetes.io/tls Secrets.
+func ParseKeyPair(certificatePEM, keyPEM []byte) (*KeyPair, error) {
+	certificates, err := ParseCertificates(certificatePEM)
+	if err != nil {
+		return nil, err
+	}
+	if len(certificates) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil || block.Type != privateKeyPEMType {
+		return nil, errors.New("no private key found")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key: %w", err)
+	}
+	if !key.PublicKey.Equal(certificates[0].PublicKey) {
+		return nil, errors.New("the private key does not match the certificate")
+	}
+	return &KeyPair{Certificate: certificates[0], Key: key}, nil
+}
+
+// ParseCertificates parses the PEM encoded certificates, e.g., a CA bundle.
+func ParseCertificates(certificatesPEM []byte) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+	for block, rest := pem.Decode(certificatesPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != certificatePEMType {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the certificate: %w", err)
+		}
+		certificates = append(certificates, certificate)
+	}
+	return certificates, nil
+}
+
+// NeedsRotation returns true if less than a third of the lifetime of the certificate is left.
+func NeedsRotation(certificate *x509.Certificate, now time.Time) bool {
+	lifetime := certificate.NotAfter.Sub(certificate.NotBefore)
+	return certificate.NotAfter.Sub(now) < lifetime/3
+}
+
+// IsValidServingCertificate returns true if the serving certificate is signed by the CA, covers the DNS names and does
+// not need to be rotated.
+func (kp *KeyPair) IsValidServingCertificate(ca *x509.Certificate, dnsNames []string, now time.Time) bool {
+	if kp.Certificate.CheckSignatureFrom(ca) != nil || NeedsRotation(kp.Certificate, now) {
+		return false
+	}
+	for _, name := range dnsNames {
+		if kp.Certificate.VerifyHostname(name) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// CABundle returns the PEM encoded CA bundle with the given CA first, followed by the certificates of the previous
+// bundle that did not expire yet. Keeping the previous CAs lets the clients trust the serving certificates they
+// signed until those are rotated.
+func CABundle(previous []byte, ca *x509.Certificate, now time.Time) []byte {
+	bundle := bytes.NewBuffer(pem.EncodeToMemory(&pem.Block{Type: certificatePEMType, Bytes: ca.Raw}))
+	// The unparsable previous bundles are dropped: the clients would not trust them anyway.
+	certificates, _ := ParseCertificates(previous)
+	for _, certificate := range certificates {
+		if certificate.Equal(ca) || now.After(certificate.NotAfter) {
+			continue
+		}
+		bundle.Write(pem.EncodeToMemory(&pem.Block{Type: certificatePEMType, Bytes: certificate.Raw}))
+	}
+	return bundle.Bytes()
+}
@@ -0,0 +1,92 @@
+package servingcerts
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestKeyPair_NewServingCertificate(t *testing.T) {
+	g := NewGomegaWithT(t)
+	now := time.Now()
+	ca, err := NewCA("test-ca", now, 24*time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ca.Certificate.IsCA).To(BeTrue())
+
+	dnsNames := []string{"svc.ns.svc", "svc.ns.svc.cluster.local"}
+	certificate, err := ca.NewServingCertificate(dnsNames, now, 48*time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(certificate.Certificate.NotAfter).To(Equal(ca.Certificate.NotAfter), "the certificate should not outlive the CA")
+	g.Expect(certificate.IsValidServingCertificate(ca.Certificate, dnsNames, now)).To(BeTrue())
+	g.Expect(certificate.IsValidServingCertificate(ca.Certificate, []string{"other.ns.svc"}, now)).To(BeFalse())
+	g.Expect(certificate.IsValidServingCertificate(ca.Certificate, dnsNames, now.Add(20*time.Hour))).To(BeFalse())
+
+	otherCA, err := NewCA("other-ca", now, 24*time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(certificate.IsValidServingCertificate(otherCA.Certificate, dnsNames, now)).To(BeFalse())
+
+	_, err = ca.NewServingCertificate(nil, now, time.Hour)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestParseKeyPair(t *testing.T) {
+	g := NewGomegaWithT(t)
+	now := time.Now()
+	ca, err := NewCA("test-ca", now, time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+	keyPEM, err := ca.KeyPEM()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	parsed, err := ParseKeyPair(ca.CertificatePEM(), keyPEM)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(parsed.Certificate.Equal(ca.Certificate)).To(BeTrue())
+
+	otherCA, err := NewCA("other-ca", now, time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = ParseKeyPair(otherCA.CertificatePEM(), keyPEM)
+	g.Expect(err).To(HaveOccurred(), "the private key does not match the certificate")
+	_, err = ParseKeyPair(nil, keyPEM)
+	g.Expect(err).To(HaveOccurred())
+	_, err = ParseKeyPair(ca.CertificatePEM(), []byte("invalid"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestNeedsRotation(t *testing.T) {
+	g := NewGomegaWithT(t)
+	now := time.Now()
+	ca, err := NewCA("test-ca", now, 30*time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+	// The lifetime includes the backdating of the certificate: 30h + 5m.
+	g.Expect(NeedsRotation(ca.Certificate, now)).To(BeFalse())
+	g.Expect(NeedsRotation(ca.Certificate, now.Add(19*time.Hour))).To(BeFalse())
+	g.Expect(NeedsRotation(ca.Certificate, now.Add(21*time.Hour))).To(BeTrue())
+	g.Expect(NeedsRotation(ca.Certificate, now.Add(31*time.Hour))).To(BeTrue())
+}
+
+func TestCABundle(t *testing.T) {
+	g := NewGomegaWithT(t)
+	now := time.Now()
+	expiring, err := NewCA("expiring-ca", now, time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+	previous, err := NewCA("previous-ca", now, 24*time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+	current, err := NewCA("current-ca", now, 48*time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	bundle := CABundle(nil, expiring.Certificate, now)
+	bundle = CABundle(bundle, previous.Certificate, now)
+	certificates, err := ParseCertificates(bundle)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(certificates).To(HaveLen(2))
+
+	bundle = CABundle(bundle, current.Certificate, now.Add(2*time.Hour))
+	certificates, err = ParseCertificates(bundle)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(certificates).To(HaveLen(2), "the expired CA should be dropped")
+	g.Expect(certificates[0].Equal(current.Certificate)).To(BeTrue(), "the current CA should come first")
+	g.Expect(certificates[1].Equal(previous.Certificate)).To(BeTrue())
+
+	g.Expect(CABundle(bundle, current.Certificate, now)).To(Equal(bundle), "the CA bundle should be stable")
+	g.Expect(CABundle([]byte("invalid"), current.Certificate, now)).To(Equal(current.CertificatePEM()))
+}
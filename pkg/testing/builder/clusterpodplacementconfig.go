@@ -1,6 +1,8 @@
 package builder
 
 import (
+	"time"
+
 	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common"
 	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/common/plugins"
 	"github.com/openshift/multiarch-tuning-operator/apis/multiarch/v1beta1"
@@ -32,6 +34,11 @@ func (p *ClusterPodPlacementConfigBuilder) WithLogVerbosity(logVerbosity common.
 	return p
 }
 
+func (p *ClusterPodPlacementConfigBuilder) WithSchedulingGateDeadline(deadline time.Duration) *ClusterPodPlacementConfigBuilder {
+	p.Spec.SchedulingGateDeadline = &v1.Duration{Duration: deadline}
+	return p
+}
+
 func (p *ClusterPodPlacementConfigBuilder) Build() *v1beta1.ClusterPodPlacementConfig {
 	return p.ClusterPodPlacementConfig
 }
@@ -75,3 +82,31 @@ func (p *ClusterPodPlacementConfigBuilder) WithNodeAffinityScoringTerm(architect
 	})
 	return p
 }
+
+func (p *ClusterPodPlacementConfigBuilder) WithNodeInventory(enabled, eligibleNodesOnly, keepGatedUntilNodeAvailable bool) *ClusterPodPlacementConfigBuilder {
+	if p.Spec.Plugins == nil {
+		p.Spec.Plugins = &plugins.Plugins{}
+	}
+	p.Spec.Plugins.NodeInventory = &plugins.NodeInventory{
+		BasePlugin:                  plugins.BasePlugin{Enabled: enabled},
+		EligibleNodesOnly:           eligibleNodesOnly,
+		KeepGatedUntilNodeAvailable: keepGatedUntilNodeAvailable,
+	}
+	return p
+}
+
+func (p *ClusterPodPlacementConfigBuilder) WithArchitectureTolerations(enabled bool, platforms ...plugins.ArchitectureTaintsTerm) *ClusterPodPlacementConfigBuilder {
+	if p.Spec.Plugins == nil {
+		p.Spec.Plugins = &plugins.Plugins{}
+	}
+	p.Spec.Plugins.ArchitectureTolerations = &plugins.ArchitectureTolerations{
+		BasePlugin: plugins.BasePlugin{Enabled: enabled},
+		Platforms:  platforms,
+	}
+	return p
+}
+
+func (p *ClusterPodPlacementConfigBuilder) WithInjectedContainerRules(rules ...v1beta1.InjectedContainerRule) *ClusterPodPlacementConfigBuilder {
+	p.Spec.InjectedContainerRules = rules
+	return p
+}
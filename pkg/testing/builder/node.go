@@ -46,7 +46,19 @@ func (b *NodeBuilder) WithTaint(taint corev1.Taint) *NodeBuilder {
 	return b
 }
 
+// WithUnschedulable marks the Node as unschedulable.
+func (b *NodeBuilder) WithUnschedulable() *NodeBuilder {
+	b.node.Spec.Unschedulable = true
+	return b
+}
+
 // Build finalizes and returns the Node object.
 func (b *NodeBuilder) Build() *corev1.Node {
 	return b.node
 }
+
+// WithAllocatable sets the allocatable resources of the Node.
+func (b *NodeBuilder) WithAllocatable(allocatable corev1.ResourceList) *NodeBuilder {
+	b.node.Status.Allocatable = allocatable
+	return b
+}
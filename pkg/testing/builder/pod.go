@@ -152,6 +152,16 @@ func (p *PodBuilder) WithNodeSelectors(kv ...string) *PodBuilder {
 	return p
 }
 
+func (p *PodBuilder) WithRuntimeClassName(runtimeClassName string) *PodBuilder {
+	p.pod.Spec.RuntimeClassName = &runtimeClassName
+	return p
+}
+
+func (p *PodBuilder) WithTolerations(tolerations ...v1.Toleration) *PodBuilder {
+	p.pod.Spec.Tolerations = append(p.pod.Spec.Tolerations, tolerations...)
+	return p
+}
+
 func (p *PodBuilder) WithOwnerReferences(values ...*metav1.OwnerReference) *PodBuilder {
 	for i := range values {
 		if values[i] == nil {
@@ -211,6 +221,30 @@ func (p *PodBuilder) WithContainerStatuses(statuses ...v1.ContainerStatus) *PodB
 	return p
 }
 
+func (p *PodBuilder) WithCreationTimestamp(creationTimestamp metav1.Time) *PodBuilder {
+	p.pod.CreationTimestamp = creationTimestamp
+	return p
+}
+
 func (p *PodBuilder) Build() *v1.Pod {
 	return p.pod
 }
+
+func (p *PodBuilder) WithTopologySpreadConstraints(constraints ...v1.TopologySpreadConstraint) *PodBuilder {
+	p.pod.Spec.TopologySpreadConstraints = append(p.pod.Spec.TopologySpreadConstraints, constraints...)
+	return p
+}
+
+// WithImageVolume adds to the pod a volume with the given name, mounting the given image reference.
+func (p *PodBuilder) WithImageVolume(name, reference string, pullPolicy v1.PullPolicy) *PodBuilder {
+	p.pod.Spec.Volumes = append(p.pod.Spec.Volumes, v1.Volume{
+		Name: name,
+		VolumeSource: v1.VolumeSource{
+			Image: &v1.ImageVolumeSource{
+				Reference:  reference,
+				PullPolicy: pullPolicy,
+			},
+		},
+	})
+	return p
+}
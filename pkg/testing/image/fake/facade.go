@@ -24,6 +24,18 @@ func (i *Facade) GetCompatibleArchitecturesSet(ctx context.Context, imageReferen
 	return i.inspectionCache.GetCompatibleArchitecturesSet(ctx, imageReference, skipCache, secrets)
 }
 
+// InspectImage returns the architectures of the image reference with its mocked digest, or an error if the image is
+// not in the mock map.
+func (i *Facade) InspectImage(ctx context.Context, imageReference string, skipCache bool,
+	secrets [][]byte) (image.Inspection, error) {
+	architectures, err := i.inspectionCache.GetCompatibleArchitecturesSet(ctx, imageReference, skipCache, secrets)
+	if err != nil {
+		return image.Inspection{}, err
+	}
+	// we expect the imageReference to start with `//`. Let's remove it
+	return image.Inspection{Architectures: architectures, Digest: MockImageDigest(imageReference[2:])}, nil
+}
+
 func newImageFacade() *Facade {
 	inspectionCache := newCacheProxy()
 	return &Facade{
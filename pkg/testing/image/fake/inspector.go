@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/opencontainers/go-digest"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/openshift/multiarch-tuning-operator/pkg/utils"
@@ -33,6 +34,12 @@ func MockImagesArchitectureMap() map[string]sets.Set[string] {
 	}
 }
 
+// MockImageDigest returns the digest of the mocked index or manifest of the image reference. The digest is derived
+// from the image reference so that the tests can predict it.
+func MockImageDigest(imageReference string) digest.Digest {
+	return digest.FromString(imageReference)
+}
+
 func (i *registryInspector) GetCompatibleArchitecturesSet(ctx context.Context, imageReference string,
 	skipCache bool, secrets [][]byte) (supportedArchitectures sets.Set[string], err error) {
 	// we expect the imageReference to start with `//`. Let's remove it
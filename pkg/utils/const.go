@@ -34,9 +34,85 @@ const (
 	SingleArchLabel                 = "multiarch.openshift.io/single-arch"
 	MultiArchLabel                  = "multiarch.openshift.io/multi-arch"
 	NoSupportedArchLabel            = "multiarch.openshift.io/no-supported-arch"
+	NoEligibleNodeArchLabel         = "multiarch.openshift.io/no-eligible-node-arch"
+	NoEligibleNodeArchLabelWaiting  = "waiting"
 	ImageInspectionErrorLabel       = "multiarch.openshift.io/image-inspect-error"
 	ImageInspectionErrorCountLabel  = "multiarch.openshift.io/image-inspect-error-count"
 	LabelGroup                      = "multiarch.openshift.io"
+	// ArchitectureSelectionConflictLabel is set on the pods whose nodeSelector or nodeAffinity select architectures
+	// that are not supported by their images.
+	ArchitectureSelectionConflictLabel = "multiarch.openshift.io/arch-selection-conflict"
+	// NoAllowedArchLabel is set on the pods whose images support none of the allowed architectures. It is also
+	// used as a requirement in their node affinity, to prevent them from being scheduled.
+	NoAllowedArchLabel = "multiarch.openshift.io/no-allowed-arch"
+)
+
+const (
+	// SchedulingGateRemovalReasonAnnotation records why the scheduling gate was removed without the pod being
+	// successfully processed by the pod placement controller.
+	SchedulingGateRemovalReasonAnnotation = "multiarch.openshift.io/scheduling-gate-removal-reason"
+	SchedulingGateDeadlineExceeded        = "SchedulingGateDeadlineExceeded"
+	// PodPlacementControllerUnavailableLabel is set by the webhook on the pods that were not gated because
+	// the pod placement controller was not available at admission time.
+	PodPlacementControllerUnavailableLabel = "multiarch.openshift.io/pod-placement-controller-unavailable"
+	// ConfigGenerationAnnotation records the generation of the ClusterPodPlacementConfig the pod was last gated or
+	// placed with.
+	ConfigGenerationAnnotation = "multiarch.openshift.io/config-generation"
+	// PlacementSpecHashAnnotation records the hash of the placement configuration of the ClusterPodPlacementConfig the
+	// pod was last gated or placed with: the changes of the fields that do not drive the placement of the pods, which
+	// also change the generation, are ignored.
+	PlacementSpecHashAnnotation = "multiarch.openshift.io/placement-spec-hash"
+)
+
+const (
+	// EmulationFallbackNamespaceLabel opts the pods of a namespace in the EmulationFallback plugin, when set to
+	// EmulationFallbackNamespaceLabelEnabled.
+	EmulationFallbackNamespaceLabel        = "multiarch.openshift.io/emulation-fallback"
+	EmulationFallbackNamespaceLabelEnabled = "enabled"
+	// EmulatedLabel is set on the pods sent to the nodes able to emulate the architectures of their images.
+	EmulatedLabel = "multiarch.openshift.io/emulated"
+	// EmulatedArchitecturesAnnotation lists the architectures emulated to run the containers of a pod.
+	EmulatedArchitecturesAnnotation = "multiarch.openshift.io/emulated-architectures"
+)
+
+const (
+	// MultiArchOwnerAnnotation declares, when set to "true" on the controller owner of a pod (e.g., a ReplicaSet,
+	// inheriting it from its Deployment), that its pods run on more than one architecture.
+	MultiArchOwnerAnnotation = "multiarch.openshift.io/multi-arch"
+)
+
+const (
+	// ContainerDecisionsAnnotation records, as a JSON object keyed by container name, how the containers of a pod
+	// are considered by the pod placement operand according to the injected container rules.
+	ContainerDecisionsAnnotation = "multiarch.openshift.io/container-decisions"
+	// ContainerDecisionInspected is the decision for the containers matched by no rule.
+	ContainerDecisionInspected = "Inspected"
+	// ContainerDecisionIncluded is the decision for the containers matched by an Include rule.
+	ContainerDecisionIncluded = "Included"
+	// ContainerDecisionAssumedMultiArch is the decision for the containers matched by an AssumeMultiArch rule.
+	ContainerDecisionAssumedMultiArch = "AssumedMultiArch"
+)
+
+const (
+	// ValidatingPolicyNamespaceLabel sets the validating policy enforced in a namespace, overriding the one of the
+	// ClusterPodPlacementConfig. ValidatingPolicyNone disables the policy in the namespace.
+	ValidatingPolicyNamespaceLabel = "multiarch.openshift.io/validating-policy"
+	ValidatingPolicyNone           = "None"
+)
+
+const (
+	// ArchitectureAgnosticVolumesAnnotation lists, comma-separated, the image volumes of a pod whose images are
+	// not tied to an architecture (e.g., data-only artifacts) and must not be inspected.
+	ArchitectureAgnosticVolumesAnnotation = "multiarch.openshift.io/architecture-agnostic-volumes"
+)
+
+const (
+	// OriginalImagesAnnotation records, as a JSON object keyed by container name, the images of the containers of a
+	// pod before they were rewritten by the pin-digest mode or the ImageRewriteRules.
+	OriginalImagesAnnotation = "multiarch.openshift.io/original-images"
+	// ImageRewriteArchitectureAnnotation records the architecture whose variants the images of a pod were rewritten to
+	// according to the ImageRewriteRules.
+	ImageRewriteArchitectureAnnotation = "multiarch.openshift.io/image-rewrite-architecture"
 )
 
 const (
@@ -47,10 +123,20 @@ const (
 )
 
 const (
-	PodMutatingWebhookConfigurationName = "pod-placement-mutating-webhook-configuration"
-	PodMutatingWebhookName              = "pod-placement-scheduling-gate.multiarch.openshift.io"
-	PodPlacementControllerName          = "pod-placement-controller"
-	PodPlacementWebhookName             = "pod-placement-web-hook"
+	PodMutatingWebhookConfigurationName   = "pod-placement-mutating-webhook-configuration"
+	PodMutatingWebhookName                = "pod-placement-scheduling-gate.multiarch.openshift.io"
+	PodValidatingWebhookConfigurationName = "pod-placement-validating-webhook-configuration"
+	PodValidatingWebhookName              = "pod-placement-architecture-policy.multiarch.openshift.io"
+	PodValidatingWebhookPath              = "/validate-pod-architecture-policy"
+	PodPlacementControllerName            = "pod-placement-controller"
+	PodPlacementWebhookName               = "pod-placement-web-hook"
+)
+
+const (
+	// LeaderElectionID is the base of the leader election IDs used by the different binary modes.
+	LeaderElectionID = "208d7abd.multiarch.openshift.io"
+	// PodPlacementControllerLeaderElectionID is the name of the Lease held by the leader of the pod placement controllers.
+	PodPlacementControllerLeaderElectionID = "ppc-controllers-" + LeaderElectionID
 )
 
 const (
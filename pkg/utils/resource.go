@@ -6,16 +6,23 @@ import (
 
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	autoscalingclientv2 "k8s.io/client-go/kubernetes/typed/autoscaling/v2"
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	networkingclientv1 "k8s.io/client-go/kubernetes/typed/networking/v1"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
@@ -74,6 +81,15 @@ func ApplyResource(ctx context.Context, clientSet *kubernetes.Clientset, client
 	case *admissionv1.MutatingWebhookConfiguration:
 		return resourceapply.ApplyMutatingWebhookConfigurationImproved(ctx, clientSet.AdmissionregistrationV1(),
 			recorder, t, resourceCache)
+	case *admissionv1.ValidatingWebhookConfiguration:
+		return resourceapply.ApplyValidatingWebhookConfigurationImproved(ctx, clientSet.AdmissionregistrationV1(),
+			recorder, t, resourceCache)
+	case *policyv1.PodDisruptionBudget:
+		return resourceapply.ApplyPodDisruptionBudget(ctx, clientSet.PolicyV1(), recorder, t)
+	case *autoscalingv2.HorizontalPodAutoscaler:
+		return applyHorizontalPodAutoscaler(ctx, clientSet.AutoscalingV2(), recorder, t)
+	case *networkingv1.NetworkPolicy:
+		return applyNetworkPolicy(ctx, clientSet.NetworkingV1(), recorder, t)
 	case *rbacv1.Role:
 		return resourceapply.ApplyRole(ctx, clientSet.RbacV1(), recorder, t)
 	case *rbacv1.RoleBinding:
@@ -100,6 +116,10 @@ func ApplyResource(ctx context.Context, clientSet *kubernetes.Clientset, client
 		return resourceapply.ApplyPrometheusRule(ctx, client, recorder, &unstructured.Unstructured{
 			Object: objMap,
 		})
+	case *unstructured.Unstructured:
+		// The resources of the unstructured objects are guessed from their kinds, e.g., cert-manager.io/v1 Certificates.
+		gvr, _ := meta.UnsafeGuessKindToResource(t.GroupVersionKind())
+		return resourceapply.ApplyUnstructuredResourceImproved(ctx, client, recorder, t, resourceCache, gvr, nil, nil)
 	default:
 		return nil, false, fmt.Errorf("unhandled type %T", obj)
 	}
@@ -183,6 +203,8 @@ func applyService(ctx context.Context, client v1.ServicesGetter, recorder events
 	}
 	existingCopy := existing.DeepCopy()
 	existingCopy.Spec = required.Spec
+	// The annotations are merged so that the ones depending on the TLS mode of the operand are added and removed.
+	resourcemerge.EnsureObjectMeta(NewPtr(false), &existingCopy.ObjectMeta, required.ObjectMeta)
 
 	// the following method is not exported by the resourceapply package and is flattened in the next rows
 	// reportUpdateEvent(recorder, required, err)
@@ -198,3 +220,68 @@ func applyService(ctx context.Context, client v1.ServicesGetter, recorder events
 	// end flattened method reportUpdateEvent
 	return actual, true, err
 }
+
+// applyHorizontalPodAutoscaler follows the ApplyPodDisruptionBudget method of the library-go resourceapply package, that
+// does not handle the HorizontalPodAutoscalers. The scaling behavior defaulted by the api server is kept when the
+// required object does not set it, so that the object is not updated at every reconciliation.
+// TODO[integration-tests]: integration tests for this function in a suite dedicated to this package
+func applyHorizontalPodAutoscaler(ctx context.Context, client autoscalingclientv2.HorizontalPodAutoscalersGetter,
+	recorder events.Recorder, required *autoscalingv2.HorizontalPodAutoscaler) (*autoscalingv2.HorizontalPodAutoscaler, bool, error) {
+	existing, err := client.HorizontalPodAutoscalers(required.Namespace).Get(ctx, required.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		requiredCopy := required.DeepCopy()
+		actual, err := client.HorizontalPodAutoscalers(required.Namespace).Create(ctx,
+			resourcemerge.WithCleanLabelsAndAnnotations(requiredCopy).(*autoscalingv2.HorizontalPodAutoscaler),
+			metav1.CreateOptions{})
+		resourcehelper.ReportCreateEvent(recorder, required, err)
+		return actual, true, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	modified := false
+	existingCopy := existing.DeepCopy()
+	resourcemerge.EnsureObjectMeta(&modified, &existingCopy.ObjectMeta, required.ObjectMeta)
+	requiredSpec := required.Spec.DeepCopy()
+	if requiredSpec.Behavior == nil {
+		requiredSpec.Behavior = existingCopy.Spec.Behavior
+	}
+	if equality.Semantic.DeepEqual(existingCopy.Spec, *requiredSpec) && !modified {
+		return existingCopy, false, nil
+	}
+	existingCopy.Spec = *requiredSpec
+	actual, err := client.HorizontalPodAutoscalers(required.Namespace).Update(ctx, existingCopy, metav1.UpdateOptions{})
+	resourcehelper.ReportUpdateEvent(recorder, required, err)
+	return actual, true, err
+}
+
+// applyNetworkPolicy follows the ApplyPodDisruptionBudget method of the library-go resourceapply package, that does not
+// handle the NetworkPolicies.
+// TODO[integration-tests]: integration tests for this function in a suite dedicated to this package
+func applyNetworkPolicy(ctx context.Context, client networkingclientv1.NetworkPoliciesGetter,
+	recorder events.Recorder, required *networkingv1.NetworkPolicy) (*networkingv1.NetworkPolicy, bool, error) {
+	existing, err := client.NetworkPolicies(required.Namespace).Get(ctx, required.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		requiredCopy := required.DeepCopy()
+		actual, err := client.NetworkPolicies(required.Namespace).Create(ctx,
+			resourcemerge.WithCleanLabelsAndAnnotations(requiredCopy).(*networkingv1.NetworkPolicy),
+			metav1.CreateOptions{})
+		resourcehelper.ReportCreateEvent(recorder, required, err)
+		return actual, true, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	modified := false
+	existingCopy := existing.DeepCopy()
+	resourcemerge.EnsureObjectMeta(&modified, &existingCopy.ObjectMeta, required.ObjectMeta)
+	if equality.Semantic.DeepEqual(existingCopy.Spec, required.Spec) && !modified {
+		return existingCopy, false, nil
+	}
+	existingCopy.Spec = *required.Spec.DeepCopy()
+	actual, err := client.NetworkPolicies(required.Namespace).Update(ctx, existingCopy, metav1.UpdateOptions{})
+	resourcehelper.ReportUpdateEvent(recorder, required, err)
+	return actual, true, err
+}
@@ -3,10 +3,13 @@ package utils
 import (
 	"context"
 	"os"
+	"slices"
 	"sync"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 
 	"go.uber.org/zap"
@@ -55,3 +58,27 @@ func IsResourceAvailable(ctx context.Context, client *dynamic.DynamicClient, res
 	availableResourcesMap[resource] = err == nil
 	return availableResourcesMap[resource]
 }
+
+// IsOpenShift returns true if the cluster serves the config.openshift.io/v1 ClusterVersion API. Only the definitive
+// results of the discovery are cached: an error is returned, and the result is not cached, when the discovery fails
+// for reasons other than the API not being served.
+func IsOpenShift(client discovery.DiscoveryInterface) (bool, error) {
+	resource := schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusterversions"}
+	rwMutex.RLock()
+	if v, ok := availableResourcesMap[resource]; ok {
+		rwMutex.RUnlock()
+		return v, nil
+	}
+	rwMutex.RUnlock()
+	resources, err := client.ServerResourcesForGroupVersion(resource.GroupVersion().String())
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	available := err == nil && slices.ContainsFunc(resources.APIResources, func(r metav1.APIResource) bool {
+		return r.Name == resource.Resource
+	})
+	rwMutex.Lock()
+	defer rwMutex.Unlock()
+	availableResourcesMap[resource] = available
+	return available, nil
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestIsOpenShift(t *testing.T) {
+	clusterVersions := schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusterversions"}
+	openShiftResources := []*metav1.APIResourceList{
+		{
+			GroupVersion: clusterVersions.GroupVersion().String(),
+			APIResources: []metav1.APIResource{{Name: clusterVersions.Resource}},
+		},
+	}
+	tests := []struct {
+		name       string
+		resources  []*metav1.APIResourceList
+		err        error
+		want       bool
+		wantErr    bool
+		wantCached bool
+	}{
+		{
+			name:       "openshift",
+			resources:  openShiftResources,
+			want:       true,
+			wantCached: true,
+		},
+		{
+			name:       "api not served",
+			want:       false,
+			wantCached: true,
+		},
+		{
+			name:       "discovery failure",
+			resources:  openShiftResources,
+			err:        errors.New("connection refused"),
+			wantErr:    true,
+			wantCached: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			rwMutex.Lock()
+			delete(availableResourcesMap, clusterVersions)
+			rwMutex.Unlock()
+			client := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: tt.resources}}
+			if tt.err != nil {
+				client.AddReactor("get", "resource", func(clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.err
+				})
+			}
+			got, err := IsOpenShift(client)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(got).To(Equal(tt.want))
+			rwMutex.RLock()
+			_, cached := availableResourcesMap[clusterVersions]
+			rwMutex.RUnlock()
+			g.Expect(cached).To(Equal(tt.wantCached))
+		})
+	}
+}
@@ -16,6 +16,11 @@ func ArchLabelValue(arch string) string {
 	return path.Join(LabelGroup, arch)
 }
 
+// EmulatesArchLabel returns the label of the nodes able to emulate the given architecture.
+func EmulatesArchLabel(arch string) string {
+	return path.Join(LabelGroup, "emulates-"+arch)
+}
+
 func HistogramObserve(initialTime time.Time, histogram prometheus.Histogram) {
 	histogram.Observe(time.Since(initialTime).Seconds())
 }